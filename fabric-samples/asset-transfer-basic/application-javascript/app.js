@@ -88,8 +88,10 @@ async function main() {
 		await enrollAdmin(caClient, wallet, mspOrg3);
 
 		// in a real application this would be done only when a new user was required to be added
-		// and would be part of an administrative flow
-		await registerAndEnrollUser(caClient, wallet, mspOrg3, org3UserId, 'org3.department1');
+		// and would be part of an administrative flow. The demo user initializes the ledger, which
+		// only admins may do, so its certificate carries the admin attribute.
+		await registerAndEnrollUser(caClient, wallet, mspOrg3, org3UserId, 'org3.department1',
+			[{ name: 'admin', value: 'true', ecert: true }]);
 
 		// Create a new gateway instance for interacting with the fabric network.
 		// In a real application this would be done as the backend server session is setup for
@@ -110,8 +112,11 @@ async function main() {
 			// Build a network instance based on the channel where the smart contract is deployed
 			const network = await gateway.getNetwork(channelName);
 
-			// Get the contract from the network.
+			// Get the contracts from the network. Asset transactions are served by the default contract,
+			// user transactions by UserContract and repair transactions by RepairContract.
 			const contract = network.getContract(chaincodeName);
+			const userContract = network.getContract(chaincodeName, 'UserContract');
+			const repairContract = network.getContract(chaincodeName, 'RepairContract');
 
 			// Initialize a set of asset data on the channel using the chaincode 'InitLedger' function.
 			// This type of transaction would only be run once by an application the first time it was started after it
@@ -128,7 +133,7 @@ async function main() {
 			console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 
 			console.log('\n--> Evaluate Transaction: GetAllUsers, function returns all the current users on the ledger');
-			result = await contract.evaluateTransaction('GetAllUsers');
+			result = await userContract.evaluateTransaction('GetAllUsers');
 			console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 
 			// Now let's try to submit a transaction.
@@ -136,19 +141,19 @@ async function main() {
 			// to the orderer to be committed by each of the peer's to the channel ledger.
 			// TODO
 			console.log('\n--> Submit Transaction: CreateUser, creates new asset with ID, name, lastname, email, money arguments');
-			result = await contract.submitTransaction('CreateUser', 'user4', 'Milan', 'Milanovic', 'milan.milanovic@email.com', '5600');
+			result = await userContract.submitTransaction('CreateUser', 'user4', 'Milan', 'Milanovic', 'milan.milanovic@email.com', '560000');
 			console.log('*** Result: committed');
 			if (`${result}` !== '') {
 				console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 			}
 
 			console.log('\n--> Evaluate Transaction: ReadUser, function returns an asset with a given assetID');
-			result = await contract.evaluateTransaction('ReadUser', 'user4');
+			result = await userContract.evaluateTransaction('ReadUser', 'user4');
 		    //console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 			console.log(`*** Result: ${result.toString()}`);
 
-			console.log('\n--> Submit Transaction: CreateAsset, creates new asset with ID, brand, model, year, color, owner, appraisedValue arguments');
-			result = await contract.submitTransaction('CreateAsset', 'asset7', 'Mercedes', 'C', '2000', 'blue', 'user4', '4500');
+			console.log('\n--> Submit Transaction: CreateAsset, creates new asset with ID, VIN, brand, model, year, color, owner, appraisedValue arguments');
+			result = await contract.submitTransaction('CreateAsset', 'asset7', 'WDD20500X0R123456', 'Mercedes', 'C', '2000', 'blue', 'user4', '450000');
 			console.log('*** Result: committed');
 			if (`${result}` !== '') {
 				console.log(`*** Result: ${prettyJSONString(result.toString())}`);
@@ -176,7 +181,7 @@ async function main() {
 			console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 
 			console.log('\n--> Submit Transaction: TransferAsset asset6, transfer to new owner of user1');
-			await contract.submitTransaction('TransferAsset', 'asset6', 'user1', 'false');
+			await contract.submitTransaction('TransferAsset', 'asset6', 'user1', 'false', '630000');
 			console.log('*** Result: committed');
 
 			console.log('\n--> Evaluate Transaction: ReadAsset, function returns "asset6" attributes');
@@ -184,7 +189,7 @@ async function main() {
 			console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 
 			console.log('\n--> Submit Transaction: CreateAssetDamage for asset5');
-			await contract.submitTransaction('CreateAssetDamage', 'asset5', 'Probusena desna prednja guma', '3400.00');
+			await contract.submitTransaction('CreateAssetDamage', 'asset5', 'Probusena desna prednja guma', '340000');
 			console.log('*** Result: committed');
 
 			console.log('\n--> Evaluate Transaction: ReadAsset, function returns "asset5" attributes');
@@ -195,14 +200,33 @@ async function main() {
 				// How about we try a transactions where the executing chaincode throws an error
 				// Notice how the submitTransaction will throw an error containing the error thrown by the chaincode
 				console.log('\n--> Submit Transaction: TransferAsset asset6, transfer to new owner of user1. Asset6 has damage and should return an error');
-				await contract.submitTransaction('TransferAsset', 'asset5', 'user2', 'false');
+				await contract.submitTransaction('TransferAsset', 'asset5', 'user2', 'false', '460000');
 				console.log('*** Result: committed');
 			} catch (error) {
 				console.log(`*** Successfully caught the error: \n    ${error}`);
 			}
 
-			console.log('\n--> Submit Transaction: RepairDamages for asset5');
-			await contract.submitTransaction('RepairDamages', 'asset5', 'user3');
+			// The owner asks for a repair, the mechanic quotes a price and, once the owner accepts it,
+			// does the repair and gets paid by the owner.
+			console.log('\n--> Submit Transaction: RequestRepair for the damages of asset5');
+			const jobID = (await repairContract.submitTransaction('RequestRepair', 'asset5', '')).toString();
+			console.log(`*** Result: committed, repair job ${jobID}`);
+
+			console.log('\n--> Submit Transaction: SubmitRepairQuote, mechanic user3 offers to repair asset5');
+			const quoteID = (await repairContract.submitTransaction('SubmitRepairQuote', jobID, 'user3', '340000')).toString();
+			console.log(`*** Result: committed, quote ${quoteID}`);
+
+			console.log('\n--> Submit Transaction: AcceptRepairQuote, the owner of asset5 accepts the quote');
+			await repairContract.submitTransaction('AcceptRepairQuote', jobID, quoteID);
+			console.log('*** Result: committed');
+
+			console.log('\n--> Submit Transaction: StartRepair and CompleteRepair, the mechanic repairs asset5');
+			await repairContract.submitTransaction('StartRepair', jobID);
+			await repairContract.submitTransaction('CompleteRepair', jobID);
+			console.log('*** Result: committed');
+
+			console.log('\n--> Submit Transaction: PayRepair, the owner of asset5 pays the mechanic');
+			await repairContract.submitTransaction('PayRepair', jobID);
 			console.log('*** Result: committed');
 
 			console.log('\n--> Evaluate Transaction: ReadAsset, function returns "asset5" attributes');
@@ -210,7 +234,7 @@ async function main() {
 			console.log(`*** Result: ${prettyJSONString(result.toString())}`);
 
 			console.log('\n--> Submit Transaction: TransferAsset asset5, transfer to new owner of user1.');
-			await contract.submitTransaction('TransferAsset', 'asset5', 'user2', 'false');
+			await contract.submitTransaction('TransferAsset', 'asset5', 'user2', 'false', '460000');
 			console.log('*** Result: committed');
 
 			console.log('\n--> Evaluate Transaction: FindAssets, function returns assets with color black');
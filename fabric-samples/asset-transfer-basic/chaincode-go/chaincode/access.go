@@ -0,0 +1,57 @@
+package chaincode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Attributes of the client certificates checked by the chaincode
+const (
+	adminAttribute = "admin"
+	roleAttribute  = "role"
+)
+
+// attribute is a name and value an enrollment certificate may carry
+type attribute struct {
+	name  string
+	value string
+}
+
+// attr returns the certificate attribute name with given value
+func attr(name string, value string) attribute {
+	return attribute{name: name, value: value}
+}
+
+func (a attribute) String() string {
+	return a.name + "=" + a.value
+}
+
+// hasAttr reports whether the certificate of the submitting client carries the attribute
+func hasAttr(ctx contractapi.TransactionContextInterface, a attribute) bool {
+	return ctx.GetClientIdentity().AssertAttributeValue(a.name, a.value) == nil
+}
+
+// requireAttr returns an error unless the certificate of the submitting client carries attribute name with given value
+func requireAttr(ctx contractapi.TransactionContextInterface, name string, value string) error {
+	return requireOneOf(ctx, attr(name, value))
+}
+
+// requireOneOf returns an error unless the certificate of the submitting client carries at least one of the attributes
+func requireOneOf(ctx contractapi.TransactionContextInterface, attrs ...attribute) error {
+	names := make([]string, len(attrs))
+	for i, a := range attrs {
+		if hasAttr(ctx, a) {
+			return nil
+		}
+		names[i] = a.String()
+	}
+
+	return fmt.Errorf("submitting client does not have attribute %s", strings.Join(names, " or "))
+}
+
+// requireAdmin returns an error unless the submitting client carries the admin=true attribute
+func requireAdmin(ctx contractapi.TransactionContextInterface) error {
+	return requireAttr(ctx, adminAttribute, "true")
+}
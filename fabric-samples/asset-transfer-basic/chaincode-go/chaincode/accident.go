@@ -0,0 +1,164 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	accidentObjectType = "accident"
+	assetAccidentIndex = "asset~accident"
+)
+
+// FileAccidentReport records the accident described by reportJSON, giving the involved assets,
+// drivers, location, time and the damages it caused. Only a driver or the owner of an involved
+// asset may file it. It returns the ID of the new report.
+func (s *SmartContract) FileAccidentReport(ctx contractapi.TransactionContextInterface, reportJSON string) (string, error) {
+	var report AccidentReport
+	err := json.Unmarshal([]byte(reportJSON), &report)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal accident report: %v", err)
+	}
+	if len(report.AssetIDs) == 0 {
+		return "", fmt.Errorf("an accident report must involve at least one asset")
+	}
+	if report.Location == "" {
+		return "", fmt.Errorf("an accident report must give a location")
+	}
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	involved := false
+	assets := map[string]*Asset{}
+	for _, assetID := range report.AssetIDs {
+		asset, err := s.ReadAsset(ctx, assetID)
+		if err != nil {
+			return "", err
+		}
+		assets[assetID] = asset
+		owner, err := s.ReadUser(ctx, asset.OwnerID)
+		if err != nil {
+			return "", err
+		}
+		involved = involved || owner.Identity == clientID
+	}
+	for _, driverID := range report.DriverIDs {
+		driver, err := s.ReadUser(ctx, driverID)
+		if err != nil {
+			return "", err
+		}
+		involved = involved || driver.Identity == clientID
+	}
+	if !involved {
+		return "", fmt.Errorf("only a driver or an owner of an involved asset can file an accident report")
+	}
+
+	report.ID = ctx.GetStub().GetTxID()
+	report.ReporterID = clientID
+	report.FiledAt, err = txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, ref := range report.Damages {
+		if _, ok := assets[ref.AssetID]; !ok {
+			return "", fmt.Errorf("the damaged asset %s is not involved in the accident", ref.AssetID)
+		}
+		damage, err := s.ReadDamage(ctx, ref.AssetID, ref.DamageID)
+		if err != nil {
+			return "", err
+		}
+		damage.AccidentID = report.ID
+		err = putDamage(ctx, damage)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = putAccidentReport(ctx, &report)
+	if err != nil {
+		return "", err
+	}
+
+	return report.ID, nil
+}
+
+// ReadAccidentReport returns the accident report stored in the world state with given id.
+func (s *SmartContract) ReadAccidentReport(ctx contractapi.TransactionContextInterface, reportID string) (*AccidentReport, error) {
+	reportKey, err := ctx.GetStub().CreateCompositeKey(accidentObjectType, []string{reportID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	reportJSON, err := ctx.GetStub().GetState(reportKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if reportJSON == nil {
+		return nil, fmt.Errorf("the accident report %s does not exist", reportID)
+	}
+
+	var report AccidentReport
+	err = json.Unmarshal(reportJSON, &report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &report, nil
+}
+
+// GetAccidentReports returns all accident reports involving asset with given ID
+func (s *SmartContract) GetAccidentReports(ctx contractapi.TransactionContextInterface, assetID string) ([]*AccidentReport, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(assetAccidentIndex, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var reports []*AccidentReport
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := s.ReadAccidentReport(ctx, string(queryResponse.Value))
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// putAccidentReport writes the given accident report and indexes it under every involved asset
+func putAccidentReport(ctx contractapi.TransactionContextInterface, report *AccidentReport) error {
+	reportKey, err := ctx.GetStub().CreateCompositeKey(accidentObjectType, []string{report.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(reportKey, reportJSON)
+	if err != nil {
+		return err
+	}
+
+	for _, assetID := range report.AssetIDs {
+		indexKey, err := ctx.GetStub().CreateCompositeKey(assetAccidentIndex, []string{assetID, report.ID})
+		if err != nil {
+			return fmt.Errorf("failed to create composite key: %v", err)
+		}
+		err = ctx.GetStub().PutState(indexKey, []byte(report.ID))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileAccidentReport(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	damage := chaincode.Damage{ID: "damage1", AssetID: "asset1", Cost: 400, Status: chaincode.DamageOpen}
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "owner2"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{damage}})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user2"})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage1\x00", &damage)
+
+	report := `{"assetIDs":["asset1","asset2"],"driverIDs":["user1","user2"],"location":"Novi Sad","occurredAt":"2020-09-12T10:00:00Z","damages":[{"assetID":"asset1","damageID":"damage1"}]}`
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("someone", nil)
+	_, err := assetTransfer.FileAccidentReport(transactionContext, report)
+	require.EqualError(t, err, "only a driver or an owner of an involved asset can file an accident report")
+
+	clientIdentity.GetIDReturns("owner2", nil)
+	chaincodeStub.GetTxIDReturns("accident1")
+	reportID, err := assetTransfer.FileAccidentReport(transactionContext, report)
+	require.NoError(t, err)
+
+	filed, err := assetTransfer.ReadAccidentReport(transactionContext, reportID)
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.AccidentReport{
+		ID:         "accident1",
+		AssetIDs:   []string{"asset1", "asset2"},
+		DriverIDs:  []string{"user1", "user2"},
+		Location:   "Novi Sad",
+		OccurredAt: time.Date(2020, 9, 12, 10, 0, 0, 0, time.UTC),
+		Damages:    []chaincode.DamageRef{{AssetID: "asset1", DamageID: "damage1"}},
+		ReporterID: "owner2",
+		FiledAt:    time.Unix(1600000000, 0).UTC(),
+	}, filed)
+	require.Equal(t, []byte("accident1"), state["\x00asset~accident\x00asset2\x00accident1\x00"])
+	linked, err := assetTransfer.ReadDamage(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, "accident1", linked.AccidentID)
+
+	_, err = assetTransfer.FileAccidentReport(transactionContext, `{"assetIDs":["asset2"],"location":"Novi Sad","damages":[{"assetID":"asset1","damageID":"damage1"}]}`)
+	require.EqualError(t, err, "the damaged asset asset1 is not involved in the accident")
+}
+
+func TestGetAccidentReports(t *testing.T) {
+	state := worldState{}
+	state.put(t, "\x00accident\x00accident1\x00", &chaincode.AccidentReport{ID: "accident1", AssetIDs: []string{"asset1"}, Location: "Novi Sad"})
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Key: "\x00asset~accident\x00asset1\x00accident1\x00", Value: []byte("accident1")}, nil)
+
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(iterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	reports, err := assetTransfer.GetAccidentReports(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.AccidentReport{{ID: "accident1", AssetIDs: []string{"asset1"}, Location: "Novi Sad"}}, reports)
+}
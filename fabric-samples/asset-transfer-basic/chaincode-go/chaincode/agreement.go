@@ -0,0 +1,236 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	agreementObjectType   = "agreement"
+	agreementTransientKey = "price_agreement"
+)
+
+// AgreeToSell lets the owner of the asset record the price they agreed to sell it to the buyer for.
+// The PriceAgreement is passed as JSON in the price_agreement transient field and stored in the
+// private data collection of the seller's organization, so the price never becomes public.
+func (s *SmartContract) AgreeToSell(ctx contractapi.TransactionContextInterface, assetID string, buyerID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.OwnerID == buyerID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return err
+	}
+
+	return recordPriceAgreement(ctx, assetID, buyerID, seller)
+}
+
+// AgreeToBuy lets the buyer record the price they agreed to buy the asset for. The PriceAgreement
+// is passed as JSON in the price_agreement transient field and stored in the private data
+// collection of the buyer's organization.
+func (s *SmartContract) AgreeToBuy(ctx contractapi.TransactionContextInterface, assetID string, buyerID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.OwnerID == buyerID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	return recordPriceAgreement(ctx, assetID, buyerID, buyer)
+}
+
+// SettleAgreedSale sells the asset to the buyer at the price agreed with AgreeToSell and AgreeToBuy.
+// Either side passes the PriceAgreement in the price_agreement transient field; it must hash the same
+// as the agreements in the seller's and in the buyer's organization collections. Both agreements are
+// deleted once the sale settles, or is filed for registration, so they cannot be used again.
+func (s *SmartContract) SettleAgreedSale(ctx contractapi.TransactionContextInterface, assetID string, buyerID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, seller) != nil {
+		err = verifyUserIdentity(ctx, buyer)
+		if err != nil {
+			return err
+		}
+	}
+	agreementJSON, err := transientPriceAgreement(ctx)
+	if err != nil {
+		return err
+	}
+	agreement, err := parsePriceAgreement(agreementJSON)
+	if err != nil {
+		return err
+	}
+	if agreement.AssetID != assetID || agreement.BuyerID != buyerID {
+		return fmt.Errorf("the agreement is not for the sale of asset %s to user %s", assetID, buyerID)
+	}
+	hash := sha256.Sum256(agreementJSON)
+	for _, party := range []*User{seller, buyer} {
+		err = verifyPriceAgreement(ctx, assetID, buyerID, party, hash[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = settleOrRegister(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, price: agreement.Price, upfront: agreement.Price})
+	if err != nil {
+		return err
+	}
+	for _, party := range []*User{seller, buyer} {
+		err = deletePriceAgreement(ctx, assetID, buyerID, party)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// recordPriceAgreement stores the price agreement passed in the transient data in the collection of the party's organization
+func recordPriceAgreement(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, party *User) error {
+	agreementJSON, err := transientPriceAgreement(ctx)
+	if err != nil {
+		return err
+	}
+	agreement, err := parsePriceAgreement(agreementJSON)
+	if err != nil {
+		return err
+	}
+	if agreement.AssetID != assetID || agreement.BuyerID != buyerID {
+		return fmt.Errorf("the agreement is not for the sale of asset %s to user %s", assetID, buyerID)
+	}
+	collection, err := userOrgCollection(party)
+	if err != nil {
+		return err
+	}
+	agreementKey, err := agreementKey(ctx, assetID, buyerID, party.ID)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().PutPrivateData(collection, agreementKey, agreementJSON)
+	if err != nil {
+		return fmt.Errorf("failed to put price agreement to private data collection %s: %v", collection, err)
+	}
+
+	return nil
+}
+
+// deletePriceAgreement removes the price agreement of the party from their organization's collection
+func deletePriceAgreement(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, party *User) error {
+	collection, err := userOrgCollection(party)
+	if err != nil {
+		return err
+	}
+	agreementKey, err := agreementKey(ctx, assetID, buyerID, party.ID)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().DelPrivateData(collection, agreementKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete from private data collection %s: %v", collection, err)
+	}
+
+	return nil
+}
+
+// verifyPriceAgreement checks that the party stored an agreement with given hash in their organization's collection
+func verifyPriceAgreement(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, party *User, hash []byte) error {
+	collection, err := userOrgCollection(party)
+	if err != nil {
+		return err
+	}
+	agreementKey, err := agreementKey(ctx, assetID, buyerID, party.ID)
+	if err != nil {
+		return err
+	}
+	storedHash, err := ctx.GetStub().GetPrivateDataHash(collection, agreementKey)
+	if err != nil {
+		return fmt.Errorf("failed to read private data hash from collection %s: %v", collection, err)
+	}
+	if storedHash == nil {
+		return fmt.Errorf("user %s has not agreed on a price for asset %s", party.ID, assetID)
+	}
+	if string(storedHash) != string(hash) {
+		return fmt.Errorf("the price agreed by user %s does not match", party.ID)
+	}
+
+	return nil
+}
+
+// userOrgCollection returns the implicit private data collection of the organization the user belongs to
+func userOrgCollection(user *User) (string, error) {
+	if user.MSPID == "" {
+		return "", fmt.Errorf("the user %s is not bound to an organization", user.ID)
+	}
+
+	return orgCollection(user.MSPID), nil
+}
+
+// agreementKey returns the private data key of the price agreement of party on the sale of asset to buyer
+func agreementKey(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, partyID string) (string, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(agreementObjectType, []string{assetID, buyerID, partyID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return key, nil
+}
+
+// transientPriceAgreement returns the price agreement passed in the price_agreement transient field
+func transientPriceAgreement(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+	agreementJSON, ok := transientMap[agreementTransientKey]
+	if !ok {
+		return nil, fmt.Errorf("the price agreement must be passed in the %s transient field", agreementTransientKey)
+	}
+
+	return agreementJSON, nil
+}
+
+// parsePriceAgreement decodes and validates the price agreement JSON
+func parsePriceAgreement(agreementJSON []byte) (*PriceAgreement, error) {
+	var agreement PriceAgreement
+	err := json.Unmarshal(agreementJSON, &agreement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal price agreement: %v", err)
+	}
+	if agreement.Price < 0 {
+		return nil, fmt.Errorf("price must not be negative")
+	}
+
+	return &agreement, nil
+}
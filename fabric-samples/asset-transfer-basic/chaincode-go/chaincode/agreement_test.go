@@ -0,0 +1,61 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivatePriceAgreement(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	enrollClients(clientIdentity, map[string]string{"seller": "Org1MSP", "buyer": "Org2MSP"})
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller", MSPID: "Org1MSP"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer", MSPID: "Org2MSP", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	agreement := []byte(`{"assetID":"asset1","buyerID":"user2","price":3000,"salt":"beef"}`)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"price_agreement": agreement}, nil)
+	clientIdentity.GetIDReturns("seller", nil)
+	err := assetTransfer.AgreeToSell(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	require.Equal(t, agreement, state["_implicit_org_Org1MSP/\x00agreement\x00asset1\x00user2\x00user1\x00"])
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.SettleAgreedSale(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "user user2 has not agreed on a price for asset asset1")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"price_agreement": []byte(`{"assetID":"asset1","buyerID":"user2","price":1000,"salt":"beef"}`)}, nil)
+	err = assetTransfer.AgreeToBuy(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"price_agreement": agreement}, nil)
+	err = assetTransfer.SettleAgreedSale(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "the price agreed by user user2 does not match")
+
+	err = assetTransfer.AgreeToBuy(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	err = assetTransfer.SettleAgreedSale(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(2000), user.Money)
+
+	require.NotContains(t, state, "_implicit_org_Org1MSP/\x00agreement\x00asset1\x00user2\x00user1\x00")
+	require.NotContains(t, state, "_implicit_org_Org2MSP/\x00agreement\x00asset1\x00user2\x00user2\x00")
+
+	// only the owner may agree to sell
+	clientIdentity.GetIDReturns("stranger", nil)
+	err = assetTransfer.AgreeToSell(transactionContext, "asset1", "user1")
+	require.Error(t, err)
+	clientIdentity.GetIDReturns("seller", nil)
+	err = assetTransfer.AgreeToBuy(transactionContext, "asset1", "user3")
+	require.EqualError(t, err, "the user user3 does not exist")
+}
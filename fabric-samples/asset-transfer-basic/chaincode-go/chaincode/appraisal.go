@@ -0,0 +1,137 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const appraisalTransientKey = "appraisal"
+
+// SetAppraisedValue lets the owner move the appraised value of the asset into the private data
+// collection of their organization. The Appraisal is passed as JSON in the appraisal transient
+// field so the value never appears in the transaction arguments; the public appraised value is
+// cleared and replaced by the hash of the appraisal.
+func (s *SmartContract) SetAppraisedValue(ctx contractapi.TransactionContextInterface, assetID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	appraisalJSON, err := transientAppraisal(ctx)
+	if err != nil {
+		return err
+	}
+	if appraisalJSON == nil {
+		return fmt.Errorf("the appraisal must be passed in the %s transient field", appraisalTransientKey)
+	}
+	appraisal, err := parseAppraisal(appraisalJSON)
+	if err != nil {
+		return err
+	}
+	if appraisal.AssetID != assetID {
+		return fmt.Errorf("the appraisal is for asset %s, not %s", appraisal.AssetID, assetID)
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ctx.GetStub().PutPrivateData(collection, assetID, appraisalJSON)
+	if err != nil {
+		return fmt.Errorf("failed to put appraisal to private data collection %s: %v", collection, err)
+	}
+	asset.AppraisedValue = 0
+	asset.AppraisalCollection = collection
+	asset.AppraisalHash = hashAppraisal(appraisalJSON)
+	return putAsset(ctx, asset)
+}
+
+// GetAppraisedValue returns the appraised value of the asset, in cents. Private appraisals are read
+// from the owner organization's collection, or from the appraisal transient field on peers
+// outside it.
+func (s *SmartContract) GetAppraisedValue(ctx contractapi.TransactionContextInterface, assetID string) (int64, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return 0, err
+	}
+
+	return appraisedValue(ctx, asset)
+}
+
+// appraisedValue returns the appraised value of the asset, checking a private appraisal against
+// the hash on the asset
+func appraisedValue(ctx contractapi.TransactionContextInterface, asset *Asset) (int64, error) {
+	if asset.AppraisalHash == "" && asset.DetailsHash != "" {
+		details, err := privateAssetDetails(ctx, asset)
+		if err != nil {
+			return 0, err
+		}
+		return details.Value, nil
+	}
+	if asset.AppraisalHash == "" {
+		return asset.AppraisedValue, nil
+	}
+	appraisalJSON, err := transientAppraisal(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if appraisalJSON == nil {
+		appraisalJSON, err = ctx.GetStub().GetPrivateData(asset.AppraisalCollection, asset.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read from private data collection %s: %v", asset.AppraisalCollection, err)
+		}
+	}
+	if appraisalJSON == nil {
+		return 0, fmt.Errorf("the appraisal of asset %s must be passed in the %s transient field", asset.ID, appraisalTransientKey)
+	}
+	if hashAppraisal(appraisalJSON) != asset.AppraisalHash {
+		return 0, fmt.Errorf("the appraisal does not match the hash of asset %s", asset.ID)
+	}
+	appraisal, err := parseAppraisal(appraisalJSON)
+	if err != nil {
+		return 0, err
+	}
+
+	return appraisal.Value, nil
+}
+
+// transientAppraisal returns the appraisal passed in the transient data, or nil when none was passed
+func transientAppraisal(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+
+	return transientMap[appraisalTransientKey], nil
+}
+
+// parseAppraisal decodes and validates the appraisal JSON
+func parseAppraisal(appraisalJSON []byte) (*Appraisal, error) {
+	var appraisal Appraisal
+	err := json.Unmarshal(appraisalJSON, &appraisal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal appraisal: %v", err)
+	}
+	if appraisal.Value <= 0 {
+		return nil, fmt.Errorf("appraised value must be positive")
+	}
+
+	return &appraisal, nil
+}
+
+// hashAppraisal returns the hex encoded SHA-256 of the appraisal JSON
+func hashAppraisal(appraisalJSON []byte) string {
+	hash := sha256.Sum256(appraisalJSON)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,50 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateAppraisedValue(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetAppraisedValue(transactionContext, "asset1")
+	require.EqualError(t, err, "the appraisal must be passed in the appraisal transient field")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"appraisal": []byte(`{"assetID":"asset2","value":4500,"salt":"beef"}`)}, nil)
+	err = assetTransfer.SetAppraisedValue(transactionContext, "asset1")
+	require.EqualError(t, err, "the appraisal is for asset asset2, not asset1")
+
+	appraisal := []byte(`{"assetID":"asset1","value":4500,"salt":"beef"}`)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"appraisal": appraisal}, nil)
+	err = assetTransfer.SetAppraisedValue(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, appraisal, state["_implicit_org_Org1MSP/asset1"])
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, int64(0), asset.AppraisedValue)
+	require.NotEmpty(t, asset.AppraisalHash)
+
+	chaincodeStub.GetTransientReturns(nil, nil)
+	value, err := assetTransfer.GetAppraisedValue(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, int64(4500), value)
+
+	// peers outside the owner's organization need the appraisal passed in
+	delete(state, "_implicit_org_Org1MSP/asset1")
+	_, err = assetTransfer.GetAppraisedValue(transactionContext, "asset1")
+	require.EqualError(t, err, "the appraisal of asset asset1 must be passed in the appraisal transient field")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"appraisal": []byte(`{"assetID":"asset1","value":9000,"salt":"beef"}`)}, nil)
+	_, err = assetTransfer.GetAppraisedValue(transactionContext, "asset1")
+	require.EqualError(t, err, "the appraisal does not match the hash of asset asset1")
+}
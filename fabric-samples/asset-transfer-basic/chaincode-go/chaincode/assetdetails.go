@@ -0,0 +1,172 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	assetDetailsObjectType   = "details"
+	assetDetailsTransientKey = "asset_details"
+)
+
+// SetPrivateAssetDetails lets the owner move the brand, model, year, color and appraised value of
+// the asset into the private data collection of their organization. The AssetDetails are passed as
+// JSON in the asset_details transient field and must match the current public details; those are
+// then cleared and replaced by the hash of the details.
+func (s *SmartContract) SetPrivateAssetDetails(ctx contractapi.TransactionContextInterface, assetID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.DetailsHash != "" {
+		return fmt.Errorf("the details of asset %s are already private", assetID)
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	detailsJSON, err := transientAssetDetails(ctx)
+	if err != nil {
+		return err
+	}
+	if detailsJSON == nil {
+		return fmt.Errorf("the asset details must be passed in the %s transient field", assetDetailsTransientKey)
+	}
+	details, err := parseAssetDetails(detailsJSON)
+	if err != nil {
+		return err
+	}
+	value, err := appraisedValue(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if details.AssetID != assetID || details.Brand != asset.Brand || details.Model != asset.Model ||
+		details.Year != asset.Year || details.Color != asset.Color || details.Value != value {
+		return fmt.Errorf("the details do not match asset %s", assetID)
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	detailsKey, err := ctx.GetStub().CreateCompositeKey(assetDetailsObjectType, []string{assetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().PutPrivateData(collection, detailsKey, detailsJSON)
+	if err != nil {
+		return fmt.Errorf("failed to put asset details to private data collection %s: %v", collection, err)
+	}
+	asset.Brand, asset.Model, asset.Year, asset.Color = "", "", 0, ""
+	asset.AppraisedValue = 0
+	asset.DetailsCollection = collection
+	asset.DetailsHash = hashAssetDetails(detailsJSON)
+	return putAsset(ctx, asset)
+}
+
+// GetAssetDetails returns the details of the asset. Private details are read from the owner
+// organization's collection, or from the asset_details transient field on peers outside it.
+func (s *SmartContract) GetAssetDetails(ctx contractapi.TransactionContextInterface, assetID string) (*AssetDetails, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.DetailsHash == "" {
+		value, err := appraisedValue(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		return &AssetDetails{AssetID: asset.ID, Brand: asset.Brand, Model: asset.Model, Year: asset.Year, Color: asset.Color, Value: value}, nil
+	}
+
+	return privateAssetDetails(ctx, asset)
+}
+
+// VerifyAssetDetails reports whether the given asset details document is the one whose hash is on the asset.
+func (s *SmartContract) VerifyAssetDetails(ctx contractapi.TransactionContextInterface, assetID string, detailsJSON string) (bool, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return false, err
+	}
+	if asset.DetailsHash == "" {
+		return false, fmt.Errorf("the details of asset %s are not private", assetID)
+	}
+
+	return hashAssetDetails([]byte(detailsJSON)) == asset.DetailsHash, nil
+}
+
+// ReadPublicAsset returns the public record of the asset: its owner's organization and status
+func (s *SmartContract) ReadPublicAsset(ctx contractapi.TransactionContextInterface, assetID string) (*PublicAsset, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicAsset{ID: asset.ID, OwnerMSP: owner.MSPID, Status: asset.Status, DetailsHash: asset.DetailsHash}, nil
+}
+
+// privateAssetDetails returns the private details of the asset, checking them against the hash on the asset
+func privateAssetDetails(ctx contractapi.TransactionContextInterface, asset *Asset) (*AssetDetails, error) {
+	detailsJSON, err := transientAssetDetails(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if detailsJSON == nil {
+		detailsKey, err := ctx.GetStub().CreateCompositeKey(assetDetailsObjectType, []string{asset.ID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create composite key: %v", err)
+		}
+		detailsJSON, err = ctx.GetStub().GetPrivateData(asset.DetailsCollection, detailsKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from private data collection %s: %v", asset.DetailsCollection, err)
+		}
+	}
+	if detailsJSON == nil {
+		return nil, fmt.Errorf("the details of asset %s must be passed in the %s transient field", asset.ID, assetDetailsTransientKey)
+	}
+	if hashAssetDetails(detailsJSON) != asset.DetailsHash {
+		return nil, fmt.Errorf("the details do not match the hash of asset %s", asset.ID)
+	}
+
+	return parseAssetDetails(detailsJSON)
+}
+
+// transientAssetDetails returns the asset details passed in the transient data, or nil when none were passed
+func transientAssetDetails(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+
+	return transientMap[assetDetailsTransientKey], nil
+}
+
+// parseAssetDetails decodes the asset details JSON
+func parseAssetDetails(detailsJSON []byte) (*AssetDetails, error) {
+	var details AssetDetails
+	err := json.Unmarshal(detailsJSON, &details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal asset details: %v", err)
+	}
+
+	return &details, nil
+}
+
+// hashAssetDetails returns the hex encoded SHA-256 of the asset details JSON
+func hashAssetDetails(detailsJSON []byte) string {
+	hash := sha256.Sum256(detailsJSON)
+	return hex.EncodeToString(hash[:])
+}
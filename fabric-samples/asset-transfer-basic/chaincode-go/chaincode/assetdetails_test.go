@@ -0,0 +1,56 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateAssetDetails(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", Brand: "Toyota", Model: "Prius", Year: 2015, Color: "blue", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetPrivateAssetDetails(transactionContext, "asset1")
+	require.EqualError(t, err, "the asset details must be passed in the asset_details transient field")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"asset_details": []byte(`{"assetID":"asset1","brand":"Toyota","model":"Prius","year":2015,"color":"red","value":3000,"salt":"beef"}`)}, nil)
+	err = assetTransfer.SetPrivateAssetDetails(transactionContext, "asset1")
+	require.EqualError(t, err, "the details do not match asset asset1")
+
+	details := `{"assetID":"asset1","brand":"Toyota","model":"Prius","year":2015,"color":"blue","value":3000,"salt":"beef"}`
+	chaincodeStub.GetTransientReturns(map[string][]byte{"asset_details": []byte(details)}, nil)
+	err = assetTransfer.SetPrivateAssetDetails(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []byte(details), state["_implicit_org_Org1MSP/\x00details\x00asset1\x00"])
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Empty(t, asset.Brand)
+	require.Equal(t, int64(0), asset.AppraisedValue)
+
+	chaincodeStub.GetTransientReturns(nil, nil)
+	publicAsset, err := assetTransfer.ReadPublicAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.PublicAsset{ID: "asset1", OwnerMSP: "Org1MSP", DetailsHash: asset.DetailsHash}, publicAsset)
+
+	assetDetails, err := assetTransfer.GetAssetDetails(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "Prius", assetDetails.Model)
+	value, err := assetTransfer.GetAppraisedValue(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3000), value)
+
+	verified, err := assetTransfer.VerifyAssetDetails(transactionContext, "asset1", details)
+	require.NoError(t, err)
+	require.True(t, verified)
+	verified, err = assetTransfer.VerifyAssetDetails(transactionContext, "asset1", `{"assetID":"asset1"}`)
+	require.NoError(t, err)
+	require.False(t, verified)
+}
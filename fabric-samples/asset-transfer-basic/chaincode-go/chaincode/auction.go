@@ -0,0 +1,433 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	auctionObjectType = "auction"
+	bidObjectType     = "bid"
+	bidTransientKey   = "bid"
+)
+
+// CreateSealedAuction lets the owner put the asset up for a sealed-bid auction. Bidders submit
+// bids with SubmitSealedBid until the seller closes bidding with CloseAuction, then reveal them
+// with RevealSealedBid, and the seller awards the asset with EndAuction.
+// It returns the ID of the new auction.
+func (s *SmartContract) CreateSealedAuction(ctx contractapi.TransactionContextInterface, assetID string) (string, error) {
+	auction, err := s.newAuction(ctx, assetID, AuctionSealed)
+	if err != nil {
+		return "", err
+	}
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return "", err
+	}
+
+	return auction.ID, nil
+}
+
+// SubmitSealedBid places a bid on an open sealed-bid auction. The Bid is passed as JSON in the bid
+// transient field and stored in the implicit private data collection of the bidder's organization;
+// only its hash is added to the auction. It returns the ID of the bid.
+func (s *SmartContract) SubmitSealedBid(ctx contractapi.TransactionContextInterface, auctionID string) (string, error) {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return "", err
+	}
+	err = checkAuctionStatus(auction, AuctionSealed, AuctionOpen)
+	if err != nil {
+		return "", err
+	}
+	bidJSON, err := transientBid(ctx)
+	if err != nil {
+		return "", err
+	}
+	bid, err := parseBid(bidJSON, auctionID)
+	if err != nil {
+		return "", err
+	}
+	if bid.BidderID == auction.SellerID {
+		return "", fmt.Errorf("the seller cannot bid on their own auction")
+	}
+	bidder, err := s.ReadUser(ctx, bid.BidderID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, bidder)
+	if err != nil {
+		return "", err
+	}
+	err = checkUserActive(bidder)
+	if err != nil {
+		return "", err
+	}
+	if auction.Deposit > 0 && bidDeposit(auction, bidder.ID) == 0 {
+		err = lockBidDeposit(auction, bidder)
+		if err != nil {
+			return "", err
+		}
+		err = putUser(ctx, bidder)
+		if err != nil {
+			return "", err
+		}
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := SealedBid{
+		ID:         ctx.GetStub().GetTxID(),
+		BidderID:   bidder.ID,
+		Collection: collection,
+		Hash:       hashBid(bidJSON),
+	}
+	bidKey, err := ctx.GetStub().CreateCompositeKey(bidObjectType, []string{auctionID, sealed.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().PutPrivateData(collection, bidKey, bidJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to put bid to private data collection %s: %v", collection, err)
+	}
+	auction.SealedBids = append(auction.SealedBids, sealed)
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return "", err
+	}
+
+	return sealed.ID, nil
+}
+
+// CloseAuction stops accepting bids. On a sealed-bid auction the seller closes bidding so that bids
+// can be revealed. An english auction can be closed by anyone once its deadline has passed; the
+// high bidder wins and pays the held bid.
+func (s *SmartContract) CloseAuction(ctx contractapi.TransactionContextInterface, auctionID string) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	if auction.Type == AuctionEnglish {
+		return s.closeEnglishAuction(ctx, auction)
+	}
+	err = checkAuctionStatus(auction, auction.Type, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyAuctionSeller(ctx, auction)
+	if err != nil {
+		return err
+	}
+
+	auction.Status = AuctionClosed
+	return putAuction(ctx, auction)
+}
+
+// RevealSealedBid reveals the bid with given ID on a closed sealed-bid auction. The bidder passes
+// the Bid in the bid transient field and it must match the hash submitted with the bid.
+func (s *SmartContract) RevealSealedBid(ctx contractapi.TransactionContextInterface, auctionID string, bidID string) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	err = checkAuctionStatus(auction, AuctionSealed, AuctionClosed)
+	if err != nil {
+		return err
+	}
+	var sealed *SealedBid
+	for i := range auction.SealedBids {
+		if auction.SealedBids[i].ID == bidID {
+			sealed = &auction.SealedBids[i]
+		}
+	}
+	if sealed == nil {
+		return fmt.Errorf("the bid %s was not placed on auction %s", bidID, auctionID)
+	}
+	for _, revealed := range auction.RevealedBids {
+		if revealed.ID == bidID {
+			return fmt.Errorf("the bid %s is already revealed", bidID)
+		}
+	}
+	bidder, err := s.ReadUser(ctx, sealed.BidderID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, bidder)
+	if err != nil {
+		return err
+	}
+	bidJSON, err := transientBid(ctx)
+	if err != nil {
+		return err
+	}
+	if hashBid(bidJSON) != sealed.Hash {
+		return fmt.Errorf("the bid does not match the hash of bid %s", bidID)
+	}
+	bid, err := parseBid(bidJSON, auctionID)
+	if err != nil {
+		return err
+	}
+
+	auction.RevealedBids = append(auction.RevealedBids, RevealedBid{ID: bidID, BidderID: bid.BidderID, Price: bid.Price})
+	return putAuction(ctx, auction)
+}
+
+// EndAuction lets the seller award the asset of a closed auction to the highest revealed bid whose
+// bidder can pay it, counting their deposit. The sale settles at the winning price. Without a valid
+// bid the auction ends and the seller keeps the asset; when the sale to the winner cannot be settled
+// the auction fails and the seller keeps the asset as well.
+func (s *SmartContract) EndAuction(ctx contractapi.TransactionContextInterface, auctionID string) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	err = checkAuctionStatus(auction, auction.Type, AuctionClosed)
+	if err != nil {
+		return err
+	}
+	seller, err := s.verifyAuctionSeller(ctx, auction)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, auction.AssetID)
+	if err != nil {
+		return err
+	}
+
+	// earlier bids win ties
+	var winner *User
+	for _, bid := range auction.RevealedBids {
+		if winner != nil && bid.Price <= auction.Price {
+			continue
+		}
+		bidder, err := s.ReadUser(ctx, bid.BidderID)
+		if err != nil {
+			return err
+		}
+		if checkUserActive(bidder) != nil || bidder.Money+bidDeposit(auction, bidder.ID) < bid.Price {
+			continue
+		}
+		winner = bidder
+		auction.WinnerID = bidder.ID
+		auction.Price = bid.Price
+	}
+
+	// deposits of bidders who revealed are returned, the winner's counts toward the price;
+	// deposits of bids never revealed go to the seller
+	revealed := map[string]bool{}
+	for _, bid := range auction.RevealedBids {
+		revealed[bid.BidderID] = true
+	}
+	credits := map[string]int64{}
+	for bidderID, amount := range depositCredits(auction) {
+		if revealed[bidderID] {
+			credits[bidderID] = credits[bidderID] + amount
+		} else {
+			credits[seller.ID] = credits[seller.ID] + amount
+		}
+	}
+	err = s.payBidders(ctx, credits, seller, winner)
+	if err != nil {
+		return err
+	}
+
+	asset.AuctionID = ""
+	if winner != nil {
+		err = checkSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: winner, price: auction.Price, upfront: auction.Price})
+		if err != nil {
+			return failAuction(ctx, auction, asset, err, seller, winner)
+		}
+	}
+	auction.Status = AuctionEnded
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return err
+	}
+	if winner == nil {
+		err = putUser(ctx, seller)
+		if err != nil {
+			return err
+		}
+		return putAsset(ctx, asset)
+	}
+
+	return executeSale(ctx, asset, seller, winner, auction.Price)
+}
+
+// failAuction ends an auction whose sale to the winner cannot be settled for reason. The seller
+// keeps the asset, which is no longer held by the auction, and the given users, credited their
+// bids and deposits by the caller, are stored.
+func failAuction(ctx contractapi.TransactionContextInterface, auction *Auction, asset *Asset, reason error, users ...*User) error {
+	for _, user := range users {
+		err := putUser(ctx, user)
+		if err != nil {
+			return err
+		}
+	}
+	asset.AuctionID = ""
+	err := putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	auction.Status = AuctionFailed
+	auction.FailureReason = reason.Error()
+	return putAuction(ctx, auction)
+}
+
+// auctionRunning returns true until the auction ended or failed
+func auctionRunning(auction *Auction) bool {
+	return auction.Status != AuctionEnded && auction.Status != AuctionFailed
+}
+
+// newAuction returns an open auction of the asset after checking that the submitting client acts for
+// its owner. The asset is held by the auction until it ends, so it cannot be sold in the meantime.
+func (s *SmartContract) newAuction(ctx contractapi.TransactionContextInterface, assetID string, auctionType string) (*Auction, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return nil, err
+	}
+	if asset.AuctionID != "" {
+		return nil, fmt.Errorf("the asset %s is already up for auction %s", assetID, asset.AuctionID)
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return nil, err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	auction := &Auction{
+		ID:           ctx.GetStub().GetTxID(),
+		AssetID:      assetID,
+		SellerID:     seller.ID,
+		Type:         auctionType,
+		Status:       AuctionOpen,
+		SealedBids:   []SealedBid{},
+		RevealedBids: []RevealedBid{},
+		CreatedAt:    now,
+	}
+	asset.AuctionID = auction.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return auction, nil
+}
+
+// ReadAuction returns the auction stored in the world state with given id
+func (s *SmartContract) ReadAuction(ctx contractapi.TransactionContextInterface, auctionID string) (*Auction, error) {
+	auctionKey, err := ctx.GetStub().CreateCompositeKey(auctionObjectType, []string{auctionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	auctionJSON, err := ctx.GetStub().GetState(auctionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if auctionJSON == nil {
+		return nil, fmt.Errorf("the auction %s does not exist", auctionID)
+	}
+
+	var auction Auction
+	err = json.Unmarshal(auctionJSON, &auction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &auction, nil
+}
+
+// verifyAuctionSeller returns the seller of the auction after checking that the submitting client acts for them
+func (s *SmartContract) verifyAuctionSeller(ctx contractapi.TransactionContextInterface, auction *Auction) (*User, error) {
+	seller, err := s.ReadUser(ctx, auction.SellerID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return nil, err
+	}
+
+	return seller, nil
+}
+
+// checkAuctionStatus returns an error unless the auction is of given type and in given status
+func checkAuctionStatus(auction *Auction, auctionType string, status string) error {
+	if auction.Type != auctionType {
+		return fmt.Errorf("the auction %s is not a %s auction", auction.ID, auctionType)
+	}
+	if auction.Status != status {
+		return fmt.Errorf("the auction %s is %s, expected %s", auction.ID, auction.Status, status)
+	}
+
+	return nil
+}
+
+// transientBid returns the bid passed in the bid transient field
+func transientBid(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+	bidJSON, ok := transientMap[bidTransientKey]
+	if !ok {
+		return nil, fmt.Errorf("the bid must be passed in the %s transient field", bidTransientKey)
+	}
+
+	return bidJSON, nil
+}
+
+// parseBid decodes the bid JSON and checks that it is a valid bid on the auction
+func parseBid(bidJSON []byte, auctionID string) (*Bid, error) {
+	var bid Bid
+	err := json.Unmarshal(bidJSON, &bid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal bid: %v", err)
+	}
+	if bid.AuctionID != auctionID {
+		return nil, fmt.Errorf("the bid is for auction %s, not %s", bid.AuctionID, auctionID)
+	}
+	if bid.Price <= 0 {
+		return nil, fmt.Errorf("bid price must be positive")
+	}
+
+	return &bid, nil
+}
+
+// hashBid returns the hex encoded SHA-256 of the bid JSON
+func hashBid(bidJSON []byte) string {
+	hash := sha256.Sum256(bidJSON)
+	return hex.EncodeToString(hash[:])
+}
+
+// putAuction writes the given auction to the world state
+func putAuction(ctx contractapi.TransactionContextInterface, auction *Auction) error {
+	auctionKey, err := ctx.GetStub().CreateCompositeKey(auctionObjectType, []string{auction.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	auctionJSON, err := json.Marshal(auction)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(auctionKey, auctionJSON)
+}
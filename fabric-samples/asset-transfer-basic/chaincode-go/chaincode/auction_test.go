@@ -0,0 +1,93 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealedBidAuction(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	enrollClients(clientIdentity, map[string]string{"seller": "Org1MSP", "bidder2": "Org2MSP", "bidder3": "Org3MSP"})
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller", MSPID: "Org1MSP", Money: 0})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder2", MSPID: "Org2MSP", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "bidder3", MSPID: "Org3MSP", Money: 7000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.CreateSealedAuction(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "auction1", auctionID)
+
+	bid2 := []byte(`{"auctionID":"auction1","bidderID":"user2","price":4000,"salt":"aa"}`)
+	bid3 := []byte(`{"auctionID":"auction1","bidderID":"user3","price":9000,"salt":"bb"}`)
+
+	clientIdentity.GetIDReturns("bidder2", nil)
+	chaincodeStub.GetTxIDReturns("bid2")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bid2}, nil)
+	bidID, err := assetTransfer.SubmitSealedBid(transactionContext, "auction1")
+	require.NoError(t, err)
+	require.Equal(t, "bid2", bidID)
+	require.Equal(t, bid2, state["_implicit_org_Org2MSP/\x00bid\x00auction1\x00bid2\x00"])
+
+	clientIdentity.GetIDReturns("bidder3", nil)
+	chaincodeStub.GetTxIDReturns("bid3")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bid3}, nil)
+	_, err = assetTransfer.SubmitSealedBid(transactionContext, "auction1")
+	require.NoError(t, err)
+
+	// only the hashes are public
+	auction, err := assetTransfer.ReadAuction(transactionContext, "auction1")
+	require.NoError(t, err)
+	require.Len(t, auction.SealedBids, 2)
+	require.NotContains(t, string(state["\x00auction\x00auction1\x00"]), "9000")
+
+	err = assetTransfer.RevealSealedBid(transactionContext, "auction1", "bid3")
+	require.EqualError(t, err, "the auction auction1 is open, expected closed")
+	err = assetTransfer.CloseAuction(transactionContext, "auction1")
+	require.Error(t, err)
+
+	clientIdentity.GetIDReturns("seller", nil)
+	err = assetTransfer.CloseAuction(transactionContext, "auction1")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("bidder2", nil)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bid2}, nil)
+	_, err = assetTransfer.SubmitSealedBid(transactionContext, "auction1")
+	require.EqualError(t, err, "the auction auction1 is closed, expected open")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": []byte(`{"auctionID":"auction1","bidderID":"user2","price":8000,"salt":"aa"}`)}, nil)
+	err = assetTransfer.RevealSealedBid(transactionContext, "auction1", "bid2")
+	require.EqualError(t, err, "the bid does not match the hash of bid bid2")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bid2}, nil)
+	err = assetTransfer.RevealSealedBid(transactionContext, "auction1", "bid2")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("bidder3", nil)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bid3}, nil)
+	err = assetTransfer.RevealSealedBid(transactionContext, "auction1", "bid3")
+	require.NoError(t, err)
+
+	// user3 bid the most but cannot pay it, so user2 wins
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("end")
+	err = assetTransfer.EndAuction(transactionContext, "auction1")
+	require.NoError(t, err)
+	auction, err = assetTransfer.ReadAuction(transactionContext, "auction1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AuctionEnded, auction.Status)
+	require.Equal(t, "user2", auction.WinnerID)
+	require.Equal(t, int64(4000), auction.Price)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(4000), user.Money)
+}
@@ -0,0 +1,144 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// stampCreated records the submitting client as the creator and last editor of the record
+func stampCreated(ctx contractapi.TransactionContextInterface, audit *Audit) error {
+	err := stampUpdated(ctx, audit)
+	if err != nil {
+		return err
+	}
+
+	audit.CreatedBy = audit.UpdatedBy
+	audit.CreatedMSP = audit.UpdatedMSP
+	audit.CreatedAt = audit.UpdatedAt
+	return nil
+}
+
+// stampUpdated records the submitting client as the last editor of the record
+func stampUpdated(ctx contractapi.TransactionContextInterface, audit *Audit) error {
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	audit.UpdatedBy = clientID
+	audit.UpdatedMSP = mspID
+	audit.UpdatedAt = now
+	return nil
+}
+
+const auditObjectType = "asset~audit"
+
+// GetAuditTrail returns the transactions that changed the asset, oldest first
+func (s *SmartContract) GetAuditTrail(ctx contractapi.TransactionContextInterface, assetID string) ([]*AuditEntry, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(auditObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var entries []*AuditEntry
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var entry AuditEntry
+		err = json.Unmarshal(queryResponse.Value, &entry)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// RecordAuditKeys completes the audit entries of the transaction with every key it wrote. Entries
+// are put when the asset is written, before the rest of the transaction's writes, so this runs after
+// every transaction of the contract and puts them again.
+func RecordAuditKeys(ctx contractapi.TransactionContextInterface) error {
+	written := writtenKeys(ctx)
+	for _, key := range written {
+		// assets are written together with their audit entry
+		entryKey, err := ctx.GetStub().CreateCompositeKey(auditObjectType, []string{key, ctx.GetStub().GetTxID()})
+		if err != nil {
+			continue // not an asset ID
+		}
+		if contains(written, entryKey) {
+			err = putAuditEntry(ctx, key)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// putAuditEntry records the running transaction in the audit trail of the asset, with the keys the
+// transaction wrote so far. Writing the asset several times in one transaction keeps a single entry.
+func putAuditEntry(ctx contractapi.TransactionContextInterface, assetID string) error {
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	function, _ := ctx.GetStub().GetFunctionAndParameters()
+
+	entry := AuditEntry{
+		AssetID:   assetID,
+		TxID:      ctx.GetStub().GetTxID(),
+		Function:  function,
+		ClientID:  clientID,
+		MSPID:     mspID,
+		Timestamp: now,
+		Keys:      []string{assetID},
+	}
+	auditPrefix, err := ctx.GetStub().CreateCompositeKey(auditObjectType, []string{})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	for _, key := range writtenKeys(ctx) {
+		if key != assetID && !strings.HasPrefix(key, auditPrefix) {
+			entry.Keys = append(entry.Keys, key)
+		}
+	}
+	entryKey, err := ctx.GetStub().CreateCompositeKey(auditObjectType, []string{assetID, entry.TxID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(entryKey, entryJSON)
+}
@@ -0,0 +1,108 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetAudit(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("dealer", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateAsset(transactionContext, "asset1", "ZFA19900100123456", "fiat", "500L", 2018, "black", "user1", 3000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("painter", nil)
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	err = assetTransfer.ChangeAssetColor(transactionContext, "asset1", "red")
+	require.NoError(t, err)
+
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.Audit{
+		CreatedBy:  "dealer",
+		CreatedMSP: "Org1MSP",
+		CreatedAt:  time.Unix(1600000000, 0).UTC(),
+		UpdatedBy:  "painter",
+		UpdatedMSP: "Org2MSP",
+		UpdatedAt:  time.Unix(1600000000, 0).UTC(),
+	}, asset.Audit)
+}
+
+func TestAuditTrail(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("dealer", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	assetTransfer := chaincode.SmartContract{}
+
+	chaincodeStub.GetTxIDReturns("tx1")
+	chaincodeStub.GetFunctionAndParametersReturns("CreateAsset", nil)
+	err := assetTransfer.CreateAsset(transactionContext, "asset1", "ZFA19900100123456", "fiat", "500L", 2018, "black", "user1", 3000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("painter", nil)
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	chaincodeStub.GetTxIDReturns("tx2")
+	chaincodeStub.GetFunctionAndParametersReturns("ChangeAssetColor", nil)
+	err = assetTransfer.ChangeAssetColor(transactionContext, "asset1", "red")
+	require.NoError(t, err)
+
+	trail, err := assetTransfer.GetAuditTrail(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, trail, 2)
+	require.Equal(t, &chaincode.AuditEntry{
+		AssetID:   "asset1",
+		TxID:      "tx2",
+		Function:  "ChangeAssetColor",
+		ClientID:  "painter",
+		MSPID:     "Org2MSP",
+		Timestamp: time.Unix(1600000000, 0).UTC(),
+		Keys:      []string{"asset1"},
+	}, trail[1])
+	require.Equal(t, "CreateAsset", trail[0].Function)
+
+	trail, err = assetTransfer.GetAuditTrail(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.Empty(t, trail)
+}
+
+func TestAuditEntryRecordsWrittenKeys(t *testing.T) {
+	state := worldState{}
+	_, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	clientIdentity := &mocks.ClientIdentity{}
+	clientIdentity.GetIDReturns("owner", nil)
+	transactionContext := &chaincode.TransactionContext{}
+	transactionContext.SetClientIdentity(clientIdentity)
+	transactionContext.SetStub(chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.GiftAsset(transactionContext, "asset1", "user2"))
+	require.NoError(t, chaincode.RecordAuditKeys(transactionContext))
+
+	trail, err := assetTransfer.GetAuditTrail(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, trail, 1)
+	// the users are written before the asset and the transfer record after it
+	require.Equal(t, "asset1", trail[0].Keys[0])
+	require.Contains(t, trail[0].Keys, "user1")
+	require.Contains(t, trail[0].Keys, "user2")
+	require.Contains(t, trail[0].Keys, "\x00transfer\x00asset1\x00tx1\x00")
+	for _, key := range trail[0].Keys {
+		require.NotContains(t, key, "asset~audit")
+	}
+}
@@ -0,0 +1,142 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const balanceDeltaObjectType = "balance~delta"
+
+// SetHotAccount marks the user as a hot account whose sale proceeds and dealer commissions are
+// credited as balance deltas. Only admins may change it.
+func (s *SmartContract) SetHotAccount(ctx contractapi.TransactionContextInterface, userID string, hot bool) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.HotAccount = hot
+	return putUser(ctx, user)
+}
+
+// GetBalance returns the balance of the user including credits not consolidated yet, in cents
+func (s *SmartContract) GetBalance(ctx contractapi.TransactionContextInterface, userID string) (int64, error) {
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	deltas, err := getBalanceDeltas(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	balance := user.Money
+	for _, delta := range deltas {
+		balance = balance + delta.Amount
+	}
+
+	return balance, nil
+}
+
+// ConsolidateBalance adds the pending credits of the user to their balance and removes them. Only
+// the consolidated balance can be spent. Anyone may run it, as it does not change what the user owns.
+func (s *SmartContract) ConsolidateBalance(ctx contractapi.TransactionContextInterface, userID string) error {
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	consolidated, err := consolidateDeltas(ctx, user)
+	if err != nil {
+		return err
+	}
+	if consolidated == 0 {
+		return nil
+	}
+
+	return putUser(ctx, user)
+}
+
+// consolidateDeltas adds the pending credits of the user to their balance and removes them. It
+// returns how many credits it consolidated; the caller stores the user.
+func consolidateDeltas(ctx contractapi.TransactionContextInterface, user *User) (int, error) {
+	deltas, err := getBalanceDeltas(ctx, user.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delta := range deltas {
+		user.Money = user.Money + delta.Amount
+		deltaKey, err := ctx.GetStub().CreateCompositeKey(balanceDeltaObjectType, []string{delta.UserID, delta.TxID, delta.Reason})
+		if err != nil {
+			return 0, fmt.Errorf("failed to create composite key: %v", err)
+		}
+		err = ctx.GetStub().DelState(deltaKey)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deltas), nil
+}
+
+// creditUser adds amount to the balance of the user. Hot accounts get a balance delta instead,
+// so the caller must not store them just for the credit. reason tells apart credits to the same
+// user within one transaction.
+func creditUser(ctx contractapi.TransactionContextInterface, user *User, amount int64, reason string) error {
+	if !user.HotAccount {
+		user.Money = user.Money + amount
+		return nil
+	}
+	if amount == 0 {
+		return nil
+	}
+
+	delta := BalanceDelta{
+		UserID: user.ID,
+		TxID:   ctx.GetStub().GetTxID(),
+		Reason: reason,
+		Amount: amount,
+	}
+	deltaKey, err := ctx.GetStub().CreateCompositeKey(balanceDeltaObjectType, []string{delta.UserID, delta.TxID, delta.Reason})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	deltaJSON, err := json.Marshal(delta)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(deltaKey, deltaJSON)
+}
+
+// getBalanceDeltas returns the credits of the user not consolidated yet
+func getBalanceDeltas(ctx contractapi.TransactionContextInterface, userID string) ([]*BalanceDelta, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(balanceDeltaObjectType, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var deltas []*BalanceDelta
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var delta BalanceDelta
+		err = json.Unmarshal(queryResponse.Value, &delta)
+		if err != nil {
+			return nil, err
+		}
+		deltas = append(deltas, &delta)
+	}
+
+	return deltas, nil
+}
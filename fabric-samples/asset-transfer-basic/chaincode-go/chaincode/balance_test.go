@@ -0,0 +1,86 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotAccountSales(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100, Identity: "dealer", Roles: []string{chaincode.RoleDealer}, Version: 1})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Money: 5000, Identity: "buyer"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetHotAccount(transactionContext, "user1", true)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("dealer", nil)
+	chaincodeStub.GetTxIDReturns("tx1")
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("offer1")
+	offerID, err := assetTransfer.OfferAsset(transactionContext, "asset2", "user3", 3000, "")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("tx2")
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.NoError(t, err)
+
+	dealer := &chaincode.User{}
+	state.get(t, "user1", dealer)
+	require.Equal(t, int64(100), dealer.Money)
+	require.Equal(t, int64(2), dealer.Version)
+	balance, err := assetTransfer.GetBalance(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3100), balance)
+	buyer := &chaincode.User{}
+	state.get(t, "user3", buyer)
+	require.Equal(t, int64(2000), buyer.Money)
+
+	err = assetTransfer.ConsolidateBalance(transactionContext, "user1")
+	require.NoError(t, err)
+	state.get(t, "user1", dealer)
+	require.Equal(t, int64(3100), dealer.Money)
+	require.Nil(t, state["\x00balance~delta\x00user1\x00tx2\x00proceeds\x00"])
+	balance, err = assetTransfer.GetBalance(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, int64(3100), balance)
+}
+
+func TestHotAccountCommission(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "buyer"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleDealer}, HotAccount: true})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "\x00config\x00dealerCommissionRate\x00", 500)
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("offer1")
+	offerID, err := assetTransfer.OfferAsset(transactionContext, "asset1", "user2", 4000, "user3")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("tx1")
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.NoError(t, err)
+
+	user := &chaincode.User{}
+	state.get(t, "user3", user)
+	require.Equal(t, int64(0), user.Money)
+	balance, err := assetTransfer.GetBalance(transactionContext, "user3")
+	require.NoError(t, err)
+	require.Equal(t, int64(200), balance)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(3800), user.Money)
+}
@@ -0,0 +1,94 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// maxBatchSize bounds the number of assets registered in one transaction
+const maxBatchSize = 100
+
+// CreateAssets registers a delivery of used cars, given as a JSON array of AssetInput, in one
+// transaction. Every car is checked like in CreateAsset before any is written; when one or more
+// fail, nothing is registered and the error lists the problem of each failing car by its position.
+func (s *SmartContract) CreateAssets(ctx contractapi.TransactionContextInterface, assetsJSON string) error {
+	var inputs []AssetInput
+	err := json.Unmarshal([]byte(assetsJSON), &inputs)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal assets: %v", err)
+	}
+	if len(inputs) == 0 {
+		return fmt.Errorf("no assets to create")
+	}
+	if len(inputs) > maxBatchSize {
+		return fmt.Errorf("at most %d assets can be created at once, got %d", maxBatchSize, len(inputs))
+	}
+
+	var assets []*Asset
+	var problems []string
+	// writes are not visible to reads within the transaction, so duplicates in the batch are caught here
+	ids := map[string]int{}
+	vins := map[string]int{}
+	for i, input := range inputs {
+		asset := &Asset{
+			ID:             input.ID,
+			VIN:            input.VIN,
+			Brand:          input.Brand,
+			Model:          input.Model,
+			Year:           input.Year,
+			Color:          input.Color,
+			OwnerID:        input.OwnerID,
+			AppraisedValue: input.AppraisedValue,
+			Damages:        []Damage{},
+		}
+		err = s.checkBatchAsset(ctx, asset, i, ids, vins)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("asset %d (%s): %v", i, input.ID, err))
+			continue
+		}
+		assets = append(assets, asset)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%d of %d assets are invalid, none were created: %s", len(problems), len(inputs), strings.Join(problems, "; "))
+	}
+
+	for _, asset := range assets {
+		err = writeNewAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkBatchAsset checks the asset at position i of a batch, including against the assets before it
+func (s *SmartContract) checkBatchAsset(ctx contractapi.TransactionContextInterface, asset *Asset, i int, ids map[string]int, vins map[string]int) error {
+	if asset.ID == "" {
+		return fmt.Errorf("the asset ID must not be empty")
+	}
+	if asset.AppraisedValue < 0 {
+		return fmt.Errorf("appraised value must not be negative")
+	}
+	if first, ok := ids[asset.ID]; ok {
+		return fmt.Errorf("the asset %s is also given at position %d", asset.ID, first)
+	}
+	ids[asset.ID] = i
+	err := s.checkCanRegister(ctx, asset.ID, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = s.checkNewAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if first, ok := vins[asset.VIN]; ok {
+		return fmt.Errorf("the VIN %s is also given at position %d", asset.VIN, first)
+	}
+	vins[asset.VIN] = i
+
+	return nil
+}
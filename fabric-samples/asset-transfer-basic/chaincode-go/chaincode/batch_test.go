@@ -0,0 +1,46 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAssets(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "asset9", &chaincode.Asset{ID: "asset9", VIN: "WBA8E1C50HK123456"})
+	state.put(t, "\x00vin~asset\x00WBA8E1C50HK123456\x00", "asset9")
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.CreateAssets(transactionContext, `[
+		{"ID":"asset1","vin":"ZFA19900100123456","brand":"fiat","owner":"user1","appraisedValue":700000},
+		{"ID":"asset1","vin":"1HGCM82633A004352","brand":"honda","owner":"user1"},
+		{"ID":"asset2","vin":"ZFA19900100123456","brand":"fiat","owner":"user1"},
+		{"ID":"asset3","vin":"WBA8E1C50HK123456","brand":"bmw","owner":"user1"},
+		{"ID":"asset4","vin":"WAUZZZ4G0GN01234","brand":"audi","owner":"user1"}
+	]`)
+	require.EqualError(t, err, "4 of 5 assets are invalid, none were created: "+
+		"asset 1 (asset1): the asset asset1 is also given at position 0; "+
+		"asset 2 (asset2): the VIN ZFA19900100123456 is also given at position 0; "+
+		"asset 3 (asset3): VIN WBA8E1C50HK123456 is already registered to asset \"asset9\"; "+
+		"asset 4 (asset4): VIN WAUZZZ4G0GN01234 must be 17 characters long")
+	require.Nil(t, state["asset1"])
+
+	err = assetTransfer.CreateAssets(transactionContext, `[
+		{"ID":"asset1","vin":"ZFA19900100123456","brand":"fiat","owner":"user1","appraisedValue":700000},
+		{"ID":"asset2","vin":"1hgcm82633a004352","brand":"honda","owner":"user1"}
+	]`)
+	require.NoError(t, err)
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.Equal(t, "1HGCM82633A004352", asset.VIN)
+	asset, err = assetTransfer.GetAssetByVIN(transactionContext, "ZFA19900100123456")
+	require.NoError(t, err)
+	require.Equal(t, "asset1", asset.ID)
+	require.Equal(t, int64(700000), asset.AppraisedValue)
+
+	err = assetTransfer.CreateAssets(transactionContext, `[]`)
+	require.EqualError(t, err, "no assets to create")
+}
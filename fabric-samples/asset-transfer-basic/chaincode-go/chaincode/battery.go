@@ -0,0 +1,96 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const batteryReportObjectType = "asset~battery"
+
+// ReportBatteryHealth lets a mechanic record the battery condition of an electric car. The report
+// becomes the battery value shown on the asset. Minted cars must have been built as electric.
+// It returns the ID of the new report.
+func (s *SmartContract) ReportBatteryHealth(ctx contractapi.TransactionContextInterface, assetID string, mechanicID string, stateOfHealth int, capacityWh int64) (string, error) {
+	if stateOfHealth < 0 || stateOfHealth > 100 {
+		return "", fmt.Errorf("state of health must be between 0 and 100 percent")
+	}
+	if capacityWh <= 0 {
+		return "", fmt.Errorf("capacity must be positive")
+	}
+	_, err := s.readMechanic(ctx, mechanicID)
+	if err != nil {
+		return "", err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.Factory != nil && asset.Factory.Specs.FuelType != FuelElectric {
+		return "", fmt.Errorf("the asset %s is not an electric vehicle", assetID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	report := BatteryReport{
+		ID:            ctx.GetStub().GetTxID(),
+		AssetID:       assetID,
+		ReporterID:    mechanicID,
+		StateOfHealth: stateOfHealth,
+		CapacityWh:    capacityWh,
+		ReportedAt:    now,
+	}
+	reportKey, err := ctx.GetStub().CreateCompositeKey(batteryReportObjectType, []string{assetID, report.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+	reportJSON, err := json.Marshal(report)
+	if err != nil {
+		return "", err
+	}
+	err = ctx.GetStub().PutState(reportKey, reportJSON)
+	if err != nil {
+		return "", err
+	}
+
+	asset.Battery = &report
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return report.ID, nil
+}
+
+// GetBatteryReports returns all battery reports of the asset, oldest first
+func (s *SmartContract) GetBatteryReports(ctx contractapi.TransactionContextInterface, assetID string) ([]*BatteryReport, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(batteryReportObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var reports []*BatteryReport
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var report BatteryReport
+		err = json.Unmarshal(queryResponse.Value, &report)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, &report)
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].ReportedAt.Before(reports[j].ReportedAt)
+	})
+
+	return reports, nil
+}
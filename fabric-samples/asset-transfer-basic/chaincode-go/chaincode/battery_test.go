@@ -0,0 +1,43 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatteryHealth(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Factory: &chaincode.FactoryData{Specs: chaincode.FactorySpecs{FuelType: chaincode.FuelElectric}}})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1", Factory: &chaincode.FactoryData{Specs: chaincode.FactorySpecs{FuelType: "petrol"}}})
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.ReportBatteryHealth(transactionContext, "asset1", "user2", 101, 60000)
+	require.EqualError(t, err, "state of health must be between 0 and 100 percent")
+	_, err = assetTransfer.ReportBatteryHealth(transactionContext, "asset2", "user2", 90, 60000)
+	require.EqualError(t, err, "the asset asset2 is not an electric vehicle")
+
+	chaincodeStub.GetTxIDReturns("report1")
+	_, err = assetTransfer.ReportBatteryHealth(transactionContext, "asset1", "user2", 96, 62000)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("report2")
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1630000000}, nil)
+	_, err = assetTransfer.ReportBatteryHealth(transactionContext, "asset1", "user2", 91, 58500)
+	require.NoError(t, err)
+
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "report2", asset.Battery.ID)
+	require.Equal(t, 91, asset.Battery.StateOfHealth)
+	reports, err := assetTransfer.GetBatteryReports(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	require.Equal(t, "report1", reports[0].ID)
+	require.Equal(t, int64(62000), reports[0].CapacityWh)
+}
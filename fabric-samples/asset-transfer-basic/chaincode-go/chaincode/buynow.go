@@ -0,0 +1,92 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// SetBuyNowPrice lets the seller offer the asset of an open auction for an immediate purchase at
+// price. A price of 0 removes the option. Dutch auctions already sell at their current price.
+func (s *SmartContract) SetBuyNowPrice(ctx contractapi.TransactionContextInterface, auctionID string, price int64) error {
+	if price < 0 {
+		return fmt.Errorf("buy-now price must not be negative")
+	}
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	if auction.Type == AuctionDutch {
+		return fmt.Errorf("buy-now is not available on dutch auctions")
+	}
+	err = checkAuctionStatus(auction, auction.Type, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyAuctionSeller(ctx, auction)
+	if err != nil {
+		return err
+	}
+	if price > 0 && price <= auction.HighBid {
+		return fmt.Errorf("the buy-now price must be higher than the current bid %d", auction.HighBid)
+	}
+
+	auction.BuyNowPrice = price
+	return putAuction(ctx, auction)
+}
+
+// BuyNow buys the asset of an open auction at its buy-now price. The auction ends immediately and
+// outstanding bids are cancelled, releasing the held high bid of an english auction and refunding
+// bid deposits; the buyer's own deposit counts toward the price.
+func (s *SmartContract) BuyNow(ctx contractapi.TransactionContextInterface, auctionID string, buyerID string) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	err = checkAuctionStatus(auction, auction.Type, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	if auction.BuyNowPrice == 0 {
+		return fmt.Errorf("the auction %s has no buy-now price", auctionID)
+	}
+	if buyerID == auction.SellerID {
+		return fmt.Errorf("the seller cannot bid on their own auction")
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, auction.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, auction.AssetID)
+	if err != nil {
+		return err
+	}
+
+	credits := depositCredits(auction)
+	if auction.HighBidderID != "" {
+		credits[auction.HighBidderID] = credits[auction.HighBidderID] + auction.HighBid
+	}
+	err = s.payBidders(ctx, credits, buyer)
+	if err != nil {
+		return err
+	}
+	auction.HighBidderID, auction.HighBid = "", 0
+	auction.Status = AuctionEnded
+	auction.WinnerID = buyerID
+	auction.Price = auction.BuyNowPrice
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return err
+	}
+	asset.AuctionID = ""
+
+	return executeSale(ctx, asset, seller, buyer, auction.Price)
+}
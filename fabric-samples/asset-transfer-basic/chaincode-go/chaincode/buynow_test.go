@@ -0,0 +1,61 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuyNow(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "buyer", Money: 9000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.StartAuction(transactionContext, "asset1", 1000, 3600)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("bidder", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 3000)
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.BuyNow(transactionContext, auctionID, "user3")
+	require.EqualError(t, err, "the auction auction1 has no buy-now price")
+
+	clientIdentity.GetIDReturns("seller", nil)
+	err = assetTransfer.SetBuyNowPrice(transactionContext, auctionID, 2500)
+	require.EqualError(t, err, "the buy-now price must be higher than the current bid 3000")
+	err = assetTransfer.SetBuyNowPrice(transactionContext, auctionID, 8000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("buynow")
+	err = assetTransfer.BuyNow(transactionContext, auctionID, "user3")
+	require.NoError(t, err)
+	auction, err := assetTransfer.ReadAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AuctionEnded, auction.Status)
+	require.Equal(t, "user3", auction.WinnerID)
+	require.Equal(t, int64(8000), auction.Price)
+
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(5000), user.Money)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(1000), user.Money)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user3", asset.OwnerID)
+
+	clientIdentity.GetIDReturns("bidder", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 9000)
+	require.EqualError(t, err, "the auction auction1 is ended, expected open")
+}
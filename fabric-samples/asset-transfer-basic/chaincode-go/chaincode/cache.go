@@ -0,0 +1,77 @@
+package chaincode
+
+import (
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// TransactionContext is the transaction context of the contract. The contract API creates one per
+// invocation, so the world state reads it caches never outlive the transaction.
+type TransactionContext struct {
+	contractapi.TransactionContext
+}
+
+// SetStub stores the stub of the invocation behind a read cache
+func (ctx *TransactionContext) SetStub(stub shim.ChaincodeStubInterface) {
+	ctx.TransactionContext.SetStub(newCachingStub(stub))
+}
+
+// cachingStub fetches every world state key from the peer once per transaction. Flows such as
+// TransferAsset read the same asset and users several times through ReadAsset and ReadUser.
+// Reads within a transaction never see its own writes, so the first value read stays valid for
+// the whole transaction and writes need not touch the cache. Values are cached as read and
+// unmarshaled by every caller, who is free to modify what it gets.
+// The stub also keeps the keys the transaction wrote, for its audit entries.
+type cachingStub struct {
+	shim.ChaincodeStubInterface
+	states  map[string][]byte
+	written []string
+}
+
+func newCachingStub(stub shim.ChaincodeStubInterface) *cachingStub {
+	return &cachingStub{ChaincodeStubInterface: stub, states: map[string][]byte{}}
+}
+
+// PutState writes the key and records it in the write set
+func (stub *cachingStub) PutState(key string, value []byte) error {
+	stub.recordWrite(key)
+	return stub.ChaincodeStubInterface.PutState(key, value)
+}
+
+// DelState deletes the key and records it in the write set
+func (stub *cachingStub) DelState(key string) error {
+	stub.recordWrite(key)
+	return stub.ChaincodeStubInterface.DelState(key)
+}
+
+// recordWrite adds the key to the write set unless it is already there
+func (stub *cachingStub) recordWrite(key string) {
+	if !contains(stub.written, key) {
+		stub.written = append(stub.written, key)
+	}
+}
+
+// writtenKeys returns the world state keys the transaction wrote so far, in write order. Stubs that
+// do not keep a write set, like the ones of unit tests, return nil.
+func writtenKeys(ctx contractapi.TransactionContextInterface) []string {
+	stub, ok := ctx.GetStub().(*cachingStub)
+	if !ok {
+		return nil
+	}
+
+	return append([]string{}, stub.written...)
+}
+
+// GetState returns the value of the key, asking the peer only on the first read
+func (stub *cachingStub) GetState(key string) ([]byte, error) {
+	if value, ok := stub.states[key]; ok {
+		return value, nil
+	}
+	value, err := stub.ChaincodeStubInterface.GetState(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stub.states[key] = value
+	return value, nil
+}
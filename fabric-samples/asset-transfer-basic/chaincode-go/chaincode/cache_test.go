@@ -0,0 +1,48 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionContextCachesReads(t *testing.T) {
+	state := worldState{}
+	_, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Name: "Ana"})
+	transactionContext := &chaincode.TransactionContext{}
+	transactionContext.SetClientIdentity(&mocks.ClientIdentity{})
+	transactionContext.SetStub(chaincodeStub)
+
+	assetTransfer := chaincode.SmartContract{}
+	for i := 0; i < 3; i++ {
+		user, err := assetTransfer.ReadUser(transactionContext, "user1")
+		require.NoError(t, err)
+		require.Equal(t, "Ana", user.Name)
+		_, err = assetTransfer.ReadAsset(transactionContext, "asset1")
+		require.EqualError(t, err, "the asset asset1 does not exist")
+	}
+	require.Equal(t, 2, chaincodeStub.GetStateCallCount())
+
+	user, err := assetTransfer.ReadUser(transactionContext, "user1")
+	require.NoError(t, err)
+	user.Name = "Ana Anic"
+	require.NoError(t, transactionContext.GetStub().PutState("user1", []byte(`{"ID":"user1","name":"Ana Anic"}`)))
+	user, err = assetTransfer.ReadUser(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, "Ana", user.Name, "reads within a transaction do not see its writes")
+}
+
+func TestContractAcceptsTransactionContext(t *testing.T) {
+	_, err := contractapi.NewChaincode(&chaincode.SmartContract{
+		Contract: contractapi.Contract{
+			TransactionContextHandler: new(chaincode.TransactionContext),
+			BeforeTransaction:         chaincode.CheckAllowedMSP,
+			AfterTransaction:          chaincode.RecordAuditKeys,
+		},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,320 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	claimObjectType = "claim"
+	ownerClaimIndex = "owner~claim"
+	assetClaimIndex = "asset~claim"
+)
+
+// FileClaim lets the owner claim the cost of a damage on the asset from the insurer of its active
+// policy. It returns the ID of the new claim, which waits for an assessor's decision. Any later
+// change to the claim must be endorsed by a peer of the insurer's organization, so the owner's
+// organization cannot approve or pay out its own claims.
+func (s *SmartContract) FileClaim(ctx contractapi.TransactionContextInterface, assetID string, damageID string) (string, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+	policy, err := activePolicy(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+	if policy.InsurerID == owner.ID {
+		return "", fmt.Errorf("the insurer %s cannot pay out claims on their own asset %s", owner.ID, assetID)
+	}
+	damage, err := s.ReadDamage(ctx, assetID, damageID)
+	if err != nil {
+		return "", err
+	}
+	if damage.ClaimID != "" {
+		return "", fmt.Errorf("the damage %s is already claimed in claim %s", damageID, damage.ClaimID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	claim := Claim{
+		ID:            ctx.GetStub().GetTxID(),
+		PolicyID:      policy.ID,
+		AssetID:       assetID,
+		DamageID:      damageID,
+		OwnerID:       owner.ID,
+		InsurerID:     policy.InsurerID,
+		ClaimedAmount: damage.Cost,
+		FiledAt:       now,
+		Status:        ClaimFiled,
+	}
+	err = putClaim(ctx, &claim)
+	if err != nil {
+		return "", err
+	}
+	err = putClaimIndexes(ctx, &claim)
+	if err != nil {
+		return "", err
+	}
+	err = setClaimEndorsement(ctx, &claim, policy.InsurerMSP)
+	if err != nil {
+		return "", err
+	}
+
+	damage.ClaimID = claim.ID
+	err = putDamage(ctx, damage)
+	if err != nil {
+		return "", err
+	}
+
+	return claim.ID, nil
+}
+
+// AssessClaim lets an assessor of the insurer's organization approve a filed claim for
+// approvedAmount, which may not exceed the claimed amount nor the coverage of the policy, or reject
+// it. The owner of the claimed asset may not assess their own claim.
+func (s *SmartContract) AssessClaim(ctx contractapi.TransactionContextInterface, claimID string, assessorID string, approve bool, approvedAmount int64) error {
+	claim, err := s.ReadClaim(ctx, claimID)
+	if err != nil {
+		return err
+	}
+	if claim.Status != ClaimFiled {
+		return fmt.Errorf("the claim %s is %s", claimID, claim.Status)
+	}
+	assessor, err := s.ReadUser(ctx, assessorID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, assessor)
+	if err != nil {
+		return err
+	}
+	err = requireRole(assessor, RoleAssessor)
+	if err != nil {
+		return err
+	}
+	if assessorID == claim.OwnerID {
+		return fmt.Errorf("the assessor %s cannot assess their own claim %s", assessorID, claimID)
+	}
+	policy, err := s.ReadPolicy(ctx, claim.PolicyID)
+	if err != nil {
+		return err
+	}
+	err = s.checkActsForInsurer(ctx, assessor, claim, policy)
+	if err != nil {
+		return err
+	}
+
+	claim.AssessorID = assessorID
+	if !approve {
+		claim.Status = ClaimRejected
+		return putClaim(ctx, claim)
+	}
+
+	terms, err := readPolicyTerms(ctx, policy)
+	if err != nil {
+		return err
+	}
+	maxAmount := claim.ClaimedAmount
+	if terms.Coverage < maxAmount {
+		maxAmount = terms.Coverage
+	}
+	if approvedAmount <= 0 || approvedAmount > maxAmount {
+		return fmt.Errorf("approved amount must be positive and at most %d", maxAmount)
+	}
+	claim.ApprovedAmount = approvedAmount
+	claim.Status = ClaimApproved
+	return putClaim(ctx, claim)
+}
+
+// PayoutClaim lets the insurer pay the approved amount of a claim, less the policy deductible, to
+// the owner. When the policy charges deductibles and the approved amount does not cover it, the
+// owner pays the insurer the rest instead. The paid amount is recorded as covered on the claimed
+// damage and the breakdown is stored on the claim.
+func (s *SmartContract) PayoutClaim(ctx contractapi.TransactionContextInterface, claimID string) error {
+	claim, err := s.ReadClaim(ctx, claimID)
+	if err != nil {
+		return err
+	}
+	if claim.Status != ClaimApproved {
+		return fmt.Errorf("the claim %s is %s", claimID, claim.Status)
+	}
+	// both accounts are written, so they must be different users
+	if claim.InsurerID == claim.OwnerID {
+		return fmt.Errorf("the insurer %s cannot pay out claims on their own asset %s", claim.InsurerID, claim.AssetID)
+	}
+	insurer, err := s.readInsurer(ctx, claim.InsurerID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, claim.OwnerID)
+	if err != nil {
+		return err
+	}
+	policy, err := s.ReadPolicy(ctx, claim.PolicyID)
+	if err != nil {
+		return err
+	}
+	terms, err := readPolicyTerms(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	claim.Deductible = terms.Deductible
+	claim.PaidAmount = claim.ApprovedAmount - terms.Deductible
+	if claim.PaidAmount < 0 {
+		if terms.ChargeDeductible {
+			claim.DeductibleCharged = -claim.PaidAmount
+		}
+		claim.PaidAmount = 0
+	}
+
+	if insurer.Money < claim.PaidAmount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", insurer.ID)
+	}
+	err = recordSpending(ctx, insurer, claim.PaidAmount)
+	if err != nil {
+		return err
+	}
+	if owner.Money < claim.DeductibleCharged {
+		return fmt.Errorf("user %s doesn't have enough money on his account", owner.ID)
+	}
+	err = recordSpending(ctx, owner, claim.DeductibleCharged)
+	if err != nil {
+		return err
+	}
+
+	insurer.Money = insurer.Money - claim.PaidAmount + claim.DeductibleCharged
+	owner.Money = owner.Money + claim.PaidAmount - claim.DeductibleCharged
+	err = putUser(ctx, insurer)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	damage, err := s.ReadDamage(ctx, claim.AssetID, claim.DamageID)
+	if err != nil {
+		return err
+	}
+	damage.CoveredAmount = claim.PaidAmount
+	err = putDamage(ctx, damage)
+	if err != nil {
+		return err
+	}
+
+	claim.Status = ClaimPaid
+	return putClaim(ctx, claim)
+}
+
+// checkActsForInsurer makes sure the assessor belongs to the organization of the claim's insurer.
+// Policies offered before insurers' organizations were recorded fall back to the organization of
+// the insurer's user.
+func (s *SmartContract) checkActsForInsurer(ctx contractapi.TransactionContextInterface, assessor *User, claim *Claim, policy *InsurancePolicy) error {
+	insurerMSP := policy.InsurerMSP
+	if insurerMSP == "" {
+		insurer, err := s.ReadUser(ctx, claim.InsurerID)
+		if err != nil {
+			return err
+		}
+		insurerMSP = insurer.MSPID
+	}
+	if assessor.MSPID != insurerMSP {
+		return fmt.Errorf("the assessor %s does not act for the insurer %s", assessor.ID, claim.InsurerID)
+	}
+
+	return nil
+}
+
+// ReadClaim returns the claim stored in the world state with given id.
+func (s *SmartContract) ReadClaim(ctx contractapi.TransactionContextInterface, claimID string) (*Claim, error) {
+	claimKey, err := ctx.GetStub().CreateCompositeKey(claimObjectType, []string{claimID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	claimJSON, err := ctx.GetStub().GetState(claimKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if claimJSON == nil {
+		return nil, fmt.Errorf("the claim %s does not exist", claimID)
+	}
+
+	var claim Claim
+	err = json.Unmarshal(claimJSON, &claim)
+	if err != nil {
+		return nil, err
+	}
+
+	return &claim, nil
+}
+
+// GetClaimStatus returns the status of the claim with given id
+func (s *SmartContract) GetClaimStatus(ctx contractapi.TransactionContextInterface, claimID string) (string, error) {
+	claim, err := s.ReadClaim(ctx, claimID)
+	if err != nil {
+		return "", err
+	}
+
+	return claim.Status, nil
+}
+
+// putClaim writes the given claim to the world state
+func putClaim(ctx contractapi.TransactionContextInterface, claim *Claim) error {
+	claimKey, err := ctx.GetStub().CreateCompositeKey(claimObjectType, []string{claim.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	claimJSON, err := json.Marshal(claim)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(claimKey, claimJSON)
+}
+
+// setClaimEndorsement requires endorsement from a peer of the insurer's organization for any
+// update of the claim. Policies offered before insurers' organizations were recorded leave the
+// claim under the chaincode endorsement policy.
+func setClaimEndorsement(ctx contractapi.TransactionContextInterface, claim *Claim, insurerMSP string) error {
+	claimKey, err := ctx.GetStub().CreateCompositeKey(claimObjectType, []string{claim.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return setKeyEndorsement(ctx, claimKey, insurerMSP)
+}
+
+// putClaimIndexes indexes the claim under its owner and its asset
+func putClaimIndexes(ctx contractapi.TransactionContextInterface, claim *Claim) error {
+	err := putClaimIndex(ctx, ownerClaimIndex, claim.OwnerID, claim.ID)
+	if err != nil {
+		return err
+	}
+
+	return putClaimIndex(ctx, assetClaimIndex, claim.AssetID, claim.ID)
+}
+
+// putClaimIndex adds the claim with given ID to the index under subjectID
+func putClaimIndex(ctx contractapi.TransactionContextInterface, indexName string, subjectID string, claimID string) error {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(indexName, []string{subjectID, claimID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte(claimID))
+}
@@ -0,0 +1,209 @@
+package chaincode_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// insuredAsset puts an asset1 owned by user1 with damage1 into the state, insured by user2 under policy1
+func insuredAsset(t *testing.T, state worldState) {
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "insurer", Status: chaincode.UserActive, Roles: []string{chaincode.RoleInsurer}})
+	state.put(t, "user5", &chaincode.User{ID: "user5", Identity: "assessor", MSPID: "Org2MSP", Status: chaincode.UserActive, Roles: []string{chaincode.RoleAssessor}})
+	damage := chaincode.Damage{ID: "damage1", AssetID: "asset1", Description: "door", Cost: 1200, Status: chaincode.DamageOpen}
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{damage}, AppraisedValue: 3000, PolicyID: "policy1"})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage1\x00", &damage)
+	putPolicy(t, state, &chaincode.InsurancePolicy{
+		ID:         "policy1",
+		AssetID:    "asset1",
+		OwnerID:    "user1",
+		InsurerID:  "user2",
+		TermDays:   365,
+		ValidFrom:  time.Unix(1590000000, 0).UTC(),
+		ValidUntil: time.Unix(1590000000, 0).UTC().AddDate(0, 0, 365),
+		Status:     chaincode.PolicyActive,
+	}, chaincode.PolicyTerms{Premium: 300, Coverage: 1000, Deductible: 200})
+}
+
+// putPolicy puts the policy into the state with its terms in the insurer's private data collection
+func putPolicy(t *testing.T, state worldState, policy *chaincode.InsurancePolicy, terms chaincode.PolicyTerms) {
+	termsJSON, err := json.Marshal(terms)
+	require.NoError(t, err)
+	hash := sha256.Sum256(termsJSON)
+	policy.InsurerMSP = "Org2MSP"
+	policy.TermsCollection = "_implicit_org_Org2MSP"
+	policy.TermsHash = hex.EncodeToString(hash[:])
+	state[policy.TermsCollection+"/"+policy.ID] = termsJSON
+	state.put(t, "\x00policy\x00"+policy.ID+"\x00", policy)
+}
+
+func TestFileAndAssessClaim(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	insuredAsset(t, state)
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	chaincodeStub.GetTxIDReturns("claim1")
+	claimID, err := assetTransfer.FileClaim(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, 1, chaincodeStub.SetStateValidationParameterCallCount())
+	claimKey, endorsementPolicy := chaincodeStub.SetStateValidationParameterArgsForCall(0)
+	require.Equal(t, "\x00claim\x00claim1\x00", claimKey)
+	insurerEP, err := statebased.NewStateEP(endorsementPolicy)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Org2MSP"}, insurerEP.ListOrgs())
+	status, err := assetTransfer.GetClaimStatus(transactionContext, claimID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.ClaimFiled, status)
+
+	_, err = assetTransfer.FileClaim(transactionContext, "asset1", "damage1")
+	require.EqualError(t, err, "the damage damage1 is already claimed in claim claim1")
+
+	err = assetTransfer.AssessClaim(transactionContext, claimID, "user1", true, 1000)
+	require.EqualError(t, err, "the user user1 does not have role assessor")
+
+	clientIdentity.GetIDReturns("assessor", nil)
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	err = assetTransfer.AssessClaim(transactionContext, claimID, "user5", true, 1200)
+	require.EqualError(t, err, "approved amount must be positive and at most 1000")
+
+	err = assetTransfer.AssessClaim(transactionContext, claimID, "user5", true, 900)
+	require.NoError(t, err)
+	claim, err := assetTransfer.ReadClaim(transactionContext, claimID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.ClaimApproved, claim.Status)
+	require.Equal(t, int64(900), claim.ApprovedAmount)
+	require.Equal(t, int64(1200), claim.ClaimedAmount)
+	require.Equal(t, "user2", claim.InsurerID)
+
+	err = assetTransfer.AssessClaim(transactionContext, claimID, "user5", false, 0)
+	require.EqualError(t, err, "the claim claim1 is approved")
+}
+
+func TestAssessOwnClaim(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	insuredAsset(t, state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org2MSP", Status: chaincode.UserActive, Roles: []string{chaincode.RoleAssessor}})
+	state.put(t, "\x00claim\x00claim1\x00", &chaincode.Claim{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", DamageID: "damage1", OwnerID: "user1", InsurerID: "user2", ClaimedAmount: 1200, Status: chaincode.ClaimFiled})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	err := assetTransfer.AssessClaim(transactionContext, "claim1", "user1", true, 900)
+	require.EqualError(t, err, "the assessor user1 cannot assess their own claim claim1")
+}
+
+func TestAssessClaimOutsideInsurer(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	insuredAsset(t, state)
+	state.put(t, "user6", &chaincode.User{ID: "user6", Identity: "assessor", MSPID: "Org1MSP", Status: chaincode.UserActive, Roles: []string{chaincode.RoleAssessor}})
+	state.put(t, "\x00claim\x00claim1\x00", &chaincode.Claim{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", DamageID: "damage1", OwnerID: "user1", InsurerID: "user2", ClaimedAmount: 1200, Status: chaincode.ClaimFiled})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("assessor", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	err := assetTransfer.AssessClaim(transactionContext, "claim1", "user6", false, 0)
+	require.EqualError(t, err, "the assessor user6 does not act for the insurer user2")
+	claim, err := assetTransfer.ReadClaim(transactionContext, "claim1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.ClaimFiled, claim.Status)
+}
+
+func TestFileClaimWithoutPolicy(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	_, err := assetTransfer.FileClaim(transactionContext, "asset1", "damage1")
+	require.EqualError(t, err, "the asset asset1 is not insured")
+}
+
+func TestPayoutClaim(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	insuredAsset(t, state)
+	state.put(t, "\x00claim\x00claim1\x00", &chaincode.Claim{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", DamageID: "damage1", OwnerID: "user1", InsurerID: "user2", ClaimedAmount: 1200, ApprovedAmount: 900, Status: chaincode.ClaimApproved})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.PayoutClaim(transactionContext, "claim1")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user2")
+
+	clientIdentity.GetIDReturns("insurer", nil)
+	err = assetTransfer.PayoutClaim(transactionContext, "claim1")
+	require.NoError(t, err)
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(1700), owner.Money)
+	insurer := &chaincode.User{}
+	state.get(t, "user2", insurer)
+	require.Equal(t, int64(4300), insurer.Money)
+	damage, err := assetTransfer.ReadDamage(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, int64(700), damage.CoveredAmount)
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, int64(700), asset.Damages[0].CoveredAmount)
+
+	claim, err := assetTransfer.ReadClaim(transactionContext, "claim1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.ClaimPaid, claim.Status)
+	require.Equal(t, int64(200), claim.Deductible)
+	require.Equal(t, int64(700), claim.PaidAmount)
+	require.Equal(t, int64(0), claim.DeductibleCharged)
+	err = assetTransfer.PayoutClaim(transactionContext, "claim1")
+	require.EqualError(t, err, "the claim claim1 is paid")
+}
+
+func TestPayoutClaimToInsurer(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	insuredAsset(t, state)
+	state.put(t, "\x00claim\x00claim1\x00", &chaincode.Claim{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", DamageID: "damage1", OwnerID: "user2", InsurerID: "user2", ClaimedAmount: 1200, ApprovedAmount: 900, Status: chaincode.ClaimApproved})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.PayoutClaim(transactionContext, "claim1")
+	require.EqualError(t, err, "the insurer user2 cannot pay out claims on their own asset asset1")
+}
+
+func TestPayoutClaimChargesDeductible(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	transactionContext.GetClientIdentity().(*mocks.ClientIdentity).GetIDReturns("insurer", nil)
+	insuredAsset(t, state)
+	policy := &chaincode.InsurancePolicy{}
+	state.get(t, "\x00policy\x00policy1\x00", policy)
+	putPolicy(t, state, policy, chaincode.PolicyTerms{Premium: 300, Coverage: 1000, Deductible: 500, ChargeDeductible: true})
+	state.put(t, "\x00claim\x00claim1\x00", &chaincode.Claim{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", DamageID: "damage1", OwnerID: "user1", InsurerID: "user2", ClaimedAmount: 1200, ApprovedAmount: 300, Status: chaincode.ClaimApproved})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.PayoutClaim(transactionContext, "claim1")
+	require.NoError(t, err)
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(800), owner.Money)
+	insurer := &chaincode.User{}
+	state.get(t, "user2", insurer)
+	require.Equal(t, int64(5200), insurer.Money)
+	claim, err := assetTransfer.ReadClaim(transactionContext, "claim1")
+	require.NoError(t, err)
+	require.Equal(t, int64(0), claim.PaidAmount)
+	require.Equal(t, int64(200), claim.DeductibleCharged)
+}
@@ -0,0 +1,213 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// stateCodec encodes assets and users for the world state
+type stateCodec interface {
+	marshalAsset(asset *Asset) ([]byte, error)
+	unmarshalAsset(value []byte, asset *Asset) error
+	marshalUser(user *User) ([]byte, error)
+	unmarshalUser(value []byte, user *User) error
+}
+
+// State encodings, stored in the stateEncoding setting
+const (
+	EncodingJSON     = "json"
+	EncodingProtobuf = "protobuf"
+)
+
+// stateEncodingConfig is 1 while assets and users are written as protobuf, JSON being the default
+const stateEncodingConfig = "stateEncoding"
+
+// SetStateEncoding selects how assets and users are written from now on, EncodingJSON or
+// EncodingProtobuf. Records already stored stay readable in either encoding; rewrite them with
+// MigrateStateEncoding. CouchDB can only index and query JSON, so protobuf suits LevelDB state
+// databases only. Only admins may change it.
+func (s *SmartContract) SetStateEncoding(ctx contractapi.TransactionContextInterface, encoding string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	value := int64(0)
+	switch encoding {
+	case EncodingJSON:
+	case EncodingProtobuf:
+		value = 1
+	default:
+		return fmt.Errorf("state encoding must be %s or %s", EncodingJSON, EncodingProtobuf)
+	}
+
+	return putConfigInt(ctx, stateEncodingConfig, value)
+}
+
+// MigrateStateEncoding rewrites every stored asset and user in the encoding selected with
+// SetStateEncoding. Only the encoding changes, so versions and audit stamps are kept.
+// Only admins may migrate.
+func (s *SmartContract) MigrateStateEncoding(ctx contractapi.TransactionContextInterface) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	codec, err := configuredCodec(ctx)
+	if err != nil {
+		return err
+	}
+
+	resultsIterator, err := ctx.GetStub().GetStateByRange("asset", "")
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		var value []byte
+		if queryResponse.Key < "user" {
+			var asset Asset
+			err = decodeAsset(queryResponse.Value, &asset)
+			if err != nil {
+				return fmt.Errorf("failed to decode asset %s: %v", queryResponse.Key, err)
+			}
+			value, err = codec.marshalAsset(&asset)
+		} else {
+			var user User
+			err = decodeUser(queryResponse.Value, &user)
+			if err != nil {
+				return fmt.Errorf("failed to decode user %s: %v", queryResponse.Key, err)
+			}
+			value, err = codec.marshalUser(&user)
+		}
+		if err != nil {
+			return err
+		}
+		err = ctx.GetStub().PutState(queryResponse.Key, value)
+		if err != nil {
+			return fmt.Errorf("failed to put %s to world state. %v", queryResponse.Key, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeAsset returns the asset in the configured state encoding
+func encodeAsset(ctx contractapi.TransactionContextInterface, asset *Asset) ([]byte, error) {
+	codec, err := configuredCodec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.marshalAsset(asset)
+}
+
+// encodeUser returns the user in the configured state encoding
+func encodeUser(ctx contractapi.TransactionContextInterface, user *User) ([]byte, error) {
+	codec, err := configuredCodec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.marshalUser(user)
+}
+
+// decodeAsset reads an asset stored in either encoding
+func decodeAsset(value []byte, asset *Asset) error {
+	return codecOf(value).unmarshalAsset(value, asset)
+}
+
+// decodeUser reads a user stored in either encoding
+func decodeUser(value []byte, user *User) error {
+	return codecOf(value).unmarshalUser(value, user)
+}
+
+// configuredCodec returns the codec new records are written with
+func configuredCodec(ctx contractapi.TransactionContextInterface) (stateCodec, error) {
+	encoding, err := getConfigInt(ctx, stateEncodingConfig, 0)
+	if err != nil {
+		return nil, err
+	}
+	if encoding == 1 {
+		return protobufCodec{}, nil
+	}
+
+	return jsonCodec{}, nil
+}
+
+// codecOf returns the codec a stored value was written with. A JSON object starts with '{',
+// which as a protobuf key would start a group of field 15, a wire type the state messages never use.
+func codecOf(value []byte) stateCodec {
+	if len(value) > 0 && value[0] == '{' {
+		return jsonCodec{}
+	}
+
+	return protobufCodec{}
+}
+
+// jsonCodec stores records as JSON, readable by CouchDB queries
+type jsonCodec struct{}
+
+func (jsonCodec) marshalAsset(asset *Asset) ([]byte, error) {
+	return json.Marshal(asset)
+}
+
+func (jsonCodec) unmarshalAsset(value []byte, asset *Asset) error {
+	return json.Unmarshal(value, asset)
+}
+
+func (jsonCodec) marshalUser(user *User) ([]byte, error) {
+	return json.Marshal(user)
+}
+
+func (jsonCodec) unmarshalUser(value []byte, user *User) error {
+	return json.Unmarshal(value, user)
+}
+
+// protobufCodec stores records as the protobuf messages of statepb.go, smaller and faster to decode
+type protobufCodec struct{}
+
+func (protobufCodec) marshalAsset(asset *Asset) ([]byte, error) {
+	message, err := assetToProto(asset)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) unmarshalAsset(value []byte, asset *Asset) error {
+	var message assetProto
+	err := proto.Unmarshal(value, &message)
+	if err != nil {
+		return err
+	}
+
+	return assetFromProto(&message, asset)
+}
+
+func (protobufCodec) marshalUser(user *User) ([]byte, error) {
+	message, err := userToProto(user)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(message)
+}
+
+func (protobufCodec) unmarshalUser(value []byte, user *User) error {
+	var message userProto
+	err := proto.Unmarshal(value, &message)
+	if err != nil {
+		return err
+	}
+
+	return userFromProto(&message, user)
+}
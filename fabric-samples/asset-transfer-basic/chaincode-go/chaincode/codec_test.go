@@ -0,0 +1,105 @@
+package chaincode_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+// requireAllFieldsSet fails when a field of the value is left zero, so a field added to a stored
+// type without a test value, and possibly without a protobuf field, is noticed
+func requireAllFieldsSet(t *testing.T, value reflect.Value, path string) {
+	switch value.Kind() {
+	case reflect.Ptr:
+		require.False(t, value.IsNil(), path)
+		requireAllFieldsSet(t, value.Elem(), path)
+	case reflect.Slice:
+		require.NotZero(t, value.Len(), path)
+		for i := 0; i < value.Len(); i++ {
+			requireAllFieldsSet(t, value.Index(i), path)
+		}
+	case reflect.Struct:
+		if value.Type() == reflect.TypeOf(time.Time{}) {
+			require.False(t, value.Interface().(time.Time).IsZero(), path)
+			return
+		}
+		for i := 0; i < value.NumField(); i++ {
+			requireAllFieldsSet(t, value.Field(i), path+"."+value.Type().Field(i).Name)
+		}
+	default:
+		require.False(t, value.IsZero(), path)
+	}
+}
+
+func TestProtobufStateEncoding(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	at := time.Date(2020, time.September, 13, 12, 26, 40, 0, time.UTC)
+	audit := chaincode.Audit{CreatedBy: "admin", CreatedMSP: "Org1MSP", CreatedAt: at, UpdatedBy: "owner", UpdatedMSP: "Org2MSP", UpdatedAt: at.Add(time.Hour)}
+	asset := &chaincode.Asset{
+		DocType: "asset", ID: "asset1", VIN: "ZFA19900100123456", Plate: "BG123AB", Brand: "fiat", Model: "punto",
+		Year: 2015, Color: "red", OwnerID: "user1", AppraisedValue: 700000, Encumbered: true, Frozen: true,
+		FrozenReason: "court order", Status: chaincode.AssetSalvaged, PolicyID: "policy1", Delegate: "user2",
+		SeizureID: "seizure1", AuctionID: "auction1", Stolen: true, Jurisdiction: "RS", ExportID: "export1", Recalls: []string{"recall1"},
+		Mileage: 120000, MileageTampered: true, WarrantyID: "warranty1",
+		Damages: []chaincode.Damage{{
+			ID: "damage1", AssetID: "asset1", Description: "tyre", Cost: 3499, Status: "open", ReporterID: "reporter",
+			ReportedAt: at, RepairedBy: "user3", DocumentHashes: []string{"ab"}, ClaimID: "claim1", CoveredAmount: 1000,
+			AccidentID: "accident1", RepairJobID: "job1", Liability: []chaincode.LiabilityShare{{UserID: "user2", Share: 10000}}, Audit: audit,
+		}},
+		Factory: &chaincode.FactoryData{ManufacturerID: "user4", ProductionDate: at, Specs: chaincode.FactorySpecs{
+			Engine: "1.2", PowerKW: 51, Transmission: "manual", FuelType: "petrol", Seats: 5}},
+		Battery:      &chaincode.BatteryReport{ID: "battery1", AssetID: "asset1", ReporterID: "user3", StateOfHealth: 91, CapacityWh: 52000, ReportedAt: at},
+		InspectionID: "inspection1", InspectionValidUntil: at.AddDate(1, 0, 0),
+		EmissionsTestID: "emissions1", EmissionsValidUntil: at.AddDate(2, 0, 0),
+		AppraisalCollection: "appraisals", AppraisalHash: "cd", DetailsCollection: "details", DetailsHash: "ef",
+		CoOwners:      []chaincode.OwnershipShare{{UserID: "user1", Share: 6000}, {UserID: "user2", Share: 4000}},
+		SaleApprovals: []string{"user2"}, SaleApprovalBuyer: "user5", ShareSupply: 100, BuyBackUserID: "user2",
+		BuyBackPrice: 500, BuyBackEscrow: 50000, LienID: "loan1", LienholderID: "user6", LienTransferApproval: "user5",
+		RegistrationID: "registration1", NoteCollection: "notes", Audit: audit, Version: 7,
+	}
+	requireAllFieldsSet(t, reflect.ValueOf(asset), "Asset")
+	user := &chaincode.User{
+		ID: "user1", Name: "Ana", Lastname: "Anic", Email: "ana@example.com", Money: 5000, Status: chaincode.UserActive,
+		Roles: []string{chaincode.RoleDealer}, Identity: "owner", MSPID: "Org1MSP", KYCVerified: true, Jurisdiction: "RS",
+		PIICollection: "pii", PIIHash: "01", EmailHash: "02", PIIPurged: true, Audit: audit, Version: 3,
+		FrozenReason: "fraud", HotAccount: true, DailyLimit: 100000, DailySpent: 2500, SpentDay: "2020-09-13",
+	}
+	requireAllFieldsSet(t, reflect.ValueOf(user), "User")
+	state.put(t, "asset1", asset)
+	state.put(t, "user1", user)
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetStateEncoding(transactionContext, "xml")
+	require.EqualError(t, err, "state encoding must be json or protobuf")
+	require.NoError(t, assetTransfer.SetStateEncoding(transactionContext, chaincode.EncodingProtobuf))
+	jsonSize := len(state["asset1"])
+	require.NoError(t, assetTransfer.MigrateStateEncoding(transactionContext))
+	require.NotEqual(t, byte('{'), state["asset1"][0])
+	require.NotEqual(t, byte('{'), state["user1"][0])
+	require.Less(t, len(state["asset1"]), jsonSize)
+
+	read, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, asset, read)
+	readUser, err := assetTransfer.ReadUser(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, user, readUser)
+
+	// records written as JSON before the switch stay readable
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 100})
+	users, err := assetTransfer.GetAllUsers(transactionContext)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	require.Equal(t, int64(100), users[1].Money)
+
+	require.NoError(t, assetTransfer.SetStateEncoding(transactionContext, chaincode.EncodingJSON))
+	require.NoError(t, assetTransfer.MigrateStateEncoding(transactionContext))
+	stored := &chaincode.Asset{}
+	state.get(t, "asset1", stored)
+	require.Equal(t, asset, stored)
+}
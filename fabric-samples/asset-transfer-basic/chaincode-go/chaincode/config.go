@@ -0,0 +1,41 @@
+package chaincode
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Network-wide settings are stored under config composite keys and changed only by admins.
+const configObjectType = "config"
+
+// ledgerInitializedConfig is set to 1 once InitLedger has run
+const ledgerInitializedConfig = "ledgerInitialized"
+
+// getConfigInt returns the integer setting with given name, or defaultValue when it was never set
+func getConfigInt(ctx contractapi.TransactionContextInterface, name string, defaultValue int64) (int64, error) {
+	configKey, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{name})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	value, err := ctx.GetStub().GetState(configKey)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if value == nil {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+// putConfigInt stores the integer setting with given name
+func putConfigInt(ctx contractapi.TransactionContextInterface, name string, value int64) error {
+	configKey, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{name})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().PutState(configKey, []byte(strconv.FormatInt(value, 10)))
+}
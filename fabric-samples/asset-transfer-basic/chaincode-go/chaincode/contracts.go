@@ -0,0 +1,99 @@
+package chaincode
+
+import (
+	"reflect"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// The chaincode serves its transactions through one contract per domain. Each contract shares the
+// SmartContract implementation and exposes only the transactions of its domain, so clients call
+// them as "UserContract:ReadUser" and so on. AssetContract is the default contract, so asset
+// transactions can be called without the contract name.
+const (
+	AssetContractName  = "AssetContract"
+	UserContractName   = "UserContract"
+	RepairContractName = "RepairContract"
+)
+
+// userTransactions are the transactions of UserContract
+var userTransactions = []string{
+	"CreateUser", "CreateUserFromTransient", "UpdateUser", "CloseUserAccount", "ReadUser", "UserExists",
+	"GetUserByEmail", "GetAllUsers", "BindUserIdentity", "GrantRole", "RevokeRole", "FreezeUser",
+	"UnfreezeUser", "RecordBlockedAttempt", "GetBlockedAttempts", "SetUserJurisdiction", "SetKYCVerified", "SetKYCThreshold", "SetPrivateUserPII",
+	"GetUserPII", "PurgeUserPII", "DepositFunds", "WithdrawFunds", "TransferFunds", "SetSpendingLimit",
+	"GetUserBalanceHistory", "SetHotAccount", "GetBalance", "ConsolidateBalance",
+}
+
+// repairTransactions are the transactions of RepairContract
+var repairTransactions = []string{
+	"RequestRepair", "RequestSalvageRestoration", "SubmitRepairQuote", "AcceptRepairQuote", "StartRepair",
+	"CompleteRepair", "PayRepair", "ReadRepairJob", "ReadRepairQuote", "GetRepairQuotes",
+	"AttachRepairDocument", "GetInvoicesForAsset", "GetInvoicesForMechanic", "ReadInvoice", "RateMechanic",
+	"GetMechanicRating", "AddServiceRecord", "GetServiceHistory", "ShareServiceHistory",
+	"UnshareServiceHistory", "AddServiceBookEntry", "GetServiceBook", "IssueWarranty", "ReadWarranty",
+	"FileWarrantyClaim", "ApproveWarrantyClaim", "RejectWarrantyClaim",
+}
+
+// AssetContract serves the transactions on assets and their sales, and the administration of the
+// chaincode: every transaction not served by UserContract or RepairContract
+type AssetContract struct {
+	SmartContract
+}
+
+// GetIgnoredFunctions hides the transactions of the other contracts
+func (c *AssetContract) GetIgnoredFunctions() []string {
+	return append(append([]string{}, userTransactions...), repairTransactions...)
+}
+
+// UserContract serves the transactions on users, their accounts and their funds
+type UserContract struct {
+	SmartContract
+}
+
+// GetIgnoredFunctions hides the transactions of the other contracts
+func (c *UserContract) GetIgnoredFunctions() []string {
+	return transactionsExcept(userTransactions)
+}
+
+// RepairContract serves the transactions on repairs, service records and warranties
+type RepairContract struct {
+	SmartContract
+}
+
+// GetIgnoredFunctions hides the transactions of the other contracts
+func (c *RepairContract) GetIgnoredFunctions() []string {
+	return transactionsExcept(repairTransactions)
+}
+
+// Contracts returns the contracts of the chaincode, AssetContract first as the default one
+func Contracts() []contractapi.ContractInterface {
+	newContract := func(name string) contractapi.Contract {
+		return contractapi.Contract{
+			Name:                      name,
+			TransactionContextHandler: new(TransactionContext),
+			BeforeTransaction:         CheckAllowedMSP,
+			AfterTransaction:          RecordAuditKeys,
+		}
+	}
+
+	return []contractapi.ContractInterface{
+		&AssetContract{SmartContract{Contract: newContract(AssetContractName)}},
+		&UserContract{SmartContract{Contract: newContract(UserContractName)}},
+		&RepairContract{SmartContract{Contract: newContract(RepairContractName)}},
+	}
+}
+
+// transactionsExcept returns the methods of SmartContract that are not in kept
+func transactionsExcept(kept []string) []string {
+	var ignored []string
+	contractType := reflect.TypeOf(&SmartContract{})
+	for i := 0; i < contractType.NumMethod(); i++ {
+		name := contractType.Method(i).Name
+		if !contains(kept, name) {
+			ignored = append(ignored, name)
+		}
+	}
+
+	return ignored
+}
@@ -0,0 +1,51 @@
+package chaincode_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContracts(t *testing.T) {
+	contracts := chaincode.Contracts()
+	cc, err := contractapi.NewChaincode(contracts...)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AssetContractName, cc.DefaultContract)
+
+	// every transaction is served by exactly one contract
+	served := map[string][]string{}
+	for _, contract := range contracts {
+		ignored := contract.(contractapi.IgnoreContractInterface).GetIgnoredFunctions()
+		contractType := reflect.TypeOf(contract)
+		for i := 0; i < contractType.NumMethod(); i++ {
+			name := contractType.Method(i).Name
+			if !contains(ignored, name) {
+				served[name] = append(served[name], contract.GetName())
+			}
+		}
+	}
+	contractType := reflect.TypeOf(&chaincode.SmartContract{})
+	frameworkType := reflect.TypeOf(&contractapi.Contract{})
+	for i := 0; i < contractType.NumMethod(); i++ {
+		name := contractType.Method(i).Name
+		if _, ok := frameworkType.MethodByName(name); ok {
+			continue
+		}
+		require.Len(t, served[name], 1, name)
+	}
+	require.Equal(t, []string{chaincode.UserContractName}, served["ReadUser"])
+	require.Equal(t, []string{chaincode.RepairContractName}, served["RequestRepair"])
+	require.Equal(t, []string{chaincode.AssetContractName}, served["ReadAsset"])
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const coOwnerApprovalConfig = "coOwnerApprovalThreshold"
+
+// SetCoOwnerApprovalThreshold sets the total share of co-owners, in basis points, that must approve
+// selling a co-owned asset. The default of 10000 requires every co-owner. Only admins may change it.
+func (s *SmartContract) SetCoOwnerApprovalThreshold(ctx contractapi.TransactionContextInterface, basisPoints int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if basisPoints <= 0 || basisPoints > 10000 {
+		return fmt.Errorf("approval threshold must be between 1 and 10000 basis points")
+	}
+
+	return putConfigInt(ctx, coOwnerApprovalConfig, basisPoints)
+}
+
+// TransferShare moves basisPoints of the asset from a co-owner to another user, making them a
+// co-owner. The sole owner of an asset holds all 10000 basis points. When the owner gives away their
+// whole share, the largest remaining co-owner becomes the owner.
+func (s *SmartContract) TransferShare(ctx contractapi.TransactionContextInterface, assetID string, fromUserID string, toUserID string, basisPoints int64) error {
+	if basisPoints <= 0 {
+		return fmt.Errorf("share must be positive")
+	}
+	if fromUserID == toUserID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	err = checkNotTokenized(asset)
+	if err != nil {
+		return err
+	}
+	from, err := s.ReadUser(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, from)
+	if err != nil {
+		return err
+	}
+	to, err := s.ReadUser(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(to)
+	if err != nil {
+		return err
+	}
+
+	shares := ownershipShares(asset)
+	if ownershipShare(shares, fromUserID) < basisPoints {
+		return fmt.Errorf("user %s holds less than %d basis points of asset %s", fromUserID, basisPoints, assetID)
+	}
+	shares = addShare(shares, fromUserID, -basisPoints)
+	shares = addShare(shares, toUserID, basisPoints)
+
+	if ownershipShare(shares, asset.OwnerID) == 0 {
+		largest := shares[0]
+		for _, share := range shares {
+			if share.Share > largest.Share {
+				largest = share
+			}
+		}
+		asset.OwnerID = largest.UserID
+	}
+	asset.CoOwners = shares
+	if len(shares) == 1 {
+		asset.CoOwners = nil
+	}
+	asset.SaleApprovals, asset.SaleApprovalBuyer = nil, ""
+	return putAsset(ctx, asset)
+}
+
+// ApproveCoOwnedSale lets a co-owner approve selling the whole asset to buyerID. Approving a
+// different buyer withdraws the approvals given for the previous one.
+func (s *SmartContract) ApproveCoOwnedSale(ctx contractapi.TransactionContextInterface, assetID string, userID string, buyerID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if ownershipShare(asset.CoOwners, userID) == 0 {
+		return fmt.Errorf("user %s is not a co-owner of asset %s", userID, assetID)
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if asset.SaleApprovalBuyer != buyerID {
+		asset.SaleApprovals, asset.SaleApprovalBuyer = nil, buyerID
+	}
+	for _, approver := range asset.SaleApprovals {
+		if approver == userID {
+			return nil
+		}
+	}
+	asset.SaleApprovals = append(asset.SaleApprovals, userID)
+	return putAsset(ctx, asset)
+}
+
+// checkCoOwnerApproval returns an error unless enough co-owners approved selling the asset to the buyer
+func checkCoOwnerApproval(ctx contractapi.TransactionContextInterface, asset *Asset, buyerID string) error {
+	if len(asset.CoOwners) == 0 {
+		return nil
+	}
+	threshold, err := getConfigInt(ctx, coOwnerApprovalConfig, 10000)
+	if err != nil {
+		return err
+	}
+	approved := int64(0)
+	if asset.SaleApprovalBuyer == buyerID {
+		for _, approver := range asset.SaleApprovals {
+			approved = approved + ownershipShare(asset.CoOwners, approver)
+		}
+	}
+	if approved < threshold {
+		return fmt.Errorf("co-owners holding %d of %d required basis points approved selling asset %s to user %s", approved, threshold, asset.ID, buyerID)
+	}
+
+	return nil
+}
+
+// splitProceeds pays the co-owners of the asset their share of the sale proceeds. The seller and
+// the buyer are credited in memory and stored by the caller, unless the seller is a hot account
+// credited through a balance delta; the seller, as owner, keeps what rounding leaves over.
+func splitProceeds(ctx contractapi.TransactionContextInterface, asset *Asset, seller *User, buyer *User, proceeds int64) error {
+	if len(asset.CoOwners) == 0 {
+		return creditUser(ctx, seller, proceeds, "proceeds")
+	}
+
+	paid := int64(0)
+	for _, share := range asset.CoOwners {
+		if share.UserID == seller.ID {
+			continue
+		}
+		amount := proceeds * share.Share / 10000
+		paid = paid + amount
+		if share.UserID == buyer.ID {
+			buyer.Money = buyer.Money + amount
+			continue
+		}
+		coOwner, err := readUser(ctx, share.UserID)
+		if err != nil {
+			return err
+		}
+		coOwner.Money = coOwner.Money + amount
+		err = putUser(ctx, coOwner)
+		if err != nil {
+			return err
+		}
+	}
+
+	return creditUser(ctx, seller, proceeds-paid, "proceeds")
+}
+
+// ownershipShares returns the shares of the asset, the owner holding everything when it has no co-owners
+func ownershipShares(asset *Asset) []OwnershipShare {
+	if len(asset.CoOwners) == 0 {
+		return []OwnershipShare{{UserID: asset.OwnerID, Share: 10000}}
+	}
+
+	return append([]OwnershipShare{}, asset.CoOwners...)
+}
+
+// ownershipShare returns the share the user holds, in basis points
+func ownershipShare(shares []OwnershipShare, userID string) int64 {
+	for _, share := range shares {
+		if share.UserID == userID {
+			return share.Share
+		}
+	}
+
+	return 0
+}
+
+// addShare adds basisPoints to the user's share, dropping users left without a share
+func addShare(shares []OwnershipShare, userID string, basisPoints int64) []OwnershipShare {
+	var result []OwnershipShare
+	found := false
+	for _, share := range shares {
+		if share.UserID == userID {
+			share.Share = share.Share + basisPoints
+			found = true
+		}
+		if share.Share > 0 {
+			result = append(result, share)
+		}
+	}
+	if !found && basisPoints > 0 {
+		result = append(result, OwnershipShare{UserID: userID, Share: basisPoints})
+	}
+
+	return result
+}
@@ -0,0 +1,82 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoOwnedSale(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "partner"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "sibling"})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Identity: "buyer", Money: 10000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.TransferShare(transactionContext, "asset1", "user1", "user2", 3000)
+	require.NoError(t, err)
+	err = assetTransfer.TransferShare(transactionContext, "asset1", "user1", "user3", 8000)
+	require.EqualError(t, err, "user user1 holds less than 8000 basis points of asset asset1")
+	err = assetTransfer.TransferShare(transactionContext, "asset1", "user1", "user3", 2000)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, []chaincode.OwnershipShare{{UserID: "user1", Share: 5000}, {UserID: "user2", Share: 3000}, {UserID: "user3", Share: 2000}}, asset.CoOwners)
+
+	err = assetTransfer.ApproveCoOwnedSale(transactionContext, "asset1", "user1", "user4")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("partner", nil)
+	err = assetTransfer.ApproveCoOwnedSale(transactionContext, "asset1", "user2", "user4")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	chaincodeStub.GetTxIDReturns("offer1")
+	offerID, err := assetTransfer.OfferAsset(transactionContext, "asset1", "user4", 10000, "")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.EqualError(t, err, "co-owners holding 8000 of 10000 required basis points approved selling asset asset1 to user user4")
+
+	err = assetTransfer.SetCoOwnerApprovalThreshold(transactionContext, 7500)
+	require.NoError(t, err)
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user4", asset.OwnerID)
+	require.Empty(t, asset.CoOwners)
+	user := &chaincode.User{}
+	for id, money := range map[string]int64{"user1": 5000, "user2": 3000, "user3": 2000, "user4": 0} {
+		state.get(t, id, user)
+		require.Equal(t, money, user.Money, id)
+	}
+}
+
+func TestTransferWholeShare(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", CoOwners: []chaincode.OwnershipShare{{UserID: "user1", Share: 4000}, {UserID: "user2", Share: 6000}}})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.TransferShare(transactionContext, "asset1", "user1", "user3", 4000)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Equal(t, []chaincode.OwnershipShare{{UserID: "user2", Share: 6000}, {UserID: "user3", Share: 4000}}, asset.CoOwners)
+
+	err = assetTransfer.TransferShare(transactionContext, "asset1", "user3", "user2", 4000)
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Empty(t, asset.CoOwners)
+}
@@ -0,0 +1,226 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const damageObjectType = "asset~damage"
+
+const (
+	damageAlertThresholdConfig  = "damageAlertThreshold"
+	defaultDamageAlertThreshold = 7500
+)
+
+// SetDamageAlertThreshold sets the share of the appraised value, in basis points, that the open
+// damages of an asset may cost before an AssetAtRisk event is emitted. Only admins may set it.
+func (s *SmartContract) SetDamageAlertThreshold(ctx contractapi.TransactionContextInterface, basisPoints int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if basisPoints < 0 || basisPoints > 10000 {
+		return fmt.Errorf("alert threshold must be between 0 and 10000 basis points")
+	}
+
+	return putConfigInt(ctx, damageAlertThresholdConfig, basisPoints)
+}
+
+// GetDamages returns all damages ever reported on asset with given ID, repaired ones included
+func (s *SmartContract) GetDamages(ctx contractapi.TransactionContextInterface, assetID string) ([]*Damage, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(damageObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var damages []*Damage
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var damage Damage
+		err = json.Unmarshal(queryResponse.Value, &damage)
+		if err != nil {
+			return nil, err
+		}
+		damages = append(damages, &damage)
+	}
+
+	return damages, nil
+}
+
+// CreateSharedAssetDamage records a damage on the asset from an accident between two cars whose
+// repair cost is split between firstUserID and secondUserID. firstShare is the part paid by the
+// first user in basis points, the second user pays the rest. Only admins may record shared
+// damages since their repair debits users other than the owner.
+func (s *SmartContract) CreateSharedAssetDamage(ctx contractapi.TransactionContextInterface, id string, description string, cost int64, firstUserID string, secondUserID string, firstShare int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if firstShare < 0 || firstShare > 10000 {
+		return fmt.Errorf("share must be between 0 and 10000 basis points")
+	}
+	if firstUserID == secondUserID {
+		return fmt.Errorf("the damage cost must be split between two different users")
+	}
+	for _, userID := range []string{firstUserID, secondUserID} {
+		exists, err := s.UserExists(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("the user %s does not exist", userID)
+		}
+	}
+
+	return s.addAssetDamage(ctx, id, description, cost, []LiabilityShare{
+		{UserID: firstUserID, Share: firstShare},
+		{UserID: secondUserID, Share: 10000 - firstShare},
+	})
+}
+
+// loadDamages sets the damages of the asset to its open damages. Damages are kept under their own
+// keys so reporting one does not rewrite the asset; only damages reported before they had their own
+// keys are still stored on the asset.
+func loadDamages(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(damageObjectType, []string{asset.ID})
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	keyed := map[string]bool{}
+	var open []Damage
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		var damage Damage
+		err = json.Unmarshal(queryResponse.Value, &damage)
+		if err != nil {
+			return err
+		}
+		keyed[damage.ID] = true
+		if damage.Status == DamageOpen {
+			open = append(open, damage)
+		}
+	}
+
+	var damages []Damage
+	for _, damage := range asset.Damages {
+		if damage.ID == "" || !keyed[damage.ID] {
+			damages = append(damages, damage)
+		}
+	}
+	asset.Damages = append(damages, open...)
+	return nil
+}
+
+// unkeyedDamages returns the damages of the asset that have no key of their own
+func unkeyedDamages(damages []Damage) []Damage {
+	var unkeyed []Damage
+	for _, damage := range damages {
+		if damage.ID == "" {
+			unkeyed = append(unkeyed, damage)
+		}
+	}
+
+	return unkeyed
+}
+
+// ReadDamage returns the damage with given ID reported on asset with given ID
+func (s *SmartContract) ReadDamage(ctx contractapi.TransactionContextInterface, assetID string, damageID string) (*Damage, error) {
+	damageKey, err := ctx.GetStub().CreateCompositeKey(damageObjectType, []string{assetID, damageID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	damageJSON, err := ctx.GetStub().GetState(damageKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if damageJSON == nil {
+		return nil, fmt.Errorf("the damage %s does not exist on asset %s", damageID, assetID)
+	}
+
+	var damage Damage
+	err = json.Unmarshal(damageJSON, &damage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &damage, nil
+}
+
+// markDamageRepaired records that the mechanic repaired the damage
+func markDamageRepaired(ctx contractapi.TransactionContextInterface, damage Damage, mechanic string) error {
+	if damage.ID == "" {
+		// damages reported before they had their own keys are only kept on the asset
+		return nil
+	}
+	damage.Status = DamageRepaired
+	damage.RepairedBy = mechanic
+	return putDamage(ctx, &damage)
+}
+
+// putDamage writes the damage under its asset~damage composite key, recording the submitting client as its last editor
+func putDamage(ctx contractapi.TransactionContextInterface, damage *Damage) error {
+	err := stampUpdated(ctx, &damage.Audit)
+	if err != nil {
+		return err
+	}
+	damageKey, err := ctx.GetStub().CreateCompositeKey(damageObjectType, []string{damage.AssetID, damage.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	damageJSON, err := json.Marshal(damage)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(damageKey, damageJSON)
+}
+
+// checkAssetAtRisk emits an AssetAtRisk event when the new damage pushed the cost of the open
+// damages of the asset over the alert threshold
+func checkAssetAtRisk(ctx contractapi.TransactionContextInterface, asset *Asset, value int64, previousCost int64) error {
+	threshold, err := getConfigInt(ctx, damageAlertThresholdConfig, defaultDamageAlertThreshold)
+	if err != nil {
+		return err
+	}
+	limit := value * threshold / 10000
+	cost := damagesCost(asset.Damages)
+	if previousCost > limit || cost <= limit {
+		return nil
+	}
+
+	eventJSON, err := json.Marshal(AssetAtRiskEvent{
+		AssetID:        asset.ID,
+		OwnerID:        asset.OwnerID,
+		DamageCost:     cost,
+		AppraisedValue: asset.AppraisedValue,
+		Threshold:      threshold,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent("AssetAtRisk", eventJSON)
+}
+
+// damagesCost returns the total repair cost of the damages
+func damagesCost(damages []Damage) int64 {
+	totalCost := int64(0)
+	for _, damage := range damages {
+		totalCost = totalCost + damage.Cost
+	}
+
+	return totalCost
+}
@@ -0,0 +1,105 @@
+package chaincode_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAssetDamage(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	transactionContext.GetClientIdentity().(*mocks.ClientIdentity).GetIDReturns("reporter", nil)
+	chaincodeStub.GetTxIDReturns("damage1")
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 400)
+	require.NoError(t, err)
+
+	expectedDamage := chaincode.Damage{
+		ID:          "damage1",
+		AssetID:     "asset1",
+		Description: "tyre",
+		Cost:        400,
+		Status:      chaincode.DamageOpen,
+		ReporterID:  "reporter",
+		ReportedAt:  time.Unix(1600000000, 0).UTC(),
+		Audit: chaincode.Audit{
+			CreatedBy: "reporter",
+			CreatedAt: time.Unix(1600000000, 0).UTC(),
+			UpdatedBy: "reporter",
+			UpdatedAt: time.Unix(1600000000, 0).UTC(),
+		},
+	}
+	damage, err := assetTransfer.ReadDamage(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, &expectedDamage, damage)
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []chaincode.Damage{expectedDamage}, asset.Damages)
+
+	chaincodeStub.GetTxIDReturns("damage2")
+	err = assetTransfer.CreateAssetDamage(transactionContext, "asset1", "engine", 2700)
+	require.NoError(t, err)
+	asset, err = assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AssetTotaled, asset.Status)
+	require.Len(t, asset.Damages, 2)
+}
+
+func TestCreateAssetDamageRestricted(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute not found"))
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("stranger", nil)
+	err := assetTransfer.CreateAssetDamage(transactionContext, "asset1", "engine", 5000)
+	require.EqualError(t, err, "only admins, mechanics and the owner can report damages to asset asset1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.CreateAssetDamage(transactionContext, "asset1", "engine", 0)
+	require.EqualError(t, err, "damage cost must be positive")
+	err = assetTransfer.CreateAssetDamage(transactionContext, "asset1", "engine", -5000)
+	require.EqualError(t, err, "damage cost must be positive")
+	err = assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 400)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("stranger", nil)
+	clientIdentity.AssertAttributeValueStub = func(name string, value string) error {
+		if name == "role" && value == chaincode.RoleMechanic {
+			return nil
+		}
+		return fmt.Errorf("attribute %s not found", name)
+	}
+	err = assetTransfer.CreateAssetDamage(transactionContext, "asset1", "mirror", 100)
+	require.NoError(t, err)
+}
+
+func TestAssetAtRiskEvent(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.SetDamageAlertThreshold(transactionContext, 5000))
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 1000))
+	require.Equal(t, 0, chaincodeStub.SetEventCallCount())
+
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "door", 800))
+	require.Equal(t, 1, chaincodeStub.SetEventCallCount())
+	name, payload := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "AssetAtRisk", name)
+	require.JSONEq(t, `{"assetID":"asset1","ownerID":"user1","damageCost":1800,"appraisedValue":3000,"threshold":5000}`, string(payload))
+
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "mirror", 100))
+	require.Equal(t, 1, chaincodeStub.SetEventCallCount())
+}
@@ -0,0 +1,79 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ApproveTransferDelegate lets the owner approve the user with given ID to transfer the asset on
+// their behalf. An asset has at most one delegate, who loses the approval when the asset is sold.
+func (s *SmartContract) ApproveTransferDelegate(ctx contractapi.TransactionContextInterface, assetID string, delegateID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if delegateID == asset.OwnerID {
+		return fmt.Errorf("the owner cannot be their own delegate")
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	delegate, err := s.ReadUser(ctx, delegateID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(delegate)
+	if err != nil {
+		return err
+	}
+
+	asset.Delegate = delegate.ID
+	return putAsset(ctx, asset)
+}
+
+// RevokeTransferDelegate lets the owner withdraw the approval given to the delegate of the asset
+func (s *SmartContract) RevokeTransferDelegate(ctx contractapi.TransactionContextInterface, assetID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if asset.Delegate == "" {
+		return fmt.Errorf("the asset %s has no delegate", assetID)
+	}
+
+	asset.Delegate = ""
+	return putAsset(ctx, asset)
+}
+
+// verifyOwnerOrDelegate returns an error unless the transaction was submitted by the client bound to
+// the owner of the asset or to its approved delegate
+func (s *SmartContract) verifyOwnerOrDelegate(ctx contractapi.TransactionContextInterface, asset *Asset, owner *User) error {
+	if verifyUserIdentity(ctx, owner) == nil {
+		return nil
+	}
+	if asset.Delegate != "" {
+		delegate, err := s.ReadUser(ctx, asset.Delegate)
+		if err != nil {
+			return err
+		}
+		if verifyUserIdentity(ctx, delegate) == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("submitting client is not authorized to transfer asset %s", asset.ID)
+}
@@ -0,0 +1,59 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferDelegate(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100, Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "dealer"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("dealer", nil)
+	err := assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2800)
+	require.EqualError(t, err, "submitting client is not authorized to transfer asset asset1")
+	err = assetTransfer.ApproveTransferDelegate(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.ApproveTransferDelegate(transactionContext, "asset1", "user1")
+	require.EqualError(t, err, "the owner cannot be their own delegate")
+	err = assetTransfer.ApproveTransferDelegate(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("dealer", nil)
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2800)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Empty(t, asset.Delegate)
+}
+
+func TestRevokeTransferDelegate(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "dealer"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000, Delegate: "user2"})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.RevokeTransferDelegate(transactionContext, "asset1")
+	require.NoError(t, err)
+	err = assetTransfer.RevokeTransferDelegate(transactionContext, "asset1")
+	require.EqualError(t, err, "the asset asset1 has no delegate")
+
+	clientIdentity.GetIDReturns("dealer", nil)
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2800)
+	require.EqualError(t, err, "submitting client is not authorized to transfer asset asset1")
+}
@@ -0,0 +1,110 @@
+package chaincode
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// SetBidDeposit lets the seller require every bidder to lock amount from their balance with their
+// first bid. The winner's deposit counts toward the price and the others are refunded when the
+// auction ends, except for sealed bids that were never revealed, whose deposits go to the seller.
+// The deposit can only be changed before the first bid.
+func (s *SmartContract) SetBidDeposit(ctx contractapi.TransactionContextInterface, auctionID string, amount int64) error {
+	if amount < 0 {
+		return fmt.Errorf("deposit must not be negative")
+	}
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	if auction.Type == AuctionDutch {
+		return fmt.Errorf("dutch auctions take no bids")
+	}
+	err = checkAuctionStatus(auction, auction.Type, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyAuctionSeller(ctx, auction)
+	if err != nil {
+		return err
+	}
+	if len(auction.SealedBids) > 0 || auction.HighBidderID != "" {
+		return fmt.Errorf("the deposit of auction %s cannot change after the first bid", auctionID)
+	}
+
+	auction.Deposit = amount
+	return putAuction(ctx, auction)
+}
+
+// lockBidDeposit takes the deposit of the auction from the bidder's balance unless they already
+// locked it. The caller stores the bidder and the auction.
+func lockBidDeposit(auction *Auction, bidder *User) error {
+	if auction.Deposit == 0 || bidDeposit(auction, bidder.ID) > 0 {
+		return nil
+	}
+	if bidder.Money < auction.Deposit {
+		return fmt.Errorf("user %s doesn't have enough money for the bid deposit", bidder.ID)
+	}
+
+	bidder.Money = bidder.Money - auction.Deposit
+	auction.Deposits = append(auction.Deposits, BidDeposit{BidderID: bidder.ID, Amount: auction.Deposit})
+	return nil
+}
+
+// bidDeposit returns the deposit the bidder locked on the auction
+func bidDeposit(auction *Auction, bidderID string) int64 {
+	for _, deposit := range auction.Deposits {
+		if deposit.BidderID == bidderID {
+			return deposit.Amount
+		}
+	}
+
+	return 0
+}
+
+// payBidders credits the amounts to the bidders. Users already loaded by the caller are credited in
+// memory and must be stored by the caller, the others are read and stored here. Every account is
+// written once, since reads within a transaction do not see its own writes.
+func (s *SmartContract) payBidders(ctx contractapi.TransactionContextInterface, credits map[string]int64, loaded ...*User) error {
+	bidderIDs := make([]string, 0, len(credits))
+	for bidderID := range credits {
+		bidderIDs = append(bidderIDs, bidderID)
+	}
+	sort.Strings(bidderIDs)
+
+	for _, bidderID := range bidderIDs {
+		var bidder *User
+		for _, user := range loaded {
+			if user != nil && user.ID == bidderID {
+				bidder = user
+			}
+		}
+		if bidder != nil {
+			bidder.Money = bidder.Money + credits[bidderID]
+			continue
+		}
+		bidder, err := s.ReadUser(ctx, bidderID)
+		if err != nil {
+			return err
+		}
+		bidder.Money = bidder.Money + credits[bidderID]
+		err = putUser(ctx, bidder)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// depositCredits returns the deposits of the auction keyed by bidder
+func depositCredits(auction *Auction) map[string]int64 {
+	credits := map[string]int64{}
+	for _, deposit := range auction.Deposits {
+		credits[deposit.BidderID] = credits[deposit.BidderID] + deposit.Amount
+	}
+
+	return credits
+}
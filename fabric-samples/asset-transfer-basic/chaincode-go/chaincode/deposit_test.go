@@ -0,0 +1,126 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealedAuctionDeposits(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "bidder3", Money: 5000})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Identity: "bidder4", Money: 5000})
+	state.put(t, "user5", &chaincode.User{ID: "user5", Identity: "bidder5", Money: 50})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.CreateSealedAuction(transactionContext, "asset1")
+	require.NoError(t, err)
+	err = assetTransfer.SetBidDeposit(transactionContext, auctionID, 500)
+	require.NoError(t, err)
+
+	bids := map[string][]byte{
+		"user2": []byte(`{"auctionID":"auction1","bidderID":"user2","price":4000,"salt":"aa"}`),
+		"user3": []byte(`{"auctionID":"auction1","bidderID":"user3","price":3000,"salt":"bb"}`),
+		"user4": []byte(`{"auctionID":"auction1","bidderID":"user4","price":4500,"salt":"cc"}`),
+		"user5": []byte(`{"auctionID":"auction1","bidderID":"user5","price":100,"salt":"dd"}`),
+	}
+	for _, bidderID := range []string{"user2", "user3", "user4", "user5"} {
+		clientIdentity.GetIDReturns("bidder"+bidderID[4:], nil)
+		chaincodeStub.GetTxIDReturns("bid" + bidderID[4:])
+		chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bids[bidderID]}, nil)
+		_, err = assetTransfer.SubmitSealedBid(transactionContext, auctionID)
+		if bidderID == "user5" {
+			require.EqualError(t, err, "user user5 doesn't have enough money for the bid deposit")
+			continue
+		}
+		require.NoError(t, err)
+	}
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(4500), user.Money)
+
+	clientIdentity.GetIDReturns("seller", nil)
+	err = assetTransfer.SetBidDeposit(transactionContext, auctionID, 100)
+	require.EqualError(t, err, "the deposit of auction auction1 cannot change after the first bid")
+	err = assetTransfer.CloseAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+
+	// user4 never reveals the highest bid
+	for _, bidderID := range []string{"user2", "user3"} {
+		clientIdentity.GetIDReturns("bidder"+bidderID[4:], nil)
+		chaincodeStub.GetTransientReturns(map[string][]byte{"bid": bids[bidderID]}, nil)
+		err = assetTransfer.RevealSealedBid(transactionContext, auctionID, "bid"+bidderID[4:])
+		require.NoError(t, err)
+	}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("end")
+	err = assetTransfer.EndAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+
+	// the winner's deposit counts toward the price, the revealed loser is refunded and the
+	// unrevealed bidder's deposit goes to the seller
+	state.get(t, "user2", user)
+	require.Equal(t, int64(1000), user.Money)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(5000), user.Money)
+	state.get(t, "user4", user)
+	require.Equal(t, int64(4500), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(4500), user.Money)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+}
+
+func TestEnglishAuctionDeposits(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "bidder3", Money: 9000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.StartAuction(transactionContext, "asset1", 1000, 3600)
+	require.NoError(t, err)
+	err = assetTransfer.SetBidDeposit(transactionContext, auctionID, 200)
+	require.NoError(t, err)
+	err = assetTransfer.SetBuyNowPrice(transactionContext, auctionID, 7000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("bidder2", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 4900)
+	require.EqualError(t, err, "user user2 doesn't have enough money on his account")
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 3000)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(1800), user.Money)
+
+	clientIdentity.GetIDReturns("bidder3", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user3", 3500)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("buynow")
+	err = assetTransfer.BuyNow(transactionContext, auctionID, "user3")
+	require.NoError(t, err)
+
+	state.get(t, "user2", user)
+	require.Equal(t, int64(5000), user.Money)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(2000), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(7000), user.Money)
+}
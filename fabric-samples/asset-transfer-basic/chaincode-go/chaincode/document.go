@@ -0,0 +1,221 @@
+package chaincode
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	documentIndexName           = "record~document"
+	notarizedDocumentObjectType = "asset~notarized"
+)
+
+// AttachDamageDocument records the SHA-256 hash of an off-chain photo or report of the damage.
+// Only the owner of the asset or whoever reported the damage may attach documents.
+func (s *SmartContract) AttachDamageDocument(ctx contractapi.TransactionContextInterface, assetID string, damageID string, hash string) error {
+	hash, err := normalizeDocumentHash(hash)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	damage, err := s.ReadDamage(ctx, assetID, damageID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	if clientID != owner.Identity && clientID != damage.ReporterID {
+		return fmt.Errorf("only the owner or the reporter can attach documents to damage %s", damageID)
+	}
+	if contains(damage.DocumentHashes, hash) {
+		return fmt.Errorf("the document %s is already attached to damage %s", hash, damageID)
+	}
+
+	damage.DocumentHashes = append(damage.DocumentHashes, hash)
+	err = putDamage(ctx, damage)
+	if err != nil {
+		return err
+	}
+
+	return putDocumentIndex(ctx, damageID, hash)
+}
+
+// AttachRepairDocument records the SHA-256 hash of an off-chain report of the repair job.
+// Only the owner or the mechanic of the job may attach documents.
+func (s *SmartContract) AttachRepairDocument(ctx contractapi.TransactionContextInterface, jobID string, hash string) error {
+	hash, err := normalizeDocumentHash(hash)
+	if err != nil {
+		return err
+	}
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	_, ownerErr := s.readRepairOwner(ctx, job)
+	if ownerErr != nil {
+		if job.MechanicID == "" {
+			return ownerErr
+		}
+		_, err = s.readMechanic(ctx, job.MechanicID)
+		if err != nil {
+			return fmt.Errorf("only the owner or the mechanic can attach documents to repair job %s", jobID)
+		}
+	}
+	if contains(job.DocumentHashes, hash) {
+		return fmt.Errorf("the document %s is already attached to repair job %s", hash, jobID)
+	}
+
+	job.DocumentHashes = append(job.DocumentHashes, hash)
+	err = writeRepairJob(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	return putDocumentIndex(ctx, jobID, hash)
+}
+
+// VerifyDocumentHash reports whether a document with given SHA-256 hash was attached to the
+// damage or repair job with given ID.
+func (s *SmartContract) VerifyDocumentHash(ctx contractapi.TransactionContextInterface, id string, hash string) (bool, error) {
+	hash, err := normalizeDocumentHash(hash)
+	if err != nil {
+		return false, err
+	}
+	indexKey, err := ctx.GetStub().CreateCompositeKey(documentIndexName, []string{id, hash})
+	if err != nil {
+		return false, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	value, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to read from world state: %v", err)
+	}
+
+	return value != nil, nil
+}
+
+// NotarizeDocument lets the owner anchor an off-chain document of given type to the record of the
+// asset by its hex encoded SHA-256 hash
+func (s *SmartContract) NotarizeDocument(ctx contractapi.TransactionContextInterface, assetID string, docType string, hash string) error {
+	if docType == "" {
+		return fmt.Errorf("document type must not be empty")
+	}
+	hash, err := normalizeDocumentHash(hash)
+	if err != nil {
+		return err
+	}
+	_, err = s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	documentKey, err := ctx.GetStub().CreateCompositeKey(notarizedDocumentObjectType, []string{assetID, hash})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	existing, err := ctx.GetStub().GetState(documentKey)
+	if err != nil {
+		return fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("the document %s is already notarized for asset %s", hash, assetID)
+	}
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	document := NotarizedDocument{
+		AssetID:     assetID,
+		Hash:        hash,
+		DocType:     docType,
+		TxID:        ctx.GetStub().GetTxID(),
+		NotarizedBy: clientID,
+		NotarizedAt: now,
+	}
+	documentJSON, err := json.Marshal(document)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(documentKey, documentJSON)
+}
+
+// VerifyDocument reports whether a document with given SHA-256 hash was notarized for the asset
+func (s *SmartContract) VerifyDocument(ctx contractapi.TransactionContextInterface, assetID string, hash string) (bool, error) {
+	hash, err := normalizeDocumentHash(hash)
+	if err != nil {
+		return false, err
+	}
+	documentKey, err := ctx.GetStub().CreateCompositeKey(notarizedDocumentObjectType, []string{assetID, hash})
+	if err != nil {
+		return false, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	documentJSON, err := ctx.GetStub().GetState(documentKey)
+	if err != nil {
+		return false, fmt.Errorf("failed to read from world state: %v", err)
+	}
+
+	return documentJSON != nil, nil
+}
+
+// GetNotarizedDocuments returns all documents notarized for the asset
+func (s *SmartContract) GetNotarizedDocuments(ctx contractapi.TransactionContextInterface, assetID string) ([]*NotarizedDocument, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(notarizedDocumentObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var documents []*NotarizedDocument
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var document NotarizedDocument
+		err = json.Unmarshal(queryResponse.Value, &document)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, &document)
+	}
+
+	return documents, nil
+}
+
+// normalizeDocumentHash checks that hash is a hex encoded SHA-256 digest and returns it in lower case
+func normalizeDocumentHash(hash string) (string, error) {
+	decoded, err := hex.DecodeString(hash)
+	if err != nil || len(decoded) != 32 {
+		return "", fmt.Errorf("%s is not a hex encoded SHA-256 hash", hash)
+	}
+
+	return strings.ToLower(hash), nil
+}
+
+// putDocumentIndex records that the document with given hash is attached to the record with given ID
+func putDocumentIndex(ctx contractapi.TransactionContextInterface, id string, hash string) error {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(documentIndexName, []string{id, hash})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte{0x00})
+}
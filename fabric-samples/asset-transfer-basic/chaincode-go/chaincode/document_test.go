@@ -0,0 +1,112 @@
+package chaincode_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDamageDocuments(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("reporter", nil)
+	chaincodeStub.GetTxIDReturns("damage1")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 400))
+
+	photo := sha256.Sum256([]byte("photo of the tyre"))
+	hash := hex.EncodeToString(photo[:])
+	err := assetTransfer.AttachDamageDocument(transactionContext, "asset1", "damage1", "not a hash")
+	require.EqualError(t, err, "not a hash is not a hex encoded SHA-256 hash")
+
+	clientIdentity.GetIDReturns("someone", nil)
+	err = assetTransfer.AttachDamageDocument(transactionContext, "asset1", "damage1", hash)
+	require.EqualError(t, err, "only the owner or the reporter can attach documents to damage damage1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.AttachDamageDocument(transactionContext, "asset1", "damage1", strings.ToUpper(hash))
+	require.NoError(t, err)
+	damage, err := assetTransfer.ReadDamage(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, []string{hash}, damage.DocumentHashes)
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []string{hash}, asset.Damages[0].DocumentHashes)
+
+	verified, err := assetTransfer.VerifyDocumentHash(transactionContext, "damage1", hash)
+	require.NoError(t, err)
+	require.True(t, verified)
+	other := sha256.Sum256([]byte("another photo"))
+	verified, err = assetTransfer.VerifyDocumentHash(transactionContext, "damage1", hex.EncodeToString(other[:]))
+	require.NoError(t, err)
+	require.False(t, verified)
+}
+
+func TestRepairDocuments(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("mechanic", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "mechanic", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", OwnerID: "user1", MechanicID: "user3", Status: chaincode.RepairCompleted})
+
+	report := sha256.Sum256([]byte("repair report"))
+	hash := hex.EncodeToString(report[:])
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.AttachRepairDocument(transactionContext, "job1", hash)
+	require.NoError(t, err)
+	job, err := assetTransfer.ReadRepairJob(transactionContext, "job1")
+	require.NoError(t, err)
+	require.Equal(t, []string{hash}, job.DocumentHashes)
+
+	verified, err := assetTransfer.VerifyDocumentHash(transactionContext, "job1", hash)
+	require.NoError(t, err)
+	require.True(t, verified)
+}
+
+func TestNotarizeDocument(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	contract := sha256.Sum256([]byte("purchase agreement"))
+	hash := hex.EncodeToString(contract[:])
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("someone", nil)
+	err := assetTransfer.NotarizeDocument(transactionContext, "asset1", "purchase agreement", hash)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	chaincodeStub.GetTxIDReturns("tx1")
+	err = assetTransfer.NotarizeDocument(transactionContext, "asset1", "purchase agreement", hash)
+	require.NoError(t, err)
+	err = assetTransfer.NotarizeDocument(transactionContext, "asset1", "photo", strings.ToUpper(hash))
+	require.EqualError(t, err, "the document "+hash+" is already notarized for asset asset1")
+
+	verified, err := assetTransfer.VerifyDocument(transactionContext, "asset1", hash)
+	require.NoError(t, err)
+	require.True(t, verified)
+	verified, err = assetTransfer.VerifyDocument(transactionContext, "asset2", hash)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	documents, err := assetTransfer.GetNotarizedDocuments(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, documents, 1)
+	require.Equal(t, "purchase agreement", documents[0].DocType)
+	require.Equal(t, "owner", documents[0].NotarizedBy)
+	require.Equal(t, "tx1", documents[0].TxID)
+}
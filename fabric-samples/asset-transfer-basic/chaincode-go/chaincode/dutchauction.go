@@ -0,0 +1,114 @@
+package chaincode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// StartDutchAuction lets the owner put the asset up for a dutch auction. The asking price starts at
+// startPrice and drops by priceDrop every dropIntervalSeconds until it reaches floorPrice; the first
+// buyer to call AcceptCurrentPrice wins. It returns the ID of the new auction.
+func (s *SmartContract) StartDutchAuction(ctx contractapi.TransactionContextInterface, assetID string, startPrice int64, floorPrice int64, priceDrop int64, dropIntervalSeconds int64) (string, error) {
+	if floorPrice < 0 || startPrice < floorPrice {
+		return "", fmt.Errorf("start price must not be below the floor price and floor price must not be negative")
+	}
+	if priceDrop <= 0 || dropIntervalSeconds <= 0 {
+		return "", fmt.Errorf("price drop and drop interval must be positive")
+	}
+	auction, err := s.newAuction(ctx, assetID, AuctionDutch)
+	if err != nil {
+		return "", err
+	}
+
+	auction.StartPrice = startPrice
+	auction.FloorPrice = floorPrice
+	auction.PriceDrop = priceDrop
+	auction.DropInterval = dropIntervalSeconds
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return "", err
+	}
+
+	return auction.ID, nil
+}
+
+// GetCurrentPrice returns the asking price of an open dutch auction at the time of the transaction, in cents.
+func (s *SmartContract) GetCurrentPrice(ctx contractapi.TransactionContextInterface, auctionID string) (int64, error) {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return 0, err
+	}
+	err = checkAuctionStatus(auction, AuctionDutch, AuctionOpen)
+	if err != nil {
+		return 0, err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return dutchPrice(auction, now), nil
+}
+
+// AcceptCurrentPrice buys the asset of an open dutch auction at the current asking price, ending the auction.
+func (s *SmartContract) AcceptCurrentPrice(ctx contractapi.TransactionContextInterface, auctionID string, buyerID string) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	err = checkAuctionStatus(auction, AuctionDutch, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	if buyerID == auction.SellerID {
+		return fmt.Errorf("the seller cannot bid on their own auction")
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, auction.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, auction.AssetID)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	auction.Status = AuctionEnded
+	auction.WinnerID = buyerID
+	auction.Price = dutchPrice(auction, now)
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return err
+	}
+	asset.AuctionID = ""
+
+	return executeSale(ctx, asset, seller, buyer, auction.Price)
+}
+
+// dutchPrice returns the asking price of the dutch auction at given time. It only depends on the
+// transaction timestamp so that all endorsing peers compute the same price.
+func dutchPrice(auction *Auction, now time.Time) int64 {
+	elapsed := int64(now.Sub(auction.CreatedAt) / time.Second)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	price := auction.StartPrice - auction.PriceDrop*(elapsed/auction.DropInterval)
+	if price < auction.FloorPrice {
+		return auction.FloorPrice
+	}
+
+	return price
+}
@@ -0,0 +1,55 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDutchAuction(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer", Money: 10000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	_, err := assetTransfer.StartDutchAuction(transactionContext, "asset1", 1000, 2000, 100, 60)
+	require.EqualError(t, err, "start price must not be below the floor price and floor price must not be negative")
+	auctionID, err := assetTransfer.StartDutchAuction(transactionContext, "asset1", 9000, 6000, 500, 60)
+	require.NoError(t, err)
+
+	price, err := assetTransfer.GetCurrentPrice(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, int64(9000), price)
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 150}, nil)
+	price, err = assetTransfer.GetCurrentPrice(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, int64(8000), price)
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 3600}, nil)
+	price, err = assetTransfer.GetCurrentPrice(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, int64(6000), price)
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 150}, nil)
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("accept")
+	err = assetTransfer.AcceptCurrentPrice(transactionContext, auctionID, "user2")
+	require.NoError(t, err)
+	auction, err := assetTransfer.ReadAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AuctionEnded, auction.Status)
+	require.Equal(t, int64(8000), auction.Price)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(2000), user.Money)
+
+	err = assetTransfer.AcceptCurrentPrice(transactionContext, auctionID, "user2")
+	require.EqualError(t, err, "the auction auction1 is ended, expected open")
+}
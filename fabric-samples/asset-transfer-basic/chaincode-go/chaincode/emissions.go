@@ -0,0 +1,148 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const emissionsTestObjectType = "emissionsTest"
+
+// RecordEmissionsTest lets a certified station record an emissions test of the asset. A passed test
+// keeps the car compliant for validDays days; a failed one makes it non-compliant right away.
+// It returns the ID of the new test.
+func (s *SmartContract) RecordEmissionsTest(ctx contractapi.TransactionContextInterface, assetID string, stationID string, co2 int64, passed bool, validDays int) (string, error) {
+	if co2 < 0 {
+		return "", fmt.Errorf("emissions must not be negative")
+	}
+	if passed && validDays <= 0 {
+		return "", fmt.Errorf("validity must be positive")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	station, err := s.ReadUser(ctx, stationID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, station)
+	if err != nil {
+		return "", err
+	}
+	err = requireRole(station, RoleEmissionsStation)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	test := EmissionsTest{
+		ID:         ctx.GetStub().GetTxID(),
+		AssetID:    assetID,
+		StationID:  stationID,
+		CO2:        co2,
+		Passed:     passed,
+		TestedAt:   now,
+		ValidUntil: now,
+	}
+	if passed {
+		test.ValidUntil = now.AddDate(0, 0, validDays)
+	}
+	err = putEmissionsTest(ctx, &test)
+	if err != nil {
+		return "", err
+	}
+
+	asset.EmissionsTestID = test.ID
+	asset.EmissionsValidUntil = test.ValidUntil
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return test.ID, nil
+}
+
+// ReadEmissionsTest returns the emissions test stored in the world state with given id
+func (s *SmartContract) ReadEmissionsTest(ctx contractapi.TransactionContextInterface, testID string) (*EmissionsTest, error) {
+	testKey, err := ctx.GetStub().CreateCompositeKey(emissionsTestObjectType, []string{testID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	testJSON, err := ctx.GetStub().GetState(testKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if testJSON == nil {
+		return nil, fmt.Errorf("the emissions test %s does not exist", testID)
+	}
+
+	var test EmissionsTest
+	err = json.Unmarshal(testJSON, &test)
+	if err != nil {
+		return nil, err
+	}
+
+	return &test, nil
+}
+
+// IsEmissionsCompliant returns true when the asset passed an emissions test that is still valid at
+// the time of the transaction
+func (s *SmartContract) IsEmissionsCompliant(ctx contractapi.TransactionContextInterface, assetID string) (bool, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return false, err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return emissionsCompliant(asset, now), nil
+}
+
+// GetNonCompliantAssets returns the assets of the owner that were never tested or whose last
+// emissions test failed or expired. An empty ownerID returns those of every owner.
+func (s *SmartContract) GetNonCompliantAssets(ctx contractapi.TransactionContextInterface, ownerID string) ([]*Asset, error) {
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.FindAssets(ctx, "", ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	var nonCompliant []*Asset
+	for _, asset := range assets {
+		if !emissionsCompliant(asset, now) {
+			nonCompliant = append(nonCompliant, asset)
+		}
+	}
+
+	return nonCompliant, nil
+}
+
+// emissionsCompliant returns true when the last emissions test of the asset is valid at given time
+func emissionsCompliant(asset *Asset, now time.Time) bool {
+	return asset.EmissionsValidUntil.After(now)
+}
+
+// putEmissionsTest writes the given emissions test to the world state
+func putEmissionsTest(ctx contractapi.TransactionContextInterface, test *EmissionsTest) error {
+	testKey, err := ctx.GetStub().CreateCompositeKey(emissionsTestObjectType, []string{test.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	testJSON, err := json.Marshal(test)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(testKey, testJSON)
+}
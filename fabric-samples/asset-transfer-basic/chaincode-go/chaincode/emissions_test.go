@@ -0,0 +1,51 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmissionsCompliance(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleEmissionsStation}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1"})
+	state.put(t, "asset3", &chaincode.Asset{ID: "asset3", OwnerID: "user1"})
+	state.put(t, "asset4", &chaincode.Asset{ID: "asset4", OwnerID: "user2"})
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.RecordEmissionsTest(transactionContext, "asset1", "user1", 120, true, 365)
+	require.EqualError(t, err, "the user user1 does not have role emissionsStation")
+	chaincodeStub.GetTxIDReturns("test1")
+	_, err = assetTransfer.RecordEmissionsTest(transactionContext, "asset1", "user2", 120, true, 365)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("test2")
+	_, err = assetTransfer.RecordEmissionsTest(transactionContext, "asset2", "user2", 130, true, 30)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("test3")
+	testID, err := assetTransfer.RecordEmissionsTest(transactionContext, "asset3", "user2", 310, false, 0)
+	require.NoError(t, err)
+	test, err := assetTransfer.ReadEmissionsTest(transactionContext, testID)
+	require.NoError(t, err)
+	require.False(t, test.Passed)
+	require.Equal(t, int64(310), test.CO2)
+
+	compliant, err := assetTransfer.IsEmissionsCompliant(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.True(t, compliant)
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 60*86400}, nil)
+	compliant, err = assetTransfer.IsEmissionsCompliant(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.False(t, compliant)
+	assets, err := assetTransfer.GetNonCompliantAssets(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Len(t, assets, 2)
+	require.Equal(t, []string{"asset2", "asset3"}, []string{assets[0].ID, assets[1].ID})
+}
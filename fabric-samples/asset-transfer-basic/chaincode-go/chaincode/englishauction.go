@@ -0,0 +1,187 @@
+package chaincode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// StartAuction lets the owner put the asset up for an english auction lasting durationSeconds.
+// Bids must reach reservePrice and beat the current high bid. It returns the ID of the new auction.
+func (s *SmartContract) StartAuction(ctx contractapi.TransactionContextInterface, assetID string, reservePrice int64, durationSeconds int64) (string, error) {
+	if reservePrice < 0 {
+		return "", fmt.Errorf("reserve price must not be negative")
+	}
+	if durationSeconds <= 0 {
+		return "", fmt.Errorf("duration must be positive")
+	}
+	auction, err := s.newAuction(ctx, assetID, AuctionEnglish)
+	if err != nil {
+		return "", err
+	}
+
+	auction.ReservePrice = reservePrice
+	auction.EndsAt = auction.CreatedAt.Add(time.Duration(durationSeconds) * time.Second)
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return "", err
+	}
+
+	return auction.ID, nil
+}
+
+// PlaceBid bids amount on an open english auction. The amount is held from the bidder's balance
+// and the hold of the previous high bidder is released.
+func (s *SmartContract) PlaceBid(ctx contractapi.TransactionContextInterface, auctionID string, bidderID string, amount int64) error {
+	auction, err := s.ReadAuction(ctx, auctionID)
+	if err != nil {
+		return err
+	}
+	err = checkAuctionStatus(auction, AuctionEnglish, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if now.After(auction.EndsAt) {
+		return fmt.Errorf("the auction %s ended at %s", auctionID, auction.EndsAt.Format(time.RFC3339))
+	}
+	if amount < auction.ReservePrice {
+		return fmt.Errorf("the bid must be at least the reserve price %d", auction.ReservePrice)
+	}
+	if amount <= auction.HighBid {
+		return fmt.Errorf("the bid must be higher than the current bid %d", auction.HighBid)
+	}
+	if bidderID == auction.SellerID {
+		return fmt.Errorf("the seller cannot bid on their own auction")
+	}
+	bidder, err := s.ReadUser(ctx, bidderID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, bidder)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(bidder)
+	if err != nil {
+		return err
+	}
+
+	if auction.HighBidderID == bidderID {
+		bidder.Money = bidder.Money + auction.HighBid
+	} else if auction.HighBidderID != "" {
+		err = s.releaseBidHold(ctx, auction.HighBidderID, auction.HighBid)
+		if err != nil {
+			return err
+		}
+	}
+	err = lockBidDeposit(auction, bidder)
+	if err != nil {
+		return err
+	}
+	if bidder.Money < amount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", bidderID)
+	}
+	bidder.Money = bidder.Money - amount
+	err = putUser(ctx, bidder)
+	if err != nil {
+		return err
+	}
+
+	auction.HighBidderID = bidderID
+	auction.HighBid = amount
+	return putAuction(ctx, auction)
+}
+
+// closeEnglishAuction ends the auction after its deadline and sells the asset to the high bidder.
+// Without bids the seller keeps the asset.
+func (s *SmartContract) closeEnglishAuction(ctx contractapi.TransactionContextInterface, auction *Auction) error {
+	err := checkAuctionStatus(auction, AuctionEnglish, AuctionOpen)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if !now.After(auction.EndsAt) {
+		return fmt.Errorf("the auction %s is open until %s", auction.ID, auction.EndsAt.Format(time.RFC3339))
+	}
+
+	if auction.HighBidderID == "" {
+		err = s.payBidders(ctx, depositCredits(auction))
+		if err != nil {
+			return err
+		}
+		asset, err := s.ReadAsset(ctx, auction.AssetID)
+		if err != nil {
+			return err
+		}
+		asset.AuctionID = ""
+		err = putAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+		auction.Status = AuctionEnded
+		return putAuction(ctx, auction)
+	}
+	auction.WinnerID = auction.HighBidderID
+	auction.Price = auction.HighBid
+
+	return s.settleAuction(ctx, auction)
+}
+
+// settleAuction sells the asset to the winner of the auction, paying with the bid held from their
+// balance, and refunds the bid deposits. When the sale cannot be settled the auction fails, the held
+// bid goes back to the winner and the seller keeps the asset.
+func (s *SmartContract) settleAuction(ctx contractapi.TransactionContextInterface, auction *Auction) error {
+	seller, err := s.ReadUser(ctx, auction.SellerID)
+	if err != nil {
+		return err
+	}
+	winner, err := s.ReadUser(ctx, auction.WinnerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, auction.AssetID)
+	if err != nil {
+		return err
+	}
+
+	// the held bid and the deposits go back to the bidders' balances, the winner's are paid out by
+	// the regular sale settlement
+	credits := depositCredits(auction)
+	credits[winner.ID] = credits[winner.ID] + auction.Price
+	err = s.payBidders(ctx, credits, winner)
+	if err != nil {
+		return err
+	}
+
+	asset.AuctionID = ""
+	err = checkSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: winner, price: auction.Price, upfront: auction.Price})
+	if err != nil {
+		return failAuction(ctx, auction, asset, err, winner)
+	}
+	auction.Status = AuctionEnded
+	err = putAuction(ctx, auction)
+	if err != nil {
+		return err
+	}
+
+	return executeSale(ctx, asset, seller, winner, auction.Price)
+}
+
+// releaseBidHold returns a held bid to the bidder's balance
+func (s *SmartContract) releaseBidHold(ctx contractapi.TransactionContextInterface, bidderID string, amount int64) error {
+	bidder, err := s.ReadUser(ctx, bidderID)
+	if err != nil {
+		return err
+	}
+
+	bidder.Money = bidder.Money + amount
+	return putUser(ctx, bidder)
+}
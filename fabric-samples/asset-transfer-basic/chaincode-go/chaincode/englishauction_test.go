@@ -0,0 +1,112 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnglishAuction(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "bidder3", Money: 7000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.StartAuction(transactionContext, "asset1", 2000, 3600)
+	require.NoError(t, err)
+	// the asset is held by the auction
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "the asset asset1 is up for auction auction1")
+	_, err = assetTransfer.StartAuction(transactionContext, "asset1", 2000, 3600)
+	require.EqualError(t, err, "the asset asset1 is already up for auction auction1")
+
+	clientIdentity.GetIDReturns("bidder2", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 1500)
+	require.EqualError(t, err, "the bid must be at least the reserve price 2000")
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 3000)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(2000), user.Money)
+
+	clientIdentity.GetIDReturns("bidder3", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user3", 3000)
+	require.EqualError(t, err, "the bid must be higher than the current bid 3000")
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user3", 4000)
+	require.NoError(t, err)
+	// the outbid hold is released
+	state.get(t, "user2", user)
+	require.Equal(t, int64(5000), user.Money)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(3000), user.Money)
+
+	err = assetTransfer.CloseAuction(transactionContext, auctionID)
+	require.EqualError(t, err, "the auction auction1 is open until 2020-09-13T13:26:40Z")
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600003601}, nil)
+	clientIdentity.GetIDReturns("bidder2", nil)
+	err = assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 5000)
+	require.EqualError(t, err, "the auction auction1 ended at 2020-09-13T13:26:40Z")
+
+	chaincodeStub.GetTxIDReturns("close")
+	err = assetTransfer.CloseAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+	auction, err := assetTransfer.ReadAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AuctionEnded, auction.Status)
+	require.Equal(t, "user3", auction.WinnerID)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user3", asset.OwnerID)
+	require.Empty(t, asset.AuctionID)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(3000), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(4000), user.Money)
+}
+
+func TestEnglishAuctionSettlementFails(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "bidder2", Money: 5000, DailyLimit: 1000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("auction1")
+	auctionID, err := assetTransfer.StartAuction(transactionContext, "asset1", 2000, 3600)
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("bidder2", nil)
+	require.NoError(t, assetTransfer.PlaceBid(transactionContext, auctionID, "user2", 3000))
+
+	// the winner cannot pay more than their daily limit, so the sale fails and the bid is refunded
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600003601}, nil)
+	chaincodeStub.GetTxIDReturns("close")
+	require.NoError(t, assetTransfer.CloseAuction(transactionContext, auctionID))
+	auction, err := assetTransfer.ReadAuction(transactionContext, auctionID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.AuctionFailed, auction.Status)
+	require.Equal(t, "payment of 3000 exceeds daily spending limit of user user2, 1000 left today", auction.FailureReason)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	require.Empty(t, asset.AuctionID)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(5000), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(0), user.Money)
+}
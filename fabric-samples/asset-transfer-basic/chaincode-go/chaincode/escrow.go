@@ -0,0 +1,191 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const escrowObjectType = "escrow"
+
+// CreateEscrow locks amount from the buyer's account for buying the asset. The seller has
+// timeoutSeconds to deliver the asset, after which either party can get the funds refunded.
+// It returns the ID of the new escrow.
+func (s *SmartContract) CreateEscrow(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, amount int64, timeoutSeconds int64) (string, error) {
+	if amount <= 0 {
+		return "", fmt.Errorf("amount must be positive")
+	}
+	if timeoutSeconds <= 0 {
+		return "", fmt.Errorf("timeout must be positive")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.OwnerID == buyerID {
+		return "", fmt.Errorf("New owner is same as current")
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return "", err
+	}
+	err = checkUserActive(buyer)
+	if err != nil {
+		return "", err
+	}
+	if buyer.Money < amount {
+		return "", fmt.Errorf("user %s doesn't have enough money on his account", buyerID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	buyer.Money = buyer.Money - amount
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return "", err
+	}
+
+	escrow := Escrow{
+		ID:       ctx.GetStub().GetTxID(),
+		AssetID:  assetID,
+		SellerID: asset.OwnerID,
+		BuyerID:  buyerID,
+		Amount:   amount,
+		Deadline: now.Add(time.Duration(timeoutSeconds) * time.Second),
+		Status:   EscrowFunded,
+	}
+	err = putEscrow(ctx, &escrow)
+	if err != nil {
+		return "", err
+	}
+
+	return escrow.ID, nil
+}
+
+// DeliverEscrowAsset lets the seller hand the asset over to the buyer, releasing the escrowed funds to the seller.
+func (s *SmartContract) DeliverEscrowAsset(ctx contractapi.TransactionContextInterface, escrowID string) error {
+	escrow, err := s.ReadEscrow(ctx, escrowID)
+	if err != nil {
+		return err
+	}
+	if escrow.Status != EscrowFunded {
+		return fmt.Errorf("the escrow %s is %s", escrowID, escrow.Status)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if now.After(escrow.Deadline) {
+		return fmt.Errorf("the escrow %s expired at %s", escrowID, escrow.Deadline.Format(time.RFC3339))
+	}
+	seller, err := s.ReadUser(ctx, escrow.SellerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return err
+	}
+	buyer, err := s.ReadUser(ctx, escrow.BuyerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, escrow.AssetID)
+	if err != nil {
+		return err
+	}
+
+	// the held funds go back to the buyer's balance and are paid out by the regular sale settlement
+	buyer.Money = buyer.Money + escrow.Amount
+	err = executeSale(ctx, asset, seller, buyer, escrow.Amount)
+	if err != nil {
+		return err
+	}
+
+	escrow.Status = EscrowReleased
+	return putEscrow(ctx, escrow)
+}
+
+// RefundEscrow returns the escrowed funds to the buyer once the escrow deadline has passed.
+// Either the buyer or the seller may trigger the refund.
+func (s *SmartContract) RefundEscrow(ctx contractapi.TransactionContextInterface, escrowID string) error {
+	escrow, err := s.ReadEscrow(ctx, escrowID)
+	if err != nil {
+		return err
+	}
+	if escrow.Status != EscrowFunded {
+		return fmt.Errorf("the escrow %s is %s", escrowID, escrow.Status)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if !now.After(escrow.Deadline) {
+		return fmt.Errorf("the escrow %s does not expire before %s", escrowID, escrow.Deadline.Format(time.RFC3339))
+	}
+	buyer, err := s.ReadUser(ctx, escrow.BuyerID)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, escrow.SellerID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, buyer) != nil && verifyUserIdentity(ctx, seller) != nil {
+		return fmt.Errorf("only the seller or the buyer can refund escrow %s", escrowID)
+	}
+
+	buyer.Money = buyer.Money + escrow.Amount
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	escrow.Status = EscrowRefunded
+	return putEscrow(ctx, escrow)
+}
+
+// ReadEscrow returns the escrow stored in the world state with given id.
+func (s *SmartContract) ReadEscrow(ctx contractapi.TransactionContextInterface, escrowID string) (*Escrow, error) {
+	escrowKey, err := ctx.GetStub().CreateCompositeKey(escrowObjectType, []string{escrowID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	escrowJSON, err := ctx.GetStub().GetState(escrowKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if escrowJSON == nil {
+		return nil, fmt.Errorf("the escrow %s does not exist", escrowID)
+	}
+
+	var escrow Escrow
+	err = json.Unmarshal(escrowJSON, &escrow)
+	if err != nil {
+		return nil, err
+	}
+
+	return &escrow, nil
+}
+
+// putEscrow writes the given escrow to the world state
+func putEscrow(ctx contractapi.TransactionContextInterface, escrow *Escrow) error {
+	escrowKey, err := ctx.GetStub().CreateCompositeKey(escrowObjectType, []string{escrow.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	escrowJSON, err := json.Marshal(escrow)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(escrowKey, escrowJSON)
+}
@@ -0,0 +1,76 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscrowDelivery(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	chaincodeStub.GetTxIDReturns("escrow1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "buyer"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("buyer", nil)
+	escrowID, err := assetTransfer.CreateEscrow(transactionContext, "asset1", "user2", 4000, 3600)
+	require.NoError(t, err)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(1000), buyer.Money)
+
+	err = assetTransfer.DeliverEscrowAsset(transactionContext, escrowID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("seller", nil)
+	err = assetTransfer.DeliverEscrowAsset(transactionContext, escrowID)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(4000), seller.Money)
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(1000), buyer.Money)
+
+	escrow, err := assetTransfer.ReadEscrow(transactionContext, escrowID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.EscrowReleased, escrow.Status)
+}
+
+func TestEscrowRefund(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("escrow1")
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	escrowID, err := assetTransfer.CreateEscrow(transactionContext, "asset1", "user2", 4000, 3600)
+	require.NoError(t, err)
+
+	err = assetTransfer.RefundEscrow(transactionContext, escrowID)
+	require.EqualError(t, err, "the escrow escrow1 does not expire before 2020-09-13T13:26:40Z")
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 3601}, nil)
+	err = assetTransfer.DeliverEscrowAsset(transactionContext, escrowID)
+	require.EqualError(t, err, "the escrow escrow1 expired at 2020-09-13T13:26:40Z")
+
+	err = assetTransfer.RefundEscrow(transactionContext, escrowID)
+	require.NoError(t, err)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(5000), buyer.Money)
+
+	err = assetTransfer.RefundEscrow(transactionContext, escrowID)
+	require.EqualError(t, err, "the escrow escrow1 is refunded")
+}
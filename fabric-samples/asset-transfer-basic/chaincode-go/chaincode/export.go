@@ -0,0 +1,253 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const exportObjectType = "asset~export"
+
+// SetUserJurisdiction sets the jurisdiction a regulator oversees. Only admins may change it.
+func (s *SmartContract) SetUserJurisdiction(ctx contractapi.TransactionContextInterface, userID string, jurisdiction string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.Jurisdiction = jurisdiction
+	return putUser(ctx, user)
+}
+
+// RegisterInJurisdiction lets a regulator register an asset not registered anywhere yet in their
+// jurisdiction
+func (s *SmartContract) RegisterInJurisdiction(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string) error {
+	regulator, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return err
+	}
+	if regulator.Jurisdiction == "" {
+		return fmt.Errorf("the user %s does not oversee a jurisdiction", regulatorID)
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.Jurisdiction != "" {
+		return fmt.Errorf("the asset %s is registered in %s", assetID, asset.Jurisdiction)
+	}
+
+	asset.Jurisdiction = regulator.Jurisdiction
+	return putAsset(ctx, asset)
+}
+
+// Export lets the owner request moving the asset to the destination jurisdiction. The regulator of
+// the current jurisdiction approves the export with ApproveExport, then the regulator of the
+// destination completes it with Import. The asset cannot be sold meanwhile.
+// It returns the ID of the new export.
+func (s *SmartContract) Export(ctx contractapi.TransactionContextInterface, assetID string, destination string) (string, error) {
+	owner, err := s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.Jurisdiction == "" {
+		return "", fmt.Errorf("the asset %s is not registered in a jurisdiction", assetID)
+	}
+	if destination == "" || destination == asset.Jurisdiction {
+		return "", fmt.Errorf("destination must be a jurisdiction other than %s", asset.Jurisdiction)
+	}
+	if asset.ExportID != "" {
+		return "", fmt.Errorf("the asset %s is being exported", assetID)
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	export := Export{
+		ID:          ctx.GetStub().GetTxID(),
+		AssetID:     assetID,
+		OwnerID:     owner.ID,
+		Origin:      asset.Jurisdiction,
+		Destination: destination,
+		Status:      ExportRequested,
+		RequestedAt: now,
+	}
+	err = putExport(ctx, &export)
+	if err != nil {
+		return "", err
+	}
+	asset.ExportID = export.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return export.ID, nil
+}
+
+// ApproveExport lets the regulator of the jurisdiction the asset is leaving approve its export
+func (s *SmartContract) ApproveExport(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string) error {
+	_, export, err := s.readExport(ctx, assetID, ExportRequested)
+	if err != nil {
+		return err
+	}
+	err = s.verifyJurisdictionRegulator(ctx, regulatorID, export.Origin)
+	if err != nil {
+		return err
+	}
+
+	export.Status = ExportApproved
+	export.ExportedBy = regulatorID
+	return putExport(ctx, export)
+}
+
+// Import lets the regulator of the destination accept an exported asset, registering it in their
+// jurisdiction
+func (s *SmartContract) Import(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string) error {
+	asset, export, err := s.readExport(ctx, assetID, ExportApproved)
+	if err != nil {
+		return err
+	}
+	err = s.verifyJurisdictionRegulator(ctx, regulatorID, export.Destination)
+	if err != nil {
+		return err
+	}
+
+	export.Status = ExportImported
+	export.ImportedBy = regulatorID
+	err = putExport(ctx, export)
+	if err != nil {
+		return err
+	}
+	asset.Jurisdiction = export.Destination
+	asset.ExportID = ""
+	return putAsset(ctx, asset)
+}
+
+// RejectExport lets the regulator whose approval the export waits for reject it. The asset stays
+// registered in its current jurisdiction.
+func (s *SmartContract) RejectExport(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string, reason string) error {
+	asset, export, err := s.readExport(ctx, assetID, "")
+	if err != nil {
+		return err
+	}
+	jurisdiction := export.Origin
+	if export.Status == ExportApproved {
+		jurisdiction = export.Destination
+	}
+	err = s.verifyJurisdictionRegulator(ctx, regulatorID, jurisdiction)
+	if err != nil {
+		return err
+	}
+
+	export.Status = ExportRejected
+	export.RejectedBy = regulatorID
+	export.Reason = reason
+	err = putExport(ctx, export)
+	if err != nil {
+		return err
+	}
+	asset.ExportID = ""
+	return putAsset(ctx, asset)
+}
+
+// GetExports returns all exports of the asset, recording the jurisdictions it was registered in
+func (s *SmartContract) GetExports(ctx contractapi.TransactionContextInterface, assetID string) ([]*Export, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(exportObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var exports []*Export
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var export Export
+		err = json.Unmarshal(queryResponse.Value, &export)
+		if err != nil {
+			return nil, err
+		}
+		exports = append(exports, &export)
+	}
+
+	return exports, nil
+}
+
+// readExport returns the asset and its export in progress, checking the export has given status
+// when status is not empty
+func (s *SmartContract) readExport(ctx contractapi.TransactionContextInterface, assetID string, status string) (*Asset, *Export, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if asset.ExportID == "" {
+		return nil, nil, fmt.Errorf("the asset %s is not being exported", assetID)
+	}
+	exportKey, err := ctx.GetStub().CreateCompositeKey(exportObjectType, []string{assetID, asset.ExportID})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	exportJSON, err := ctx.GetStub().GetState(exportKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if exportJSON == nil {
+		return nil, nil, fmt.Errorf("the export %s does not exist", asset.ExportID)
+	}
+	var export Export
+	err = json.Unmarshal(exportJSON, &export)
+	if err != nil {
+		return nil, nil, err
+	}
+	if status != "" && export.Status != status {
+		return nil, nil, fmt.Errorf("the export %s is %s", export.ID, export.Status)
+	}
+
+	return asset, &export, nil
+}
+
+// verifyJurisdictionRegulator returns an error unless the user is a regulator of the jurisdiction
+// the submitting client acts for
+func (s *SmartContract) verifyJurisdictionRegulator(ctx contractapi.TransactionContextInterface, regulatorID string, jurisdiction string) error {
+	regulator, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return err
+	}
+	if regulator.Jurisdiction != jurisdiction {
+		return fmt.Errorf("the user %s is not a regulator of %s", regulatorID, jurisdiction)
+	}
+
+	return nil
+}
+
+// putExport writes the export under its asset~export composite key
+func putExport(ctx contractapi.TransactionContextInterface, export *Export) error {
+	exportKey, err := ctx.GetStub().CreateCompositeKey(exportObjectType, []string{export.AssetID, export.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	exportJSON, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(exportKey, exportJSON)
+}
@@ -0,0 +1,68 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportImport(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleRegulator}})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Roles: []string{chaincode.RoleRegulator}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetUserJurisdiction(transactionContext, "user3", "RS")
+	require.NoError(t, err)
+	err = assetTransfer.SetUserJurisdiction(transactionContext, "user4", "DE")
+	require.NoError(t, err)
+	_, err = assetTransfer.Export(transactionContext, "asset1", "DE")
+	require.EqualError(t, err, "the asset asset1 is not registered in a jurisdiction")
+	err = assetTransfer.RegisterInJurisdiction(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+
+	chaincodeStub.GetTxIDReturns("export1")
+	exportID, err := assetTransfer.Export(transactionContext, "asset1", "DE")
+	require.NoError(t, err)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "the asset asset1 is being exported")
+	err = assetTransfer.Import(transactionContext, "asset1", "user4")
+	require.EqualError(t, err, "the export export1 is requested")
+	err = assetTransfer.ApproveExport(transactionContext, "asset1", "user4")
+	require.EqualError(t, err, "the user user4 is not a regulator of RS")
+	err = assetTransfer.ApproveExport(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+	err = assetTransfer.Import(transactionContext, "asset1", "user4")
+	require.NoError(t, err)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "DE", asset.Jurisdiction)
+	require.Empty(t, asset.ExportID)
+
+	chaincodeStub.GetTxIDReturns("export2")
+	_, err = assetTransfer.Export(transactionContext, "asset1", "AT")
+	require.NoError(t, err)
+	err = assetTransfer.RejectExport(transactionContext, "asset1", "user4", "unpaid road tax")
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "DE", asset.Jurisdiction)
+
+	exports, err := assetTransfer.GetExports(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, exports, 2)
+	require.Equal(t, exportID, exports[0].ID)
+	require.Equal(t, chaincode.ExportImported, exports[0].Status)
+	require.Equal(t, "user3", exports[0].ExportedBy)
+	require.Equal(t, "user4", exports[0].ImportedBy)
+	require.Equal(t, chaincode.ExportRejected, exports[1].Status)
+
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+}
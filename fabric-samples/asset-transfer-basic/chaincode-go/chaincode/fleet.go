@@ -0,0 +1,174 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// maxFleetSize bounds the number of assets transferred in one transaction
+const maxFleetSize = 100
+
+// TransferFleet sells a fleet of assets, given as a JSON array of FleetItem, to newOwner in one
+// transaction. Every asset must belong to the same seller and pass the checks of a single sale.
+// The buyer pays the total price once and the seller is credited once, while each asset gets its
+// own transfer record at its own price. The submitting client must be bound to the buyer and to
+// the seller or the delegate of every asset. Co-owned assets and regulated transfers are not
+// supported; sell those one by one.
+func (s *SmartContract) TransferFleet(ctx contractapi.TransactionContextInterface, itemsJSON string, newOwner string, withDamage bool) error {
+	var items []FleetItem
+	err := json.Unmarshal([]byte(itemsJSON), &items)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal fleet: %v", err)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no assets to transfer")
+	}
+	if len(items) > maxFleetSize {
+		return fmt.Errorf("at most %d assets can be transferred at once, got %d", maxFleetSize, len(items))
+	}
+	regulated, err := getConfigInt(ctx, registrationRequiredConfig, 0)
+	if err != nil {
+		return err
+	}
+	if regulated != 0 {
+		return fmt.Errorf("transfers must be confirmed by the registry, sell the assets one by one")
+	}
+	buyer, err := s.ReadUser(ctx, newOwner)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	var seller *User
+	assets := make([]*Asset, len(items))
+	total := int64(0)
+	for i, item := range items {
+		if item.Price < 0 {
+			return fmt.Errorf("sale price of asset %s must not be negative", item.AssetID)
+		}
+		for _, previous := range items[:i] {
+			if previous.AssetID == item.AssetID {
+				return fmt.Errorf("the asset %s is given more than once", item.AssetID)
+			}
+		}
+		asset, err := s.ReadAsset(ctx, item.AssetID)
+		if err != nil {
+			return err
+		}
+		if seller == nil {
+			if asset.OwnerID == newOwner {
+				return fmt.Errorf("New owner is same as current")
+			}
+			seller, err = s.ReadUser(ctx, asset.OwnerID)
+			if err != nil {
+				return err
+			}
+		}
+		if asset.OwnerID != seller.ID {
+			return fmt.Errorf("the fleet mixes assets of users %s and %s", seller.ID, asset.OwnerID)
+		}
+		err = s.verifyOwnerOrDelegate(ctx, asset, seller)
+		if err != nil {
+			return err
+		}
+		if len(asset.CoOwners) > 0 {
+			return fmt.Errorf("the asset %s is co-owned and must be sold on its own", asset.ID)
+		}
+		if len(asset.Damages) > 0 && !withDamage {
+			return fmt.Errorf("the asset %s has unrepaired damages", asset.ID)
+		}
+		err = checkAssetSale(ctx, asset, seller, buyer)
+		if err != nil {
+			return err
+		}
+		assets[i] = asset
+		total = total + item.Price
+	}
+	err = checkUserActive(seller)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(buyer)
+	if err != nil {
+		return err
+	}
+	err = checkKYC(ctx, buyer, total)
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		err = transferInsurance(ctx, asset, buyer.ID)
+		if err != nil {
+			return err
+		}
+	}
+	if buyer.Money < total {
+		return fmt.Errorf("Customer doesn't have enough money on his account")
+	}
+	err = recordSpending(ctx, buyer, total)
+	if err != nil {
+		return err
+	}
+
+	// the tax is due per asset, but the treasury and the seller are credited once since writes
+	// are not visible to reads within the transaction
+	taxes := make([]int64, len(items))
+	totalTax := int64(0)
+	for i, item := range items {
+		taxes[i], err = transferTax(ctx, item.Price)
+		if err != nil {
+			return err
+		}
+		totalTax = totalTax + taxes[i]
+	}
+	err = creditTreasury(ctx, totalTax)
+	if err != nil {
+		return err
+	}
+	buyer.Money = buyer.Money - total
+	err = creditUser(ctx, seller, total-totalTax, "proceeds")
+	if err != nil {
+		return err
+	}
+	if !seller.HotAccount {
+		err = putUser(ctx, seller)
+		if err != nil {
+			return err
+		}
+	}
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	for i, asset := range assets {
+		err = handOverAsset(ctx, asset, seller, buyer)
+		if err != nil {
+			return err
+		}
+		err = recordSalePrice(ctx, asset, items[i].Price)
+		if err != nil {
+			return err
+		}
+		err = putTransferRecord(ctx, &TransferRecord{
+			TxID:           ctx.GetStub().GetTxID(),
+			AssetID:        asset.ID,
+			SellerID:       seller.ID,
+			BuyerID:        buyer.ID,
+			Price:          items[i].Price,
+			AppraisedValue: asset.AppraisedValue,
+			Tax:            taxes[i],
+			Mileage:        asset.Mileage,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
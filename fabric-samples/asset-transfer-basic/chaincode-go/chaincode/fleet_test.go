@@ -0,0 +1,55 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferFleet(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 10000})
+	state.put(t, "user3", &chaincode.User{ID: "user3"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 5000})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1", Mileage: 120000})
+	state.put(t, "asset3", &chaincode.Asset{ID: "asset3", OwnerID: "user3"})
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.SetTransferTaxRate(transactionContext, 250))
+
+	err := assetTransfer.TransferFleet(transactionContext, `[{"assetID":"asset1","price":4000},{"assetID":"asset1","price":2000}]`, "user2", false)
+	require.EqualError(t, err, "the asset asset1 is given more than once")
+	err = assetTransfer.TransferFleet(transactionContext, `[{"assetID":"asset1","price":4000},{"assetID":"asset3","price":2000}]`, "user2", false)
+	require.EqualError(t, err, "the fleet mixes assets of users user1 and user3")
+	err = assetTransfer.TransferFleet(transactionContext, `[{"assetID":"asset1","price":8000},{"assetID":"asset2","price":4000}]`, "user2", false)
+	require.EqualError(t, err, "Customer doesn't have enough money on his account")
+	err = assetTransfer.TransferFleet(transactionContext, `[]`, "user2", false)
+	require.EqualError(t, err, "no assets to transfer")
+
+	err = assetTransfer.TransferFleet(transactionContext, `[{"assetID":"asset1","price":4000},{"assetID":"asset2","price":2000}]`, "user2", false)
+	require.NoError(t, err)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(5850), seller.Money)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(4000), buyer.Money)
+	treasury := &chaincode.User{}
+	state.get(t, chaincode.TreasuryUserID, treasury)
+	require.Equal(t, int64(150), treasury.Money)
+
+	for _, id := range []string{"asset1", "asset2"} {
+		asset, err := assetTransfer.ReadAsset(transactionContext, id)
+		require.NoError(t, err)
+		require.Equal(t, "user2", asset.OwnerID)
+	}
+	transfers, err := assetTransfer.GetTransfers(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.TransferRecord{{TxID: "tx1", AssetID: "asset2", SellerID: "user1", BuyerID: "user2", Price: 2000, Tax: 50, Mileage: 120000}}, transfers)
+	transfers, err = assetTransfer.GetTransfers(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.TransferRecord{{TxID: "tx1", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 4000, AppraisedValue: 5000, Tax: 100}}, transfers)
+}
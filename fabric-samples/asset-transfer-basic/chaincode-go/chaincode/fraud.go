@@ -0,0 +1,126 @@
+package chaincode
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetSuspiciousClaims screens all indexed claims and flags users and assets with at least maxClaims
+// claims filed within windowDays days of each other, as well as claims filed less than
+// newPolicyDays days after their policy was created. Flags are ordered by subject and ID.
+func (s *SmartContract) GetSuspiciousClaims(ctx contractapi.TransactionContextInterface, windowDays int, maxClaims int, newPolicyDays int) ([]*ClaimFlag, error) {
+	if windowDays <= 0 || maxClaims < 2 || newPolicyDays < 0 {
+		return nil, fmt.Errorf("window must be positive, claim limit at least 2 and new policy period not negative")
+	}
+	window := time.Duration(windowDays) * 24 * time.Hour
+
+	var flags []*ClaimFlag
+	claimsByOwner, err := s.readClaimIndex(ctx, ownerClaimIndex)
+	if err != nil {
+		return nil, err
+	}
+	flags = append(flags, flagRepeatedClaims(FlagUser, claimsByOwner, window, maxClaims)...)
+	claimsByAsset, err := s.readClaimIndex(ctx, assetClaimIndex)
+	if err != nil {
+		return nil, err
+	}
+	flags = append(flags, flagRepeatedClaims(FlagAsset, claimsByAsset, window, maxClaims)...)
+
+	policies := map[string]*InsurancePolicy{}
+	for _, ownerID := range sortedClaimSubjects(claimsByOwner) {
+		for _, claim := range claimsByOwner[ownerID] {
+			policy, ok := policies[claim.PolicyID]
+			if !ok {
+				policy, err = readPolicy(ctx, claim.PolicyID)
+				if err != nil {
+					return nil, err
+				}
+				policies[claim.PolicyID] = policy
+			}
+			age := claim.FiledAt.Sub(policy.CreatedAt)
+			if age < time.Duration(newPolicyDays)*24*time.Hour {
+				flags = append(flags, &ClaimFlag{
+					Subject:  FlagClaim,
+					ID:       claim.ID,
+					Reason:   fmt.Sprintf("filed %d days after policy %s was created", int(age.Hours()/24), policy.ID),
+					ClaimIDs: []string{claim.ID},
+				})
+			}
+		}
+	}
+
+	return flags, nil
+}
+
+// readClaimIndex returns all claims in the owner or asset claim index, grouped by the indexed subject
+func (s *SmartContract) readClaimIndex(ctx contractapi.TransactionContextInterface, indexName string) (map[string][]*Claim, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(indexName, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	claims := map[string][]*Claim{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil {
+			return nil, err
+		}
+		claim, err := s.ReadClaim(ctx, string(queryResponse.Value))
+		if err != nil {
+			return nil, err
+		}
+		claims[attributes[0]] = append(claims[attributes[0]], claim)
+	}
+
+	return claims, nil
+}
+
+// flagRepeatedClaims flags every subject with at least maxClaims claims filed within the window
+func flagRepeatedClaims(subject string, claimsBySubject map[string][]*Claim, window time.Duration, maxClaims int) []*ClaimFlag {
+	var flags []*ClaimFlag
+	for _, id := range sortedClaimSubjects(claimsBySubject) {
+		claims := claimsBySubject[id]
+		sort.SliceStable(claims, func(i, j int) bool {
+			return claims[i].FiledAt.Before(claims[j].FiledAt)
+		})
+		for first := 0; first+maxClaims <= len(claims); first++ {
+			last := first + maxClaims - 1
+			if claims[last].FiledAt.Sub(claims[first].FiledAt) > window {
+				continue
+			}
+			var claimIDs []string
+			for _, claim := range claims[first : last+1] {
+				claimIDs = append(claimIDs, claim.ID)
+			}
+			flags = append(flags, &ClaimFlag{
+				Subject:  subject,
+				ID:       id,
+				Reason:   fmt.Sprintf("%d claims within %d days", maxClaims, int(window.Hours()/24)),
+				ClaimIDs: claimIDs,
+			})
+			break
+		}
+	}
+
+	return flags
+}
+
+// sortedClaimSubjects returns the subjects of the grouped claims in a deterministic order
+func sortedClaimSubjects(claimsBySubject map[string][]*Claim) []string {
+	var subjects []string
+	for subject := range claimsBySubject {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+
+	return subjects
+}
@@ -0,0 +1,58 @@
+package chaincode_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSuspiciousClaims(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	day := func(n int) time.Time {
+		return time.Unix(1600000000, 0).UTC().AddDate(0, 0, n)
+	}
+	claims := []*chaincode.Claim{
+		{ID: "claim1", PolicyID: "policy1", AssetID: "asset1", OwnerID: "user1", FiledAt: day(0)},
+		{ID: "claim2", PolicyID: "policy2", AssetID: "asset2", OwnerID: "user1", FiledAt: day(10)},
+		{ID: "claim3", PolicyID: "policy3", AssetID: "asset3", OwnerID: "user2", FiledAt: day(100)},
+	}
+	state.put(t, "\x00policy\x00policy1\x00", &chaincode.InsurancePolicy{ID: "policy1", CreatedAt: day(-200)})
+	state.put(t, "\x00policy\x00policy2\x00", &chaincode.InsurancePolicy{ID: "policy2", CreatedAt: day(-200)})
+	state.put(t, "\x00policy\x00policy3\x00", &chaincode.InsurancePolicy{ID: "policy3", CreatedAt: day(95)})
+	for _, claim := range claims {
+		state.put(t, "\x00claim\x00"+claim.ID+"\x00", claim)
+	}
+	chaincodeStub.GetStateByPartialCompositeKeyStub = func(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+		iterator := &mocks.StateQueryIterator{}
+		for i, claim := range claims {
+			subject := claim.OwnerID
+			if objectType == "asset~claim" {
+				subject = claim.AssetID
+			}
+			key, err := shim.CreateCompositeKey(objectType, []string{subject, claim.ID})
+			require.NoError(t, err)
+			iterator.HasNextReturnsOnCall(i, true)
+			iterator.NextReturnsOnCall(i, &queryresult.KV{Key: key, Value: []byte(claim.ID)}, nil)
+		}
+		return iterator, nil
+	}
+	chaincodeStub.SplitCompositeKeyStub = func(key string) (string, []string, error) {
+		parts := strings.Split(strings.Trim(key, "\x00"), "\x00")
+		return parts[0], parts[1:], nil
+	}
+
+	assetTransfer := chaincode.SmartContract{}
+	flags, err := assetTransfer.GetSuspiciousClaims(transactionContext, 30, 2, 30)
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.ClaimFlag{
+		{Subject: chaincode.FlagUser, ID: "user1", Reason: "2 claims within 30 days", ClaimIDs: []string{"claim1", "claim2"}},
+		{Subject: chaincode.FlagClaim, ID: "claim3", Reason: "filed 5 days after policy policy3 was created", ClaimIDs: []string{"claim3"}},
+	}, flags)
+}
@@ -0,0 +1,243 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Codes prefixing the errors returned for operations blocked by a freeze
+const (
+	ErrCodeUserFrozen  = "USER_FROZEN"
+	ErrCodeAssetFrozen = "ASSET_FROZEN"
+	ErrCodeAssetSeized = "ASSET_SEIZED"
+	ErrCodeAssetStolen = "ASSET_STOLEN"
+)
+
+// FreezeUser blacklists the user with given ID: until unfrozen the user cannot pay, receive or
+// withdraw funds, and their assets cannot be sold. Those operations fail with the USER_FROZEN code
+// and are recorded with RecordBlockedAttempt. Only admins may freeze users.
+func (s *SmartContract) FreezeUser(ctx contractapi.TransactionContextInterface, userID string, reason string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		return fmt.Errorf("reason must not be empty")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status != UserActive {
+		return fmt.Errorf("the user %s is %s", userID, user.Status)
+	}
+
+	user.Status = UserFrozen
+	user.FrozenReason = reason
+	err = putUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return setUserFreezeEvent(ctx, "UserFrozen", user, reason)
+}
+
+// UnfreezeUser lifts the freeze of the user with given ID. Only admins may unfreeze users.
+func (s *SmartContract) UnfreezeUser(ctx contractapi.TransactionContextInterface, userID string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status != UserFrozen {
+		return fmt.Errorf("the user %s is not frozen", userID)
+	}
+
+	reason := user.FrozenReason
+	user.Status = UserActive
+	user.FrozenReason = ""
+	err = putUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return setUserFreezeEvent(ctx, "UserUnfrozen", user, reason)
+}
+
+// setUserFreezeEvent emits an event recording the change of the user's freeze
+func setUserFreezeEvent(ctx contractapi.TransactionContextInterface, name string, user *User, reason string) error {
+	eventJSON, err := json.Marshal(UserFreezeEvent{UserID: user.ID, Status: user.Status, Reason: reason})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventJSON)
+}
+
+const blockedAttemptObjectType = "user~blocked"
+
+// RecordBlockedAttempt records in the user's audit trail that operation was refused with the
+// USER_FROZEN code, and emits a BlockedAttempt event. The refused transaction commits nothing, so
+// clients submit this after a call fails with that code. It fails unless the user is frozen.
+func (s *SmartContract) RecordBlockedAttempt(ctx contractapi.TransactionContextInterface, userID string, operation string) error {
+	if operation == "" {
+		return fmt.Errorf("operation must not be empty")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Status != UserFrozen {
+		return fmt.Errorf("the user %s is not frozen", userID)
+	}
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	attempt := BlockedAttempt{
+		UserID:    userID,
+		Operation: operation,
+		Code:      ErrCodeUserFrozen,
+		TxID:      ctx.GetStub().GetTxID(),
+		ClientID:  clientID,
+		MSPID:     mspID,
+		Timestamp: now,
+	}
+	attemptKey, err := ctx.GetStub().CreateCompositeKey(blockedAttemptObjectType, []string{userID, attempt.TxID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	attemptJSON, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(attemptKey, attemptJSON)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent("BlockedAttempt", attemptJSON)
+}
+
+// GetBlockedAttempts returns the recorded attempts refused because the user was frozen, oldest first
+func (s *SmartContract) GetBlockedAttempts(ctx contractapi.TransactionContextInterface, userID string) ([]*BlockedAttempt, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(blockedAttemptObjectType, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var attempts []*BlockedAttempt
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var attempt BlockedAttempt
+		err = json.Unmarshal(queryResponse.Value, &attempt)
+		if err != nil {
+			return nil, err
+		}
+		attempts = append(attempts, &attempt)
+	}
+	sort.SliceStable(attempts, func(i, j int) bool {
+		return attempts[i].Timestamp.Before(attempts[j].Timestamp)
+	})
+
+	return attempts, nil
+}
+
+// FreezeAsset puts the asset with given ID on administrative hold, for example during a dispute or
+// an investigation. Until unfrozen the asset cannot be transferred, listed or paid for repairs.
+// Only admins may freeze assets.
+func (s *SmartContract) FreezeAsset(ctx contractapi.TransactionContextInterface, assetID string, reason string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if reason == "" {
+		return fmt.Errorf("reason must not be empty")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.Frozen {
+		return fmt.Errorf("the asset %s is already frozen", assetID)
+	}
+
+	asset.Frozen = true
+	asset.FrozenReason = reason
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	return setAssetFreezeEvent(ctx, "AssetFrozen", asset, reason)
+}
+
+// UnfreezeAsset releases the hold on the asset with given ID. Only admins may unfreeze assets.
+func (s *SmartContract) UnfreezeAsset(ctx contractapi.TransactionContextInterface, assetID string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if !asset.Frozen {
+		return fmt.Errorf("the asset %s is not frozen", assetID)
+	}
+
+	reason := asset.FrozenReason
+	asset.Frozen = false
+	asset.FrozenReason = ""
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	return setAssetFreezeEvent(ctx, "AssetUnfrozen", asset, reason)
+}
+
+// checkAssetNotHeld returns an error while the asset is on administrative hold, seized or reported stolen
+func checkAssetNotHeld(asset *Asset) error {
+	if asset.Frozen {
+		return fmt.Errorf("%s: the asset %s is frozen", ErrCodeAssetFrozen, asset.ID)
+	}
+	if asset.SeizureID != "" {
+		return fmt.Errorf("%s: the asset %s is seized", ErrCodeAssetSeized, asset.ID)
+	}
+	if asset.Stolen {
+		return fmt.Errorf("%s: the asset %s is reported stolen", ErrCodeAssetStolen, asset.ID)
+	}
+
+	return nil
+}
+
+// setAssetFreezeEvent emits an event recording the change of the asset's hold
+func setAssetFreezeEvent(ctx contractapi.TransactionContextInterface, name string, asset *Asset, reason string) error {
+	eventJSON, err := json.Marshal(AssetFreezeEvent{AssetID: asset.ID, Frozen: asset.Frozen, Reason: reason})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventJSON)
+}
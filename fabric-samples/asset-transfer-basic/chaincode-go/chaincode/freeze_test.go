@@ -0,0 +1,115 @@
+package chaincode_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreezeUser(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.FreezeUser(transactionContext, "user1", "")
+	require.EqualError(t, err, "reason must not be empty")
+	err = assetTransfer.FreezeUser(transactionContext, "user1", "court order 17")
+	require.NoError(t, err)
+	name, payload := chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "UserFrozen", name)
+	event := chaincode.UserFreezeEvent{}
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, chaincode.UserFreezeEvent{UserID: "user1", Status: chaincode.UserFrozen, Reason: "court order 17"}, event)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user2", 100, "")
+	require.EqualError(t, err, "USER_FROZEN: the user user1 is frozen")
+	err = assetTransfer.TransferFunds(transactionContext, "user2", "user1", 100, "")
+	require.EqualError(t, err, "USER_FROZEN: the user user1 is frozen")
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "USER_FROZEN: the user user1 is frozen")
+
+	err = assetTransfer.UnfreezeUser(transactionContext, "user1")
+	require.NoError(t, err)
+	name, _ = chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "UserUnfrozen", name)
+	err = assetTransfer.UnfreezeUser(transactionContext, "user1")
+	require.EqualError(t, err, "the user user1 is not frozen")
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user2", 100, "")
+	require.NoError(t, err)
+}
+
+func TestRecordBlockedAttempt(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP", Status: chaincode.UserActive})
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTxIDReturns("tx1")
+	err := assetTransfer.RecordBlockedAttempt(transactionContext, "user1", "TransferFunds")
+	require.EqualError(t, err, "the user user1 is not frozen")
+
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP", Status: chaincode.UserFrozen})
+	err = assetTransfer.RecordBlockedAttempt(transactionContext, "user1", "")
+	require.EqualError(t, err, "operation must not be empty")
+	err = assetTransfer.RecordBlockedAttempt(transactionContext, "user1", "TransferFunds")
+	require.NoError(t, err)
+	name, payload := chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "BlockedAttempt", name)
+	event := chaincode.BlockedAttempt{}
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, chaincode.ErrCodeUserFrozen, event.Code)
+
+	attempts, err := assetTransfer.GetBlockedAttempts(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Len(t, attempts, 1)
+	require.Equal(t, &chaincode.BlockedAttempt{
+		UserID:    "user1",
+		Operation: "TransferFunds",
+		Code:      chaincode.ErrCodeUserFrozen,
+		TxID:      "tx1",
+		ClientID:  "owner",
+		MSPID:     "Org1MSP",
+		Timestamp: time.Unix(1600000000, 0).UTC(),
+	}, attempts[0])
+}
+
+func TestFreezeAsset(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Status: chaincode.UserActive})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.FreezeAsset(transactionContext, "asset1", "ownership dispute")
+	require.NoError(t, err)
+	name, _ := chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "AssetFrozen", name)
+	err = assetTransfer.FreezeAsset(transactionContext, "asset1", "ownership dispute")
+	require.EqualError(t, err, "the asset asset1 is already frozen")
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2000)
+	require.EqualError(t, err, "ASSET_FROZEN: the asset asset1 is frozen")
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 2000, "")
+	require.EqualError(t, err, "ASSET_FROZEN: the asset asset1 is frozen")
+
+	err = assetTransfer.UnfreezeAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	err = assetTransfer.UnfreezeAsset(transactionContext, "asset1")
+	require.EqualError(t, err, "the asset asset1 is not frozen")
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2000)
+	require.NoError(t, err)
+}
@@ -0,0 +1,252 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const paymentObjectType = "payment"
+
+// DepositFunds adds the given amount to the balance of user with given ID. Only admins may
+// deposit, since the money enters the ledger from outside.
+func (s *SmartContract) DepositFunds(ctx contractapi.TransactionContextInterface, userID string, amount int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(user)
+	if err != nil {
+		return err
+	}
+
+	user.Money = user.Money + amount
+	err = putUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return setFundsEvent(ctx, "FundsDeposited", user, amount)
+}
+
+// WithdrawFunds takes the given amount from the balance of user with given ID.
+func (s *SmartContract) WithdrawFunds(ctx contractapi.TransactionContextInterface, userID string, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(user)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, user)
+	if err != nil {
+		return err
+	}
+	if user.Money < amount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", userID)
+	}
+
+	user.Money = user.Money - amount
+	err = putUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return setFundsEvent(ctx, "FundsWithdrawn", user, amount)
+}
+
+// TransferFunds moves the given amount from one user to another and records the payment.
+func (s *SmartContract) TransferFunds(ctx contractapi.TransactionContextInterface, fromUserID string, toUserID string, amount int64, memo string) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if fromUserID == toUserID {
+		return fmt.Errorf("cannot transfer funds to the same user")
+	}
+	from, err := s.ReadUser(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+	to, err := s.ReadUser(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, from)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(from)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(to)
+	if err != nil {
+		return err
+	}
+	if from.Money < amount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", fromUserID)
+	}
+	err = recordSpending(ctx, from, amount)
+	if err != nil {
+		return err
+	}
+
+	from.Money = from.Money - amount
+	to.Money = to.Money + amount
+	err = putUser(ctx, from)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, to)
+	if err != nil {
+		return err
+	}
+
+	payment := Payment{
+		ID:         ctx.GetStub().GetTxID(),
+		FromUserID: fromUserID,
+		ToUserID:   toUserID,
+		Amount:     amount,
+		Memo:       memo,
+	}
+	paymentJSON, err := json.Marshal(payment)
+	if err != nil {
+		return err
+	}
+	paymentKey, err := ctx.GetStub().CreateCompositeKey(paymentObjectType, []string{payment.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().PutState(paymentKey, paymentJSON)
+	if err != nil {
+		return fmt.Errorf("failed to put payment to world state. %v", err)
+	}
+
+	return ctx.GetStub().SetEvent("FundsTransferred", paymentJSON)
+}
+
+// SetSpendingLimit sets how much the user with given ID may spend per day, 0 removes the limit.
+// Only admins may set limits.
+func (s *SmartContract) SetSpendingLimit(ctx contractapi.TransactionContextInterface, userID string, limit int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if limit < 0 {
+		return fmt.Errorf("limit must not be negative")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.DailyLimit = limit
+	return putUser(ctx, user)
+}
+
+// GetUserBalanceHistory returns the balances of user with given ID ordered from oldest to newest.
+func (s *SmartContract) GetUserBalanceHistory(ctx contractapi.TransactionContextInterface, userID string) ([]*BalanceRecord, error) {
+	resultsIterator, err := ctx.GetStub().GetHistoryForKey(userID)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var records []*BalanceRecord
+	for resultsIterator.HasNext() {
+		modification, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp, err := ptypes.Timestamp(modification.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		record := BalanceRecord{
+			TxID:      modification.TxId,
+			Timestamp: timestamp,
+			IsDelete:  modification.IsDelete,
+		}
+		if !modification.IsDelete {
+			var user User
+			err = decodeUser(modification.Value, &user)
+			if err != nil {
+				return nil, err
+			}
+			record.Balance = user.Money
+		}
+		records = append(records, &record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+	return records, nil
+}
+
+// recordSpending adds amount to what the user spent today and fails when that exceeds the daily limit
+// or the account is frozen. The day is taken from the transaction timestamp so that all peers agree on it.
+func recordSpending(ctx contractapi.TransactionContextInterface, user *User, amount int64) error {
+	err := checkUserActive(user)
+	if err != nil {
+		return err
+	}
+	err = checkSpendingLimit(ctx, user, amount)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	today := now.UTC().Format("2006-01-02")
+	if user.SpentDay != today {
+		user.SpentDay = today
+		user.DailySpent = 0
+	}
+
+	user.DailySpent = user.DailySpent + amount
+	return nil
+}
+
+// checkSpendingLimit returns an error when paying amount would exceed the daily spending limit of the user
+func checkSpendingLimit(ctx contractapi.TransactionContextInterface, user *User, amount int64) error {
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	spent := user.DailySpent
+	if user.SpentDay != now.UTC().Format("2006-01-02") {
+		spent = 0
+	}
+	if user.DailyLimit > 0 && spent+amount > user.DailyLimit {
+		return fmt.Errorf("payment of %d exceeds daily spending limit of user %s, %d left today", amount, user.ID, user.DailyLimit-spent)
+	}
+
+	return nil
+}
+
+// setFundsEvent emits an event describing a balance change of the user
+func setFundsEvent(ctx contractapi.TransactionContextInterface, name string, user *User, amount int64) error {
+	eventJSON, err := json.Marshal(FundsEvent{UserID: user.ID, Amount: amount, Balance: user.Money})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventJSON)
+}
@@ -0,0 +1,162 @@
+package chaincode_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositFunds(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.DepositFunds(transactionContext, "user1", 50)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(150), user.Money)
+
+	name, payload := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "FundsDeposited", name)
+	event := chaincode.FundsEvent{}
+	require.NoError(t, json.Unmarshal(payload, &event))
+	require.Equal(t, chaincode.FundsEvent{UserID: "user1", Amount: 50, Balance: 150}, event)
+
+	err = assetTransfer.DepositFunds(transactionContext, "user1", 0)
+	require.EqualError(t, err, "amount must be positive")
+
+	err = assetTransfer.DepositFunds(transactionContext, "user9", 50)
+	require.EqualError(t, err, "the user user9 does not exist")
+}
+
+func TestDepositFundsRequiresAdmin(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute admin not found"))
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.DepositFunds(transactionContext, "user1", 50)
+	require.EqualError(t, err, "submitting client does not have attribute admin=true")
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(100), user.Money)
+}
+
+func TestWithdrawFunds(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.WithdrawFunds(transactionContext, "user1", 40)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(60), user.Money)
+	name, _ := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "FundsWithdrawn", name)
+
+	err = assetTransfer.WithdrawFunds(transactionContext, "user1", 61)
+	require.EqualError(t, err, "user user1 doesn't have enough money on his account")
+
+	err = assetTransfer.WithdrawFunds(transactionContext, "user1", -5)
+	require.EqualError(t, err, "amount must be positive")
+}
+
+func TestTransferFunds(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 20})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.TransferFunds(transactionContext, "user1", "user2", 30, "refund")
+	require.NoError(t, err)
+	from := &chaincode.User{}
+	state.get(t, "user1", from)
+	require.Equal(t, int64(70), from.Money)
+	to := &chaincode.User{}
+	state.get(t, "user2", to)
+	require.Equal(t, int64(50), to.Money)
+
+	expectedPayment := chaincode.Payment{ID: "tx1", FromUserID: "user1", ToUserID: "user2", Amount: 30, Memo: "refund"}
+	payment := chaincode.Payment{}
+	state.get(t, "\x00payment\x00tx1\x00", &payment)
+	require.Equal(t, expectedPayment, payment)
+	name, _ := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "FundsTransferred", name)
+
+	err = assetTransfer.TransferFunds(transactionContext, "user2", "user1", 51, "")
+	require.EqualError(t, err, "user user2 doesn't have enough money on his account")
+
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user1", 10, "")
+	require.EqualError(t, err, "cannot transfer funds to the same user")
+
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user9", 10, "")
+	require.EqualError(t, err, "the user user9 does not exist")
+}
+
+func TestGetUserBalanceHistory(t *testing.T) {
+	iterator := &mocks.HistoryQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, true)
+	iterator.HasNextReturnsOnCall(2, false)
+	iterator.NextReturnsOnCall(0, &queryresult.KeyModification{TxId: "tx2", Value: []byte(`{"ID":"user1","money":150}`), Timestamp: &timestamp.Timestamp{Seconds: 20}}, nil)
+	iterator.NextReturnsOnCall(1, &queryresult.KeyModification{TxId: "tx1", Value: []byte(`{"ID":"user1","money":100}`), Timestamp: &timestamp.Timestamp{Seconds: 10}}, nil)
+
+	transactionContext, chaincodeStub := prepMocks(worldState{})
+	chaincodeStub.GetHistoryForKeyReturns(iterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	records, err := assetTransfer.GetUserBalanceHistory(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.BalanceRecord{
+		{TxID: "tx1", Timestamp: time.Unix(10, 0).UTC(), Balance: 100},
+		{TxID: "tx2", Timestamp: time.Unix(20, 0).UTC(), Balance: 150},
+	}, records)
+	require.Equal(t, "user1", chaincodeStub.GetHistoryForKeyArgsForCall(0))
+
+	chaincodeStub.GetHistoryForKeyReturns(nil, fmt.Errorf("failed retrieving history"))
+	records, err = assetTransfer.GetUserBalanceHistory(transactionContext, "user1")
+	require.EqualError(t, err, "failed retrieving history")
+	require.Nil(t, records)
+}
+
+func TestSpendingLimit(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetSpendingLimit(transactionContext, "user1", 100)
+	require.NoError(t, err)
+
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user2", 60, "")
+	require.NoError(t, err)
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user2", 60, "")
+	require.EqualError(t, err, "payment of 60 exceeds daily spending limit of user user1, 40 left today")
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 24*60*60}, nil)
+	err = assetTransfer.TransferFunds(transactionContext, "user1", "user2", 60, "")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(60), user.DailySpent)
+	require.Equal(t, "2020-09-14", user.SpentDay)
+
+	err = assetTransfer.SetSpendingLimit(transactionContext, "user1", -1)
+	require.EqualError(t, err, "limit must not be negative")
+}
@@ -0,0 +1,55 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// BindUserIdentity binds the user with given ID to another enrollment identity of the organization
+// with given MSP ID. Only admins may rebind users.
+func (s *SmartContract) BindUserIdentity(ctx contractapi.TransactionContextInterface, userID string, clientID string, mspID string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if clientID == "" || mspID == "" {
+		return fmt.Errorf("client identity and MSP ID must not be empty")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.Identity = clientID
+	user.MSPID = mspID
+	return putUser(ctx, user)
+}
+
+// submittingClientID returns the ID of the client that submitted the transaction
+func submittingClientID(ctx contractapi.TransactionContextInterface) (string, error) {
+	clientID, err := ctx.GetClientIdentity().GetID()
+	if err != nil {
+		return "", fmt.Errorf("failed to get client identity: %v", err)
+	}
+
+	return clientID, nil
+}
+
+// verifyUserIdentity returns an error unless the transaction was submitted by the client bound to the
+// user. The same subject can be enrolled by several organizations, so the MSP ID must match as well.
+func verifyUserIdentity(ctx contractapi.TransactionContextInterface, user *User) error {
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	if clientID != user.Identity || mspID != user.MSPID {
+		return fmt.Errorf("submitting client is not authorized to act for user %s", user.ID)
+	}
+
+	return nil
+}
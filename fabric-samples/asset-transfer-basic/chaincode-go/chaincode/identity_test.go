@@ -0,0 +1,43 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIdentityBinding(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("marko", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateUser(transactionContext, "user1", "Marko", "Markovic", "marko.markovic@email.com", 100)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, "marko", user.Identity)
+	require.Equal(t, "Org1MSP", user.MSPID)
+
+	clientIdentity.GetIDReturns("jovan", nil)
+	err = assetTransfer.WithdrawFunds(transactionContext, "user1", 10)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	err = assetTransfer.BindUserIdentity(transactionContext, "user1", "jovan", "")
+	require.EqualError(t, err, "client identity and MSP ID must not be empty")
+	err = assetTransfer.BindUserIdentity(transactionContext, "user1", "jovan", "Org2MSP")
+	require.NoError(t, err)
+	state.get(t, "user1", user)
+	require.Equal(t, "Org2MSP", user.MSPID)
+
+	// the same subject enrolled by another organization is a different client
+	err = assetTransfer.WithdrawFunds(transactionContext, "user1", 10)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	err = assetTransfer.WithdrawFunds(transactionContext, "user1", 10)
+	require.NoError(t, err)
+}
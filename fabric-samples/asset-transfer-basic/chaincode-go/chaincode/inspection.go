@@ -0,0 +1,162 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const inspectionObjectType = "inspection"
+
+// inspectionRequiredConfig is set to 1 when assets may only change hands with a valid inspection certificate
+const inspectionRequiredConfig = "inspectionRequired"
+
+// IssueInspection lets an inspector record the technical inspection of the asset. A passed
+// inspection is valid for validDays days; a failed one ends the certificate the car had.
+// It returns the ID of the new inspection record.
+func (s *SmartContract) IssueInspection(ctx contractapi.TransactionContextInterface, assetID string, inspectorID string, passed bool, validDays int) (string, error) {
+	if passed && validDays <= 0 {
+		return "", fmt.Errorf("validity must be positive")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	inspector, err := s.ReadUser(ctx, inspectorID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, inspector)
+	if err != nil {
+		return "", err
+	}
+	err = requireRole(inspector, RoleInspector)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	inspection := InspectionRecord{
+		ID:          ctx.GetStub().GetTxID(),
+		AssetID:     assetID,
+		InspectorID: inspectorID,
+		Passed:      passed,
+		IssuedAt:    now,
+		ValidUntil:  now,
+	}
+	if passed {
+		inspection.ValidUntil = now.AddDate(0, 0, validDays)
+	}
+	err = putInspection(ctx, &inspection)
+	if err != nil {
+		return "", err
+	}
+
+	asset.InspectionID = inspection.ID
+	asset.InspectionValidUntil = inspection.ValidUntil
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return inspection.ID, nil
+}
+
+// ReadInspection returns the inspection record stored in the world state with given id
+func (s *SmartContract) ReadInspection(ctx contractapi.TransactionContextInterface, inspectionID string) (*InspectionRecord, error) {
+	inspectionKey, err := ctx.GetStub().CreateCompositeKey(inspectionObjectType, []string{inspectionID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	inspectionJSON, err := ctx.GetStub().GetState(inspectionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if inspectionJSON == nil {
+		return nil, fmt.Errorf("the inspection %s does not exist", inspectionID)
+	}
+
+	var inspection InspectionRecord
+	err = json.Unmarshal(inspectionJSON, &inspection)
+	if err != nil {
+		return nil, err
+	}
+
+	return &inspection, nil
+}
+
+// GetLapsedInspections returns the assets that were inspected but whose inspection certificate is
+// no longer valid at the time of the transaction
+func (s *SmartContract) GetLapsedInspections(ctx contractapi.TransactionContextInterface) ([]*Asset, error) {
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.GetAllAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lapsed []*Asset
+	for _, asset := range assets {
+		if asset.InspectionID != "" && !asset.InspectionValidUntil.After(now) {
+			lapsed = append(lapsed, asset)
+		}
+	}
+
+	return lapsed, nil
+}
+
+// SetInspectionRequired turns on or off the rule that assets can only be transferred with a valid
+// inspection certificate. Only admins may change it.
+func (s *SmartContract) SetInspectionRequired(ctx contractapi.TransactionContextInterface, required bool) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	value := int64(0)
+	if required {
+		value = 1
+	}
+
+	return putConfigInt(ctx, inspectionRequiredConfig, value)
+}
+
+// checkInspection returns an error when inspections are required for transfers and the asset has
+// no valid inspection certificate
+func checkInspection(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	required, err := getConfigInt(ctx, inspectionRequiredConfig, 0)
+	if err != nil {
+		return err
+	}
+	if required == 0 {
+		return nil
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if !asset.InspectionValidUntil.After(now) {
+		return fmt.Errorf("the asset %s has no valid inspection certificate", asset.ID)
+	}
+
+	return nil
+}
+
+// putInspection writes the given inspection record to the world state
+func putInspection(ctx contractapi.TransactionContextInterface, inspection *InspectionRecord) error {
+	inspectionKey, err := ctx.GetStub().CreateCompositeKey(inspectionObjectType, []string{inspection.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	inspectionJSON, err := json.Marshal(inspection)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(inspectionKey, inspectionJSON)
+}
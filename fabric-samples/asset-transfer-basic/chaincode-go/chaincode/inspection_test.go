@@ -0,0 +1,52 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspection(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleInspector}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.IssueInspection(transactionContext, "asset1", "user2", true, 365)
+	require.EqualError(t, err, "the user user2 does not have role inspector")
+	chaincodeStub.GetTxIDReturns("inspection1")
+	inspectionID, err := assetTransfer.IssueInspection(transactionContext, "asset1", "user3", true, 365)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("inspection2")
+	_, err = assetTransfer.IssueInspection(transactionContext, "asset2", "user3", true, 30)
+	require.NoError(t, err)
+	inspection, err := assetTransfer.ReadInspection(transactionContext, inspectionID)
+	require.NoError(t, err)
+	require.True(t, inspection.Passed)
+	require.Equal(t, inspection.IssuedAt.AddDate(0, 0, 365), inspection.ValidUntil)
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 60*86400}, nil)
+	lapsed, err := assetTransfer.GetLapsedInspections(transactionContext)
+	require.NoError(t, err)
+	require.Len(t, lapsed, 1)
+	require.Equal(t, "asset2", lapsed[0].ID)
+
+	err = assetTransfer.SetInspectionRequired(transactionContext, true)
+	require.NoError(t, err)
+	err = assetTransfer.GiftAsset(transactionContext, "asset2", "user2")
+	require.EqualError(t, err, "the asset asset2 has no valid inspection certificate")
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+
+	_, err = assetTransfer.IssueInspection(transactionContext, "asset1", "user3", false, 0)
+	require.NoError(t, err)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user1")
+	require.EqualError(t, err, "the asset asset1 has no valid inspection certificate")
+}
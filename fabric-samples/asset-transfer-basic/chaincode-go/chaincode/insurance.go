@@ -0,0 +1,400 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const policyObjectType = "policy"
+
+// insuranceRequiredConfig is set to 1 when assets may only change hands while insured by a policy that transfers to the buyer
+const insuranceRequiredConfig = "insuranceRequired"
+
+// Premium rates used by QuotePremium, in basis points of the appraised value per year of cover
+const (
+	basePremiumRate      = 300
+	premiumRatePerYear   = 25 // for every year of the car's age
+	maxRatedAge          = 20
+	premiumRatePerDamage = 50  // for every damage ever reported on the car
+	premiumRatePerClaim  = 200 // for every damage that was claimed from an insurer
+)
+
+// CreatePolicy lets an insurer offer to insure the asset for termDays days at a time. The
+// PolicyTerms are passed as JSON in the policy_terms transient field and stored in the implicit
+// private data collection of the insurer's organization.
+// The policy takes effect once the owner pays the premium with PayPremium.
+// It returns the ID of the new policy.
+func (s *SmartContract) CreatePolicy(ctx contractapi.TransactionContextInterface, assetID string, insurerID string, termDays int) (string, error) {
+	if termDays <= 0 {
+		return "", fmt.Errorf("term must be positive")
+	}
+	termsJSON, err := transientPolicyTerms(ctx)
+	if err != nil {
+		return "", err
+	}
+	if termsJSON == nil {
+		return "", fmt.Errorf("the policy terms must be passed in the %s transient field", policyTermsTransientKey)
+	}
+	_, err = parsePolicyTerms(termsJSON)
+	if err != nil {
+		return "", err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if insurerID == asset.OwnerID {
+		return "", fmt.Errorf("the insurer %s cannot insure their own asset %s", insurerID, assetID)
+	}
+	insurer, err := s.readInsurer(ctx, insurerID)
+	if err != nil {
+		return "", err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return "", err
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	policy := InsurancePolicy{
+		ID:              ctx.GetStub().GetTxID(),
+		AssetID:         assetID,
+		OwnerID:         asset.OwnerID,
+		InsurerID:       insurer.ID,
+		InsurerMSP:      mspID,
+		TermsCollection: collection,
+		TermsHash:       hashPolicyTerms(termsJSON),
+		TermDays:        termDays,
+		CreatedAt:       now,
+		Status:          PolicyPending,
+	}
+	err = ctx.GetStub().PutPrivateData(collection, policy.ID, termsJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to put policy terms to private data collection %s: %v", collection, err)
+	}
+	err = putPolicy(ctx, &policy)
+	if err != nil {
+		return "", err
+	}
+
+	return policy.ID, nil
+}
+
+// PayPremium lets the owner accept a pending policy by paying its premium to the insurer.
+// The policy covers the asset for one term from now on. Peers outside the insurer's organization
+// need the policy terms passed in the policy_terms transient field.
+func (s *SmartContract) PayPremium(ctx contractapi.TransactionContextInterface, policyID string) error {
+	policy, err := s.ReadPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if policy.Status != PolicyPending {
+		return fmt.Errorf("the policy %s is %s", policyID, policy.Status)
+	}
+	asset, err := s.ReadAsset(ctx, policy.AssetID)
+	if err != nil {
+		return err
+	}
+	if asset.OwnerID != policy.OwnerID {
+		return fmt.Errorf("the asset %s changed owner since policy %s was offered", asset.ID, policyID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.chargePremium(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	policy.Status = PolicyActive
+	policy.ValidFrom = now
+	policy.ValidUntil = now.AddDate(0, 0, policy.TermDays)
+	err = putPolicy(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	asset.PolicyID = policy.ID
+	return putAsset(ctx, asset)
+}
+
+// RenewPolicy lets the owner pay the premium for another term of an active policy. The new term
+// starts when the current one ends, or now if the policy has already expired. Like PayPremium it
+// needs the policy terms in the policy_terms transient field outside the insurer's organization.
+func (s *SmartContract) RenewPolicy(ctx contractapi.TransactionContextInterface, policyID string) error {
+	policy, err := s.ReadPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if policy.Status != PolicyActive {
+		return fmt.Errorf("the policy %s is %s", policyID, policy.Status)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	err = s.chargePremium(ctx, policy)
+	if err != nil {
+		return err
+	}
+
+	if policy.ValidUntil.Before(now) {
+		policy.ValidFrom = now
+		policy.ValidUntil = now
+	}
+	policy.ValidUntil = policy.ValidUntil.AddDate(0, 0, policy.TermDays)
+	return putPolicy(ctx, policy)
+}
+
+// CancelPolicy lets the owner or the insurer end a policy. Premiums already paid are not refunded.
+func (s *SmartContract) CancelPolicy(ctx contractapi.TransactionContextInterface, policyID string) error {
+	policy, err := s.ReadPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	if policy.Status == PolicyCancelled {
+		return fmt.Errorf("the policy %s is %s", policyID, policy.Status)
+	}
+	owner, err := s.ReadUser(ctx, policy.OwnerID)
+	if err != nil {
+		return err
+	}
+	insurer, err := s.ReadUser(ctx, policy.InsurerID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, owner) != nil && verifyUserIdentity(ctx, insurer) != nil {
+		return fmt.Errorf("only the owner or the insurer can cancel policy %s", policyID)
+	}
+
+	policy.Status = PolicyCancelled
+	return putPolicy(ctx, policy)
+}
+
+// QuotePremium returns the yearly premium for insuring the asset, computed from its appraised
+// value, its age, and how many damages were reported on it and claimed from insurers.
+func (s *SmartContract) QuotePremium(ctx contractapi.TransactionContextInterface, assetID string) (int64, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return 0, err
+	}
+	damages, err := s.GetDamages(ctx, assetID)
+	if err != nil {
+		return 0, err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	age := int64(now.Year() - asset.Year)
+	if age < 0 {
+		age = 0
+	}
+	if age > maxRatedAge {
+		age = maxRatedAge
+	}
+	claims := int64(0)
+	for _, damage := range damages {
+		if damage.ClaimID != "" {
+			claims++
+		}
+	}
+
+	value, err := appraisedValue(ctx, asset)
+	if err != nil {
+		return 0, err
+	}
+
+	rate := basePremiumRate + age*premiumRatePerYear + int64(len(damages))*premiumRatePerDamage + claims*premiumRatePerClaim
+	return value * rate / 10000, nil
+}
+
+// ReadPolicy returns the insurance policy stored in the world state with given id.
+func (s *SmartContract) ReadPolicy(ctx contractapi.TransactionContextInterface, policyID string) (*InsurancePolicy, error) {
+	return readPolicy(ctx, policyID)
+}
+
+// SetPolicyTransferable lets the insurer decide whether the policy moves to the buyer when the asset is sold.
+func (s *SmartContract) SetPolicyTransferable(ctx contractapi.TransactionContextInterface, policyID string, transferable bool) error {
+	policy, err := readPolicy(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	_, err = s.readInsurer(ctx, policy.InsurerID)
+	if err != nil {
+		return err
+	}
+
+	policy.Transferable = transferable
+	return putPolicy(ctx, policy)
+}
+
+// SetInsuranceRequired turns on or off the rule that assets can only be transferred while insured by
+// an active policy that transfers to the buyer. Only admins may change it.
+func (s *SmartContract) SetInsuranceRequired(ctx contractapi.TransactionContextInterface, required bool) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	value := int64(0)
+	if required {
+		value = 1
+	}
+
+	return putConfigInt(ctx, insuranceRequiredConfig, value)
+}
+
+// readPolicy returns the insurance policy stored in the world state with given id
+func readPolicy(ctx contractapi.TransactionContextInterface, policyID string) (*InsurancePolicy, error) {
+	policyKey, err := ctx.GetStub().CreateCompositeKey(policyObjectType, []string{policyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	policyJSON, err := ctx.GetStub().GetState(policyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if policyJSON == nil {
+		return nil, fmt.Errorf("the policy %s does not exist", policyID)
+	}
+
+	var policy InsurancePolicy
+	err = json.Unmarshal(policyJSON, &policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &policy, nil
+}
+
+// activePolicy returns the policy covering the asset at the time of the transaction
+func activePolicy(ctx contractapi.TransactionContextInterface, asset *Asset) (*InsurancePolicy, error) {
+	if asset.PolicyID == "" {
+		return nil, fmt.Errorf("the asset %s is not insured", asset.ID)
+	}
+	policy, err := readPolicy(ctx, asset.PolicyID)
+	if err != nil {
+		return nil, err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if policy.Status != PolicyActive || now.Before(policy.ValidFrom) || !now.Before(policy.ValidUntil) {
+		return nil, fmt.Errorf("the asset %s has no active insurance policy", asset.ID)
+	}
+
+	return policy, nil
+}
+
+// transferInsurance hands a transferable active policy of the asset over to the buyer and detaches
+// any other policy from the asset. It fails instead when insurance is required for transfers and
+// the asset would change hands uninsured.
+func transferInsurance(ctx contractapi.TransactionContextInterface, asset *Asset, buyerID string) error {
+	required, err := getConfigInt(ctx, insuranceRequiredConfig, 0)
+	if err != nil {
+		return err
+	}
+	policy, err := activePolicy(ctx, asset)
+	if err != nil {
+		if required != 0 {
+			return err
+		}
+		asset.PolicyID = ""
+		return nil
+	}
+	// an insurer buying the asset cannot insure it themselves
+	if !policy.Transferable || policy.InsurerID == buyerID {
+		if required != 0 {
+			return fmt.Errorf("the insurance policy %s of asset %s does not transfer to the new owner", policy.ID, asset.ID)
+		}
+		asset.PolicyID = ""
+		return nil
+	}
+
+	policy.OwnerID = buyerID
+	return putPolicy(ctx, policy)
+}
+
+// chargePremium moves the premium of the policy from the owner, who must submit the transaction, to the insurer
+func (s *SmartContract) chargePremium(ctx contractapi.TransactionContextInterface, policy *InsurancePolicy) error {
+	// both accounts are written, so they must be different users
+	if policy.OwnerID == policy.InsurerID {
+		return fmt.Errorf("the insurer %s cannot insure their own asset %s", policy.InsurerID, policy.AssetID)
+	}
+	terms, err := readPolicyTerms(ctx, policy)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, policy.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	insurer, err := s.ReadUser(ctx, policy.InsurerID)
+	if err != nil {
+		return err
+	}
+	if owner.Money < terms.Premium {
+		return fmt.Errorf("user %s doesn't have enough money on his account", owner.ID)
+	}
+	err = recordSpending(ctx, owner, terms.Premium)
+	if err != nil {
+		return err
+	}
+
+	owner.Money = owner.Money - terms.Premium
+	insurer.Money = insurer.Money + terms.Premium
+	err = putUser(ctx, owner)
+	if err != nil {
+		return err
+	}
+
+	return putUser(ctx, insurer)
+}
+
+// readInsurer returns the insurer with given ID, provided the insurer submitted the transaction
+func (s *SmartContract) readInsurer(ctx contractapi.TransactionContextInterface, insurerID string) (*User, error) {
+	insurer, err := s.ReadUser(ctx, insurerID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, insurer)
+	if err != nil {
+		return nil, err
+	}
+	err = requireRole(insurer, RoleInsurer)
+	if err != nil {
+		return nil, err
+	}
+
+	return insurer, nil
+}
+
+// putPolicy writes the given insurance policy to the world state
+func putPolicy(ctx contractapi.TransactionContextInterface, policy *InsurancePolicy) error {
+	policyKey, err := ctx.GetStub().CreateCompositeKey(policyObjectType, []string{policy.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	policyJSON, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(policyKey, policyJSON)
+}
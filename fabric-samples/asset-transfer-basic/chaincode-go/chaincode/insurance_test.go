@@ -0,0 +1,132 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInsurancePolicy(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	enrollClients(clientIdentity, map[string]string{"owner": "Org1MSP", "insurer": "Org2MSP"})
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner", MSPID: "Org1MSP"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "insurer", MSPID: "Org2MSP", Roles: []string{chaincode.RoleInsurer}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("insurer", nil)
+	_, err := assetTransfer.CreatePolicy(transactionContext, "asset1", "user2", 365)
+	require.EqualError(t, err, "the policy terms must be passed in the policy_terms transient field")
+
+	terms := []byte(`{"premium":300,"coverage":2500,"deductible":0,"salt":"f00d"}`)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"policy_terms": terms}, nil)
+	clientIdentity.GetIDReturns("owner", nil)
+	_, err = assetTransfer.CreatePolicy(transactionContext, "asset1", "user2", 365)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user2")
+	_, err = assetTransfer.CreatePolicy(transactionContext, "asset1", "user1", 365)
+	require.EqualError(t, err, "the insurer user1 cannot insure their own asset asset1")
+
+	clientIdentity.GetIDReturns("insurer", nil)
+	chaincodeStub.GetTxIDReturns("policy1")
+	policyID, err := assetTransfer.CreatePolicy(transactionContext, "asset1", "user2", 365)
+	require.NoError(t, err)
+	require.Equal(t, terms, state["_implicit_org_Org2MSP/policy1"])
+	verified, err := assetTransfer.VerifyPolicyTerms(transactionContext, policyID, string(terms))
+	require.NoError(t, err)
+	require.True(t, verified)
+	verified, err = assetTransfer.VerifyPolicyTerms(transactionContext, policyID, `{"premium":1,"coverage":2500,"deductible":0,"salt":"f00d"}`)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	// the owner's peer cannot read the insurer's collection, so the owner passes the terms
+	delete(state, "_implicit_org_Org2MSP/policy1")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"policy_terms": []byte(`{"premium":1,"coverage":2500,"deductible":0,"salt":"f00d"}`)}, nil)
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.PayPremium(transactionContext, policyID)
+	require.EqualError(t, err, "the terms do not match the hash of policy policy1")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"policy_terms": terms}, nil)
+
+	err = assetTransfer.PayPremium(transactionContext, policyID)
+	require.NoError(t, err)
+	policy, err := assetTransfer.ReadPolicy(transactionContext, policyID)
+	require.NoError(t, err)
+	start := time.Unix(1600000000, 0).UTC()
+	require.Equal(t, "Org2MSP", policy.InsurerMSP)
+	require.Equal(t, chaincode.PolicyActive, policy.Status)
+	require.Equal(t, start, policy.ValidFrom)
+	require.Equal(t, start.AddDate(0, 0, 365), policy.ValidUntil)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, policyID, asset.PolicyID)
+
+	err = assetTransfer.RenewPolicy(transactionContext, policyID)
+	require.NoError(t, err)
+	policy, err = assetTransfer.ReadPolicy(transactionContext, policyID)
+	require.NoError(t, err)
+	require.Equal(t, start.AddDate(0, 0, 730), policy.ValidUntil)
+
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(400), owner.Money)
+	insurer := &chaincode.User{}
+	state.get(t, "user2", insurer)
+	require.Equal(t, int64(600), insurer.Money)
+
+	err = assetTransfer.CancelPolicy(transactionContext, policyID)
+	require.NoError(t, err)
+	err = assetTransfer.RenewPolicy(transactionContext, policyID)
+	require.EqualError(t, err, "the policy policy1 is cancelled")
+}
+
+func TestQuotePremium(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", Year: 2015, AppraisedValue: 1000000})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage1\x00", &chaincode.Damage{ID: "damage1", AssetID: "asset1", Cost: 400})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage2\x00", &chaincode.Damage{ID: "damage2", AssetID: "asset1", Cost: 900, ClaimID: "claim1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	premium, err := assetTransfer.QuotePremium(transactionContext, "asset1")
+	require.NoError(t, err)
+	// 300 base + 5 years * 25 + 2 damages * 50 + 1 claim * 200 = 725 basis points
+	require.Equal(t, int64(72500), premium)
+}
+
+func TestTransferRequiresInsurance(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Status: chaincode.UserActive, Roles: []string{chaincode.RoleInsurer}})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Money: 5000, Status: chaincode.UserActive})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000, PolicyID: "policy1"})
+	state.put(t, "\x00policy\x00policy1\x00", &chaincode.InsurancePolicy{
+		ID:         "policy1",
+		AssetID:    "asset1",
+		OwnerID:    "user1",
+		InsurerID:  "user2",
+		Status:     chaincode.PolicyActive,
+		ValidFrom:  time.Unix(1590000000, 0).UTC(),
+		ValidUntil: time.Unix(1590000000, 0).UTC().AddDate(1, 0, 0),
+	})
+
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.SetInsuranceRequired(transactionContext, true))
+	err := assetTransfer.TransferAsset(transactionContext, "asset1", "user3", false, 2000)
+	require.EqualError(t, err, "the insurance policy policy1 of asset asset1 does not transfer to the new owner")
+
+	require.NoError(t, assetTransfer.SetPolicyTransferable(transactionContext, "policy1", true))
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user3", false, 2000)
+	require.NoError(t, err)
+	policy, err := assetTransfer.ReadPolicy(transactionContext, "policy1")
+	require.NoError(t, err)
+	require.Equal(t, "user3", policy.OwnerID)
+
+	require.NoError(t, assetTransfer.CancelPolicy(transactionContext, "policy1"))
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user1", false, 0)
+	require.EqualError(t, err, "the asset asset1 has no active insurance policy")
+}
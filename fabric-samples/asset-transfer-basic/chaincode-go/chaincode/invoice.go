@@ -0,0 +1,140 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	invoiceObjectType   = "asset~invoice"
+	mechanicInvoiceName = "mechanic~invoice"
+)
+
+// GetInvoicesForAsset returns all invoices for repairs of asset with given ID
+func (s *SmartContract) GetInvoicesForAsset(ctx contractapi.TransactionContextInterface, assetID string) ([]*Invoice, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(invoiceObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var invoices []*Invoice
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var invoice Invoice
+		err = json.Unmarshal(queryResponse.Value, &invoice)
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, &invoice)
+	}
+
+	return invoices, nil
+}
+
+// GetInvoicesForMechanic returns all invoices issued for repairs done by the mechanic with given ID
+func (s *SmartContract) GetInvoicesForMechanic(ctx contractapi.TransactionContextInterface, mechanicID string) ([]*Invoice, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(mechanicInvoiceName, []string{mechanicID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var invoices []*Invoice
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		_, attributes, err := ctx.GetStub().SplitCompositeKey(queryResponse.Key)
+		if err != nil {
+			return nil, err
+		}
+		invoice, err := s.ReadInvoice(ctx, string(queryResponse.Value), attributes[1])
+		if err != nil {
+			return nil, err
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	return invoices, nil
+}
+
+// ReadInvoice returns the invoice with given ID issued for asset with given ID
+func (s *SmartContract) ReadInvoice(ctx contractapi.TransactionContextInterface, assetID string, invoiceID string) (*Invoice, error) {
+	invoiceKey, err := ctx.GetStub().CreateCompositeKey(invoiceObjectType, []string{assetID, invoiceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	invoiceJSON, err := ctx.GetStub().GetState(invoiceKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if invoiceJSON == nil {
+		return nil, fmt.Errorf("the invoice %s does not exist for asset %s", invoiceID, assetID)
+	}
+
+	var invoice Invoice
+	err = json.Unmarshal(invoiceJSON, &invoice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+// issueInvoice writes the invoice for the paid repair job, listing each damage it repaired
+func (s *SmartContract) issueInvoice(ctx contractapi.TransactionContextInterface, job *RepairJob) error {
+	issuedAt, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	invoice := Invoice{
+		ID:         ctx.GetStub().GetTxID(),
+		JobID:      job.ID,
+		AssetID:    job.AssetID,
+		OwnerID:    job.OwnerID,
+		MechanicID: job.MechanicID,
+		Items:      []InvoiceItem{},
+		Total:      job.Price,
+		IssuedAt:   issuedAt,
+	}
+	for _, damageID := range job.DamageIDs {
+		if damageID == "" {
+			// damages reported before they had their own keys have no record to list
+			continue
+		}
+		damage, err := s.ReadDamage(ctx, job.AssetID, damageID)
+		if err != nil {
+			return err
+		}
+		invoice.Items = append(invoice.Items, InvoiceItem{DamageID: damage.ID, Description: damage.Description, Cost: damage.Cost})
+	}
+
+	invoiceKey, err := ctx.GetStub().CreateCompositeKey(invoiceObjectType, []string{invoice.AssetID, invoice.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	invoiceJSON, err := json.Marshal(invoice)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(invoiceKey, invoiceJSON)
+	if err != nil {
+		return err
+	}
+
+	indexKey, err := ctx.GetStub().CreateCompositeKey(mechanicInvoiceName, []string{invoice.MechanicID, invoice.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte(invoice.AssetID))
+}
@@ -0,0 +1,71 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairInvoice(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTxIDReturns("damage1")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 400))
+	chaincodeStub.GetTxIDReturns("damage2")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "mirror", 100))
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("quote1")
+	quoteID, err := assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user3", 450)
+	require.NoError(t, err)
+	require.NoError(t, assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID))
+	require.NoError(t, assetTransfer.StartRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.CompleteRepair(transactionContext, jobID))
+	chaincodeStub.GetTxIDReturns("payment1")
+	require.NoError(t, assetTransfer.PayRepair(transactionContext, jobID))
+
+	invoice, err := assetTransfer.ReadInvoice(transactionContext, "asset1", "payment1")
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.Invoice{
+		ID:         "payment1",
+		JobID:      "job1",
+		AssetID:    "asset1",
+		OwnerID:    "user1",
+		MechanicID: "user3",
+		Items: []chaincode.InvoiceItem{
+			{DamageID: "damage1", Description: "tyre", Cost: 400},
+			{DamageID: "damage2", Description: "mirror", Cost: 100},
+		},
+		Total:    450,
+		IssuedAt: time.Unix(1600000000, 0).UTC(),
+	}, invoice)
+	require.Equal(t, []byte("asset1"), state["\x00mechanic~invoice\x00user3\x00payment1\x00"])
+}
+
+func TestGetInvoicesForMechanic(t *testing.T) {
+	state := worldState{}
+	state.put(t, "\x00asset~invoice\x00asset1\x00payment1\x00", &chaincode.Invoice{ID: "payment1", AssetID: "asset1", MechanicID: "user3", Total: 450})
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Key: "\x00mechanic~invoice\x00user3\x00payment1\x00", Value: []byte("asset1")}, nil)
+
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(iterator, nil)
+	chaincodeStub.SplitCompositeKeyReturns("mechanic~invoice", []string{"user3", "payment1"}, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	invoices, err := assetTransfer.GetInvoicesForMechanic(transactionContext, "user3")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.Invoice{{ID: "payment1", AssetID: "asset1", MechanicID: "user3", Total: 450}}, invoices)
+}
@@ -0,0 +1,56 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// kycThresholdConfig is the sale price, in cents, above which buyers must be KYC verified; 0 turns the check off
+const kycThresholdConfig = "kycThreshold"
+
+// SetKYCVerified lets a regulator record whether the identity of the user with given ID has been
+// verified. Regulators cannot verify themselves.
+func (s *SmartContract) SetKYCVerified(ctx contractapi.TransactionContextInterface, userID string, regulatorID string, verified bool) error {
+	if userID == regulatorID {
+		return fmt.Errorf("the regulator %s cannot verify themselves", regulatorID)
+	}
+	_, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return err
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	user.KYCVerified = verified
+	return putUser(ctx, user)
+}
+
+// SetKYCThreshold sets the sale price, in cents, above which only KYC verified users may buy assets.
+// Zero lets everyone buy at any price. Only admins may set it.
+func (s *SmartContract) SetKYCThreshold(ctx contractapi.TransactionContextInterface, threshold int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if threshold < 0 {
+		return fmt.Errorf("threshold must not be negative")
+	}
+
+	return putConfigInt(ctx, kycThresholdConfig, threshold)
+}
+
+// checkKYC returns an error when the price is above the KYC threshold and the buyer is not verified
+func checkKYC(ctx contractapi.TransactionContextInterface, buyer *User, price int64) error {
+	threshold, err := getConfigInt(ctx, kycThresholdConfig, 0)
+	if err != nil {
+		return err
+	}
+	if threshold > 0 && price > threshold && !buyer.KYCVerified {
+		return fmt.Errorf("the user %s must be KYC verified to buy for more than %d", buyer.ID, threshold)
+	}
+
+	return nil
+}
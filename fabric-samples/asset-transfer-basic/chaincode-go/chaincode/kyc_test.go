@@ -0,0 +1,39 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKYCThreshold(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user7", &chaincode.User{ID: "user7", Identity: "regulator", Status: chaincode.UserActive, Roles: []string{chaincode.RoleRegulator}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetKYCThreshold(transactionContext, 2000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2500)
+	require.EqualError(t, err, "the user user2 must be KYC verified to buy for more than 2000")
+	err = assetTransfer.SetKYCVerified(transactionContext, "user2", "user1", true)
+	require.EqualError(t, err, "the user user1 does not have role regulator")
+
+	clientIdentity.GetIDReturns("regulator", nil)
+	err = assetTransfer.SetKYCVerified(transactionContext, "user7", "user7", true)
+	require.EqualError(t, err, "the regulator user7 cannot verify themselves")
+	err = assetTransfer.SetKYCVerified(transactionContext, "user2", "user7", true)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2500)
+	require.NoError(t, err)
+}
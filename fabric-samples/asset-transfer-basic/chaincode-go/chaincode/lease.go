@@ -0,0 +1,299 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const leaseObjectType = "lease"
+
+// leasePeriod is the time between lease installments. Months are counted as 30 days so that the
+// due dates only depend on the transaction timestamps.
+const leasePeriod = 30 * 24 * time.Hour
+
+// OfferLease lets the owner of the asset offer it for lease to the lessee for termMonths monthly
+// installments of monthlyAmount. It returns the ID of the new lease.
+func (s *SmartContract) OfferLease(ctx contractapi.TransactionContextInterface, assetID string, lesseeID string, monthlyAmount int64, termMonths int) (string, error) {
+	if monthlyAmount <= 0 || termMonths <= 0 {
+		return "", fmt.Errorf("monthly amount and term must be positive")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.OwnerID == lesseeID {
+		return "", fmt.Errorf("the owner cannot lease their own asset")
+	}
+	lessor, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, lessor)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.ReadUser(ctx, lesseeID)
+	if err != nil {
+		return "", err
+	}
+
+	lease := Lease{
+		ID:            ctx.GetStub().GetTxID(),
+		AssetID:       assetID,
+		LessorID:      lessor.ID,
+		LesseeID:      lesseeID,
+		MonthlyAmount: monthlyAmount,
+		Term:          termMonths,
+		Status:        LeaseOffered,
+	}
+	err = putLease(ctx, &lease)
+	if err != nil {
+		return "", err
+	}
+
+	return lease.ID, nil
+}
+
+// AcceptLease lets the lessee accept an offered lease. The first installment is due one month later.
+func (s *SmartContract) AcceptLease(ctx contractapi.TransactionContextInterface, leaseID string) error {
+	lease, err := s.ReadLease(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	if lease.Status != LeaseOffered {
+		return fmt.Errorf("the lease %s is %s", leaseID, lease.Status)
+	}
+	lessee, err := s.ReadUser(ctx, lease.LesseeID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, lessee)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(lessee)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, lease.AssetID)
+	if err != nil {
+		return err
+	}
+	if asset.OwnerID != lease.LessorID {
+		return fmt.Errorf("the asset %s is not owned by user %s", asset.ID, lease.LessorID)
+	}
+	if asset.Encumbered {
+		return fmt.Errorf("the asset %s is encumbered and cannot be leased", asset.ID)
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	asset.Encumbered = true
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	lease.Status = LeaseActive
+	lease.StartedAt = now
+	lease.NextDueAt = now.Add(leasePeriod)
+	return putLease(ctx, lease)
+}
+
+// PayLeaseInstallment lets the lessee pay the next monthly installment of an active or delinquent
+// lease to the lessor. Paying the last installment completes the lease and releases the asset.
+func (s *SmartContract) PayLeaseInstallment(ctx contractapi.TransactionContextInterface, leaseID string) error {
+	lease, err := s.ReadLease(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	if lease.Status != LeaseActive && lease.Status != LeaseDelinquent {
+		return fmt.Errorf("the lease %s is %s", leaseID, lease.Status)
+	}
+	lessee, err := s.ReadUser(ctx, lease.LesseeID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, lessee)
+	if err != nil {
+		return err
+	}
+	lessor, err := s.ReadUser(ctx, lease.LessorID)
+	if err != nil {
+		return err
+	}
+	if lessee.Money < lease.MonthlyAmount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", lessee.ID)
+	}
+	err = recordSpending(ctx, lessee, lease.MonthlyAmount)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	lessee.Money = lessee.Money - lease.MonthlyAmount
+	lessor.Money = lessor.Money + lease.MonthlyAmount
+	err = putUser(ctx, lessee)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, lessor)
+	if err != nil {
+		return err
+	}
+
+	lease.PaymentsMade++
+	lease.NextDueAt = lease.NextDueAt.Add(leasePeriod)
+	if lease.PaymentsMade < lease.Term {
+		lease.Status = leaseStatus(lease, now)
+		return putLease(ctx, lease)
+	}
+
+	lease.Status = LeaseCompleted
+	err = s.releaseLeasedAsset(ctx, lease)
+	if err != nil {
+		return err
+	}
+	return putLease(ctx, lease)
+}
+
+// CheckLeaseDelinquency flags the lease delinquent when an installment is past due and emits a
+// LeaseDelinquent event. Anyone may call it; it returns whether the lease is delinquent.
+func (s *SmartContract) CheckLeaseDelinquency(ctx contractapi.TransactionContextInterface, leaseID string) (bool, error) {
+	lease, err := s.ReadLease(ctx, leaseID)
+	if err != nil {
+		return false, err
+	}
+	if lease.Status != LeaseActive {
+		return lease.Status == LeaseDelinquent, nil
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return false, err
+	}
+	if leaseStatus(lease, now) != LeaseDelinquent {
+		return false, nil
+	}
+
+	lease.Status = LeaseDelinquent
+	err = putLease(ctx, lease)
+	if err != nil {
+		return false, err
+	}
+	eventJSON, err := json.Marshal(LeaseDelinquentEvent{LeaseID: lease.ID, AssetID: lease.AssetID, LesseeID: lease.LesseeID, NextDueAt: lease.NextDueAt})
+	if err != nil {
+		return false, err
+	}
+	err = ctx.GetStub().SetEvent("LeaseDelinquent", eventJSON)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// GetActiveLeases returns the running leases where the user with given ID is the lessor or the
+// lessee. Leases with a missed installment are reported delinquent even before anyone flagged them.
+func (s *SmartContract) GetActiveLeases(ctx contractapi.TransactionContextInterface, userID string) ([]*Lease, error) {
+	now, err := txTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(leaseObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var leases []*Lease
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var lease Lease
+		err = json.Unmarshal(queryResponse.Value, &lease)
+		if err != nil {
+			return nil, err
+		}
+		if lease.Status != LeaseActive && lease.Status != LeaseDelinquent {
+			continue
+		}
+		if lease.LessorID == userID || lease.LesseeID == userID {
+			lease.Status = leaseStatus(&lease, now)
+			leases = append(leases, &lease)
+		}
+	}
+
+	return leases, nil
+}
+
+// ReadLease returns the lease stored in the world state with given id.
+func (s *SmartContract) ReadLease(ctx contractapi.TransactionContextInterface, leaseID string) (*Lease, error) {
+	leaseKey, err := ctx.GetStub().CreateCompositeKey(leaseObjectType, []string{leaseID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	leaseJSON, err := ctx.GetStub().GetState(leaseKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if leaseJSON == nil {
+		return nil, fmt.Errorf("the lease %s does not exist", leaseID)
+	}
+
+	var lease Lease
+	err = json.Unmarshal(leaseJSON, &lease)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// leaseStatus returns whether the running lease is active or delinquent at given time
+func leaseStatus(lease *Lease, now time.Time) string {
+	if now.After(lease.NextDueAt) {
+		return LeaseDelinquent
+	}
+
+	return LeaseActive
+}
+
+// releaseLeasedAsset lifts the encumbrance the lease put on the asset
+func (s *SmartContract) releaseLeasedAsset(ctx contractapi.TransactionContextInterface, lease *Lease) error {
+	asset, err := s.ReadAsset(ctx, lease.AssetID)
+	if err != nil {
+		return err
+	}
+
+	asset.Encumbered = false
+	return putAsset(ctx, asset)
+}
+
+// putLease writes the given lease to the world state
+func putLease(ctx contractapi.TransactionContextInterface, lease *Lease) error {
+	leaseKey, err := ctx.GetStub().CreateCompositeKey(leaseObjectType, []string{lease.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	leaseJSON, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(leaseKey, leaseJSON)
+}
@@ -0,0 +1,72 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+const month = 30 * 24 * 60 * 60
+
+func TestLease(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "lessor"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "lessee", Money: 1000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("lessor", nil)
+	chaincodeStub.GetTxIDReturns("lease1")
+	leaseID, err := assetTransfer.OfferLease(transactionContext, "asset1", "user2", 400, 2)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("lessee", nil)
+	err = assetTransfer.AcceptLease(transactionContext, leaseID)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.True(t, asset.Encumbered)
+
+	leases, err := assetTransfer.GetActiveLeases(transactionContext, "user2")
+	require.NoError(t, err)
+	require.Len(t, leases, 1)
+	require.Equal(t, chaincode.LeaseActive, leases[0].Status)
+
+	// the first installment is missed
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + month + 1}, nil)
+	leases, err = assetTransfer.GetActiveLeases(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LeaseDelinquent, leases[0].Status)
+	delinquent, err := assetTransfer.CheckLeaseDelinquency(transactionContext, leaseID)
+	require.NoError(t, err)
+	require.True(t, delinquent)
+	name, _ := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "LeaseDelinquent", name)
+
+	err = assetTransfer.PayLeaseInstallment(transactionContext, leaseID)
+	require.NoError(t, err)
+	lease, err := assetTransfer.ReadLease(transactionContext, leaseID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LeaseActive, lease.Status)
+	require.Equal(t, 1, lease.PaymentsMade)
+
+	err = assetTransfer.PayLeaseInstallment(transactionContext, leaseID)
+	require.NoError(t, err)
+	lease, err = assetTransfer.ReadLease(transactionContext, leaseID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LeaseCompleted, lease.Status)
+	state.get(t, "asset1", asset)
+	require.False(t, asset.Encumbered)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(800), user.Money)
+
+	err = assetTransfer.PayLeaseInstallment(transactionContext, leaseID)
+	require.EqualError(t, err, "the lease lease1 is completed")
+}
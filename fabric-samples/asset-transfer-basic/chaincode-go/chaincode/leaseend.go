@@ -0,0 +1,142 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const leaseTerminationPenaltyConfig = "leaseTerminationPenalty"
+
+// SetLeaseTerminationPenalty sets the share of the unpaid installments a lessee pays when ending a
+// lease early, in basis points. Only admins may change the penalty.
+func (s *SmartContract) SetLeaseTerminationPenalty(ctx contractapi.TransactionContextInterface, basisPoints int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if basisPoints < 0 || basisPoints > 10000 {
+		return fmt.Errorf("penalty must be between 0 and 10000 basis points")
+	}
+
+	return putConfigInt(ctx, leaseTerminationPenaltyConfig, basisPoints)
+}
+
+// SetLeaseResidualValue lets the lessor set the residual value of an offered lease, so the lessee
+// knows the buyout price before accepting.
+func (s *SmartContract) SetLeaseResidualValue(ctx contractapi.TransactionContextInterface, leaseID string, residualValue int64) error {
+	if residualValue < 0 {
+		return fmt.Errorf("residual value must not be negative")
+	}
+	lease, err := s.ReadLease(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	if lease.Status != LeaseOffered {
+		return fmt.Errorf("the lease %s is %s", leaseID, lease.Status)
+	}
+	lessor, err := s.ReadUser(ctx, lease.LessorID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, lessor)
+	if err != nil {
+		return err
+	}
+
+	lease.ResidualValue = residualValue
+	return putLease(ctx, lease)
+}
+
+// TerminateLease lets the lessee end a running lease early. The lessee pays the lessor the
+// termination penalty on the unpaid installments and the asset is released.
+func (s *SmartContract) TerminateLease(ctx contractapi.TransactionContextInterface, leaseID string) error {
+	lease, lessee, lessor, err := s.readRunningLease(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	rate, err := getConfigInt(ctx, leaseTerminationPenaltyConfig, 0)
+	if err != nil {
+		return err
+	}
+	penalty := unpaidInstallments(lease) * rate / 10000
+	if lessee.Money < penalty {
+		return fmt.Errorf("user %s doesn't have enough money on his account", lessee.ID)
+	}
+	err = recordSpending(ctx, lessee, penalty)
+	if err != nil {
+		return err
+	}
+
+	lessee.Money = lessee.Money - penalty
+	lessor.Money = lessor.Money + penalty
+	err = putUser(ctx, lessee)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, lessor)
+	if err != nil {
+		return err
+	}
+	err = s.releaseLeasedAsset(ctx, lease)
+	if err != nil {
+		return err
+	}
+
+	lease.Status = LeaseTerminated
+	return putLease(ctx, lease)
+}
+
+// BuyOutLease lets the lessee buy the leased asset for its residual value plus the unpaid
+// installments. Ownership changes and the lease closes in the same transaction.
+func (s *SmartContract) BuyOutLease(ctx contractapi.TransactionContextInterface, leaseID string) error {
+	lease, lessee, lessor, err := s.readRunningLease(ctx, leaseID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, lease.AssetID)
+	if err != nil {
+		return err
+	}
+
+	// the lease's own encumbrance must not block the sale
+	asset.Encumbered = false
+	err = executeSale(ctx, asset, lessor, lessee, lease.ResidualValue+unpaidInstallments(lease))
+	if err != nil {
+		return err
+	}
+
+	lease.Status = LeaseBoughtOut
+	return putLease(ctx, lease)
+}
+
+// readRunningLease returns an active or delinquent lease with its lessee and lessor after checking
+// that the submitting client acts for the lessee
+func (s *SmartContract) readRunningLease(ctx contractapi.TransactionContextInterface, leaseID string) (*Lease, *User, *User, error) {
+	lease, err := s.ReadLease(ctx, leaseID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if lease.Status != LeaseActive && lease.Status != LeaseDelinquent {
+		return nil, nil, nil, fmt.Errorf("the lease %s is %s", leaseID, lease.Status)
+	}
+	lessee, err := s.ReadUser(ctx, lease.LesseeID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	err = verifyUserIdentity(ctx, lessee)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	lessor, err := s.ReadUser(ctx, lease.LessorID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return lease, lessee, lessor, nil
+}
+
+// unpaidInstallments returns the sum of the installments of the lease still to be paid, in cents
+func unpaidInstallments(lease *Lease) int64 {
+	return int64(lease.Term-lease.PaymentsMade) * lease.MonthlyAmount
+}
@@ -0,0 +1,82 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+// startLease puts an accepted lease of asset1 from user1 to user2 into the state
+func startLease(t *testing.T, state worldState) {
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "lessor"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "lessee", Money: 10000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Encumbered: true})
+	state.put(t, "\x00lease\x00lease1\x00", &chaincode.Lease{ID: "lease1", AssetID: "asset1", LessorID: "user1", LesseeID: "user2",
+		MonthlyAmount: 500, Term: 12, PaymentsMade: 2, ResidualValue: 4000, Status: chaincode.LeaseActive})
+}
+
+func TestTerminateLease(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	startLease(t, state)
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetLeaseTerminationPenalty(transactionContext, 2000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("lessor", nil)
+	err = assetTransfer.TerminateLease(transactionContext, "lease1")
+	require.Error(t, err)
+
+	clientIdentity.GetIDReturns("lessee", nil)
+	err = assetTransfer.TerminateLease(transactionContext, "lease1")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(9000), user.Money)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.False(t, asset.Encumbered)
+	require.Equal(t, "user1", asset.OwnerID)
+	lease, err := assetTransfer.ReadLease(transactionContext, "lease1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LeaseTerminated, lease.Status)
+}
+
+func TestBuyOutLease(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	startLease(t, state)
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("lessor", nil)
+	err := assetTransfer.SetLeaseResidualValue(transactionContext, "lease1", 3000)
+	require.EqualError(t, err, "the lease lease1 is active")
+
+	// the residual value plus ten unpaid installments
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	user.Money = 8999
+	state.put(t, "user2", user)
+	clientIdentity.GetIDReturns("lessee", nil)
+	err = assetTransfer.BuyOutLease(transactionContext, "lease1")
+	require.EqualError(t, err, "Customer doesn't have enough money on his account")
+
+	user.Money = 9000
+	state.put(t, "user2", user)
+	err = assetTransfer.BuyOutLease(transactionContext, "lease1")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.False(t, asset.Encumbered)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(0), user.Money)
+	lease, err := assetTransfer.ReadLease(transactionContext, "lease1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LeaseBoughtOut, lease.Status)
+}
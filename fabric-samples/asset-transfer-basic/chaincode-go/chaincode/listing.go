@@ -0,0 +1,256 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const listingObjectType = "listing"
+
+// ListForSale lets the owner publish the asset for sale at askingPrice. When the listing is
+// brokered by a dealer, dealerID names the dealer who earns a commission; leave it empty otherwise.
+func (s *SmartContract) ListForSale(ctx contractapi.TransactionContextInterface, assetID string, askingPrice int64, dealerID string) error {
+	if askingPrice < 0 {
+		return fmt.Errorf("asking price must not be negative")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return err
+	}
+	listing, err := readListing(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if listing != nil && listing.Status == ListingOpen && listing.SellerID == asset.OwnerID {
+		return fmt.Errorf("the asset %s is already listed", assetID)
+	}
+	_, err = s.readDealer(ctx, dealerID, asset)
+	if err != nil {
+		return err
+	}
+
+	return putListing(ctx, &Listing{
+		AssetID:     assetID,
+		SellerID:    seller.ID,
+		AskingPrice: askingPrice,
+		DealerID:    dealerID,
+		Status:      ListingOpen,
+	})
+}
+
+// Unlist lets the owner withdraw the listing of the asset.
+func (s *SmartContract) Unlist(ctx contractapi.TransactionContextInterface, assetID string) error {
+	listing, err := readOpenListing(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, listing.SellerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return err
+	}
+
+	listing.Status = ListingWithdrawn
+	return putListing(ctx, listing)
+}
+
+// BuyListedAsset lets the buyer pay the asking price of a listed asset and take ownership, closing the listing.
+func (s *SmartContract) BuyListedAsset(ctx contractapi.TransactionContextInterface, assetID string, buyerID string) error {
+	listing, err := readOpenListing(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if listing.SellerID == buyerID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, listing.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	dealer, err := s.readDealer(ctx, listing.DealerID, asset, buyer.ID)
+	if err != nil {
+		return err
+	}
+
+	err = settleSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, dealer: dealer, price: listing.AskingPrice, upfront: listing.AskingPrice})
+	if err != nil {
+		return err
+	}
+
+	listing.Status = ListingSold
+	listing.BuyerID = buyerID
+	return putListing(ctx, listing)
+}
+
+// TradeInPurchase buys the listed sellerAssetID, paying partly with the buyer's buyerAssetID which is taken
+// in at its appraised value. Both ownerships change and only the net difference is paid, by the buyer when
+// the listed car is worth more and by the seller otherwise.
+func (s *SmartContract) TradeInPurchase(ctx contractapi.TransactionContextInterface, buyerAssetID string, sellerAssetID string) error {
+	listing, err := readOpenListing(ctx, sellerAssetID)
+	if err != nil {
+		return err
+	}
+	tradeIn, err := s.ReadAsset(ctx, buyerAssetID)
+	if err != nil {
+		return err
+	}
+	if tradeIn.OwnerID == listing.SellerID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	buyer, err := s.ReadUser(ctx, tradeIn.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, listing.SellerID)
+	if err != nil {
+		return err
+	}
+	purchased, err := s.ReadAsset(ctx, sellerAssetID)
+	if err != nil {
+		return err
+	}
+	dealer, err := s.readDealer(ctx, listing.DealerID, purchased, buyer.ID)
+	if err != nil {
+		return err
+	}
+	if dealer != nil {
+		err = checkDealerNotParty(dealer.ID, tradeIn)
+		if err != nil {
+			return err
+		}
+	}
+
+	tradeInValue, err := appraisedValue(ctx, tradeIn)
+	if err != nil {
+		return err
+	}
+
+	buyerPays, sellerPays := listing.AskingPrice-tradeInValue, int64(0)
+	if buyerPays < 0 {
+		buyerPays, sellerPays = 0, -buyerPays
+	}
+	err = settleSale(ctx, &saleTerms{asset: tradeIn, seller: buyer, buyer: seller, price: tradeInValue, upfront: sellerPays})
+	if err != nil {
+		return err
+	}
+	err = settleSale(ctx, &saleTerms{asset: purchased, seller: seller, buyer: buyer, dealer: dealer, price: listing.AskingPrice, upfront: buyerPays})
+	if err != nil {
+		return err
+	}
+
+	listing.Status = ListingSold
+	listing.BuyerID = buyer.ID
+	return putListing(ctx, listing)
+}
+
+// GetOpenListings returns all assets currently listed for sale
+func (s *SmartContract) GetOpenListings(ctx contractapi.TransactionContextInterface) ([]*Listing, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(listingObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var listings []*Listing
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var listing Listing
+		err = json.Unmarshal(queryResponse.Value, &listing)
+		if err != nil {
+			return nil, err
+		}
+		if listing.Status == ListingOpen {
+			listings = append(listings, &listing)
+		}
+	}
+
+	return listings, nil
+}
+
+// readListing returns the latest listing of the asset, or nil when it was never listed
+func readListing(ctx contractapi.TransactionContextInterface, assetID string) (*Listing, error) {
+	listingKey, err := ctx.GetStub().CreateCompositeKey(listingObjectType, []string{assetID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	listingJSON, err := ctx.GetStub().GetState(listingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if listingJSON == nil {
+		return nil, nil
+	}
+
+	var listing Listing
+	err = json.Unmarshal(listingJSON, &listing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &listing, nil
+}
+
+// readOpenListing returns the listing of the asset and fails unless it is open
+func readOpenListing(ctx contractapi.TransactionContextInterface, assetID string) (*Listing, error) {
+	listing, err := readListing(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if listing == nil || listing.Status != ListingOpen {
+		return nil, fmt.Errorf("the asset %s is not listed for sale", assetID)
+	}
+
+	return listing, nil
+}
+
+// putListing writes the given listing to the world state
+func putListing(ctx contractapi.TransactionContextInterface, listing *Listing) error {
+	listingKey, err := ctx.GetStub().CreateCompositeKey(listingObjectType, []string{listing.AssetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	listingJSON, err := json.Marshal(listing)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(listingKey, listingJSON)
+}
@@ -0,0 +1,130 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListAndBuy(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.BuyListedAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "the asset asset1 is not listed for sale")
+
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 4500, "")
+	require.NoError(t, err)
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 4000, "")
+	require.EqualError(t, err, "the asset asset1 is already listed")
+
+	err = assetTransfer.BuyListedAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(4500), seller.Money)
+	listing := &chaincode.Listing{}
+	state.get(t, "\x00listing\x00asset1\x00", listing)
+	require.Equal(t, &chaincode.Listing{AssetID: "asset1", SellerID: "user1", AskingPrice: 4500, Status: chaincode.ListingSold, BuyerID: "user2"}, listing)
+
+	err = assetTransfer.Unlist(transactionContext, "asset1")
+	require.EqualError(t, err, "the asset asset1 is not listed for sale")
+}
+
+func TestGetOpenListings(t *testing.T) {
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, true)
+	iterator.HasNextReturnsOnCall(2, false)
+	iterator.NextReturnsOnCall(0, &queryresult.KV{Value: []byte(`{"assetID":"asset1","status":"open"}`)}, nil)
+	iterator.NextReturnsOnCall(1, &queryresult.KV{Value: []byte(`{"assetID":"asset2","status":"sold"}`)}, nil)
+
+	transactionContext, chaincodeStub := prepMocks(worldState{})
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(iterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	listings, err := assetTransfer.GetOpenListings(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.Listing{{AssetID: "asset1", Status: chaincode.ListingOpen}}, listings)
+}
+
+func TestBrokeredListing(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleDealer}})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Roles: []string{chaincode.RoleOwner}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetDealerCommissionRate(transactionContext, 500)
+	require.NoError(t, err)
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 4000, "user4")
+	require.EqualError(t, err, "the user user4 does not have role dealer")
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 4000, "user1")
+	require.EqualError(t, err, "the dealer user1 cannot be a party to the sale of asset asset1")
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1", CoOwners: []chaincode.OwnershipShare{{UserID: "user1", Share: 5000}, {UserID: "user3", Share: 5000}}})
+	err = assetTransfer.ListForSale(transactionContext, "asset2", 4000, "user3")
+	require.EqualError(t, err, "the dealer user3 cannot be a party to the sale of asset asset2")
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 4000, "user3")
+	require.NoError(t, err)
+
+	err = assetTransfer.BuyListedAsset(transactionContext, "asset1", "user3")
+	require.EqualError(t, err, "the dealer user3 cannot be a party to the sale of asset asset1")
+	_, err = assetTransfer.OfferAsset(transactionContext, "asset1", "user2", 4000, "user2")
+	require.EqualError(t, err, "the dealer user2 cannot be a party to the sale of asset asset1")
+
+	err = assetTransfer.BuyListedAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(3800), seller.Money)
+	dealer := &chaincode.User{}
+	state.get(t, "user3", dealer)
+	require.Equal(t, int64(200), dealer.Money)
+	transfer := &chaincode.TransferRecord{}
+	state.get(t, "\x00transfer\x00asset1\x00tx1\x00", transfer)
+	require.Equal(t, "user3", transfer.DealerID)
+	require.Equal(t, int64(200), transfer.Commission)
+}
+
+func TestTradeInPurchase(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 0})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 1500})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 5000})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user2", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.ListForSale(transactionContext, "asset1", 4500, "")
+	require.NoError(t, err)
+
+	err = assetTransfer.TradeInPurchase(transactionContext, "asset2", "asset1")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	state.get(t, "asset2", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(1500), user.Money)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(0), user.Money)
+
+	err = assetTransfer.TradeInPurchase(transactionContext, "asset1", "asset2")
+	require.EqualError(t, err, "the asset asset2 is not listed for sale")
+}
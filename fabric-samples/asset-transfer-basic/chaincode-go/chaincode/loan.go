@@ -0,0 +1,262 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const loanObjectType = "loan"
+
+// OfferLoan lets the lender offer the borrower amount towards buying the asset. It returns the ID
+// of the new loan which the borrower passes to AcceptLoan.
+func (s *SmartContract) OfferLoan(ctx contractapi.TransactionContextInterface, lenderID string, borrowerID string, assetID string, amount int64) (string, error) {
+	if amount <= 0 {
+		return "", fmt.Errorf("amount must be positive")
+	}
+	if lenderID == borrowerID {
+		return "", fmt.Errorf("the lender cannot lend to themselves")
+	}
+	lender, err := s.ReadUser(ctx, lenderID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, lender)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.ReadUser(ctx, borrowerID)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+
+	loan := Loan{
+		ID:         ctx.GetStub().GetTxID(),
+		AssetID:    assetID,
+		LenderID:   lenderID,
+		BorrowerID: borrowerID,
+		Principal:  amount,
+		Balance:    amount,
+		Status:     LoanOffered,
+	}
+	err = putLoan(ctx, &loan)
+	if err != nil {
+		return "", err
+	}
+
+	return loan.ID, nil
+}
+
+// AcceptLoan lets the borrower accept an offered loan to pay for an open offer of the asset. The
+// lender pays the loan amount towards the price, the borrower pays the rest, and a lien of the
+// lender is recorded on the asset.
+func (s *SmartContract) AcceptLoan(ctx contractapi.TransactionContextInterface, loanID string, offerID string) error {
+	loan, err := s.ReadLoan(ctx, loanID)
+	if err != nil {
+		return err
+	}
+	if loan.Status != LoanOffered {
+		return fmt.Errorf("the loan %s is %s", loanID, loan.Status)
+	}
+	offer, err := s.ReadOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+	if offer.Status != OfferOpen {
+		return fmt.Errorf("the offer %s is %s", offerID, offer.Status)
+	}
+	if offer.AssetID != loan.AssetID || offer.BuyerID != loan.BorrowerID {
+		return fmt.Errorf("the offer %s is not for the asset and borrower of loan %s", offerID, loanID)
+	}
+	if loan.Principal > offer.Price {
+		return fmt.Errorf("the loan exceeds the price of %d", offer.Price)
+	}
+	borrower, err := s.ReadUser(ctx, loan.BorrowerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, borrower)
+	if err != nil {
+		return err
+	}
+	lender, err := s.ReadUser(ctx, loan.LenderID)
+	if err != nil {
+		return err
+	}
+	if lender.Money < loan.Principal {
+		return fmt.Errorf("user %s doesn't have enough money on his account", lender.ID)
+	}
+	err = checkUserActive(lender)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, offer.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, offer.AssetID)
+	if err != nil {
+		return err
+	}
+	dealer, err := s.readDealer(ctx, offer.DealerID, asset, borrower.ID, lender.ID)
+	if err != nil {
+		return err
+	}
+
+	lender.Money = lender.Money - loan.Principal
+	borrower.Money = borrower.Money + loan.Principal
+	err = putUser(ctx, lender)
+	if err != nil {
+		return err
+	}
+	err = settleSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: borrower, dealer: dealer, price: offer.Price, upfront: offer.Price})
+	if err != nil {
+		return err
+	}
+	asset.LienID = loan.ID
+	asset.LienholderID = lender.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	offer.Status = OfferAccepted
+	err = putOffer(ctx, offer)
+	if err != nil {
+		return err
+	}
+	loan.Status = LoanActive
+	return putLoan(ctx, loan)
+}
+
+// RepayLoan lets the borrower repay amount of an active loan to the lender. The lien on the asset
+// is released once the balance reaches zero.
+func (s *SmartContract) RepayLoan(ctx contractapi.TransactionContextInterface, loanID string, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	loan, err := s.ReadLoan(ctx, loanID)
+	if err != nil {
+		return err
+	}
+	if loan.Status != LoanActive {
+		return fmt.Errorf("the loan %s is %s", loanID, loan.Status)
+	}
+	if amount > loan.Balance {
+		return fmt.Errorf("amount exceeds the remaining balance of %d", loan.Balance)
+	}
+	borrower, err := s.ReadUser(ctx, loan.BorrowerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, borrower)
+	if err != nil {
+		return err
+	}
+	lender, err := s.ReadUser(ctx, loan.LenderID)
+	if err != nil {
+		return err
+	}
+	if borrower.Money < amount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", borrower.ID)
+	}
+	err = recordSpending(ctx, borrower, amount)
+	if err != nil {
+		return err
+	}
+
+	borrower.Money = borrower.Money - amount
+	lender.Money = lender.Money + amount
+	err = putUser(ctx, borrower)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, lender)
+	if err != nil {
+		return err
+	}
+
+	loan.Balance = loan.Balance - amount
+	if loan.Balance == 0 {
+		loan.Status = LoanRepaid
+		asset, err := s.ReadAsset(ctx, loan.AssetID)
+		if err != nil {
+			return err
+		}
+		if asset.LienID == loan.ID {
+			asset.LienID, asset.LienholderID = "", ""
+			err = putAsset(ctx, asset)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return putLoan(ctx, loan)
+}
+
+// ApproveLienTransfer lets the lienholder of the asset consent to its transfer to buyerID. The lien
+// stays on the asset after the transfer and the approval is used up by it. An empty buyerID
+// withdraws the approval.
+func (s *SmartContract) ApproveLienTransfer(ctx contractapi.TransactionContextInterface, assetID string, buyerID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.LienID == "" {
+		return fmt.Errorf("the asset %s has no lien", assetID)
+	}
+	lienholder, err := s.ReadUser(ctx, asset.LienholderID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, lienholder)
+	if err != nil {
+		return err
+	}
+
+	asset.LienTransferApproval = buyerID
+	return putAsset(ctx, asset)
+}
+
+// ReadLoan returns the loan stored in the world state with given id.
+func (s *SmartContract) ReadLoan(ctx contractapi.TransactionContextInterface, loanID string) (*Loan, error) {
+	loanKey, err := ctx.GetStub().CreateCompositeKey(loanObjectType, []string{loanID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	loanJSON, err := ctx.GetStub().GetState(loanKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if loanJSON == nil {
+		return nil, fmt.Errorf("the loan %s does not exist", loanID)
+	}
+
+	var loan Loan
+	err = json.Unmarshal(loanJSON, &loan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &loan, nil
+}
+
+// putLoan writes the given loan to the world state
+func putLoan(ctx contractapi.TransactionContextInterface, loan *Loan) error {
+	loanKey, err := ctx.GetStub().CreateCompositeKey(loanObjectType, []string{loan.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	loanJSON, err := json.Marshal(loan)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(loanKey, loanJSON)
+}
@@ -0,0 +1,99 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoanWithLien(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "borrower", Money: 2000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "lender", Money: 10000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "\x00offer\x00offer1\x00", &chaincode.Offer{ID: "offer1", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 8000, Status: chaincode.OfferOpen})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("lender", nil)
+	chaincodeStub.GetTxIDReturns("loan1")
+	loanID, err := assetTransfer.OfferLoan(transactionContext, "user3", "user2", "asset1", 6000)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("borrower", nil)
+	err = assetTransfer.AcceptLoan(transactionContext, loanID, "offer1")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Equal(t, "loan1", asset.LienID)
+	require.Equal(t, "user3", asset.LienholderID)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(0), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(8000), user.Money)
+
+	state.put(t, "user4", &chaincode.User{ID: "user4"})
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user4")
+	require.EqualError(t, err, "the asset asset1 has a lien held by user user3 who must approve the transfer")
+
+	state.get(t, "user2", user)
+	user.Money = 6000
+	state.put(t, "user2", user)
+	err = assetTransfer.RepayLoan(transactionContext, loanID, 7000)
+	require.EqualError(t, err, "amount exceeds the remaining balance of 6000")
+	err = assetTransfer.RepayLoan(transactionContext, loanID, 2000)
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "loan1", asset.LienID)
+
+	err = assetTransfer.RepayLoan(transactionContext, loanID, 4000)
+	require.NoError(t, err)
+	loan, err := assetTransfer.ReadLoan(transactionContext, loanID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.LoanRepaid, loan.Status)
+	state.get(t, "asset1", asset)
+	require.Empty(t, asset.LienID)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(10000), user.Money)
+
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user4")
+	require.NoError(t, err)
+}
+
+func TestLienholderApprovesTransfer(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "lender"})
+	state.put(t, "user4", &chaincode.User{ID: "user4"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", LienID: "loan1", LienholderID: "user3"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.ApproveLienTransfer(transactionContext, "asset1", "user2")
+	require.Error(t, err)
+
+	clientIdentity.GetIDReturns("lender", nil)
+	err = assetTransfer.ApproveLienTransfer(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user4")
+	require.EqualError(t, err, "the asset asset1 has a lien held by user user3 who must approve the transfer")
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Equal(t, "loan1", asset.LienID)
+	require.Empty(t, asset.LienTransferApproval)
+}
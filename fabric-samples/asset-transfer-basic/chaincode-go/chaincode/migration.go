@@ -0,0 +1,140 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// legacyDamage is a damage as stored before money amounts were kept in cents
+type legacyDamage struct {
+	Description string  `json:"description"`
+	Cost        float64 `json:"cost"`
+}
+
+// legacyUser is a user as stored before money amounts were kept in cents
+type legacyUser struct {
+	ID       string  `json:"ID"`
+	Name     string  `json:"name"`
+	Lastname string  `json:"lastname"`
+	Email    string  `json:"email"`
+	Money    float64 `json:"money"`
+}
+
+// legacyAsset is an asset as stored before money amounts were kept in cents
+type legacyAsset struct {
+	ID             string         `json:"ID"`
+	Brand          string         `json:"brand"`
+	Model          string         `json:"model"`
+	Year           int            `json:"year"`
+	Color          string         `json:"color"`
+	OwnerID        string         `json:"owner"`
+	Damages        []legacyDamage `json:"damages"`
+	AppraisedValue float64        `json:"appraisedValue"`
+}
+
+const moneyUnitConfig = "moneyUnit"
+
+// MigrateMoneyToCents rewrites users and assets stored with decimal money amounts
+// so that every amount is kept in integer cents. Running it again is a no-op. Only admins may migrate.
+func (s *SmartContract) MigrateMoneyToCents(ctx contractapi.TransactionContextInterface) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	markerKey, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{moneyUnitConfig})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	marker, err := ctx.GetStub().GetState(markerKey)
+	if err != nil {
+		return fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if marker != nil {
+		return nil
+	}
+
+	userIterator, err := ctx.GetStub().GetStateByRange("user", "")
+	if err != nil {
+		return err
+	}
+	defer userIterator.Close()
+
+	for userIterator.HasNext() {
+		queryResponse, err := userIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		var old legacyUser
+		err = json.Unmarshal(queryResponse.Value, &old)
+		if err != nil {
+			return err
+		}
+		user := User{ID: old.ID, Name: old.Name, Lastname: old.Lastname, Email: old.Email, Money: toCents(old.Money)}
+		err = putUser(ctx, &user)
+		if err != nil {
+			return fmt.Errorf("failed to put user to world state. %v", err)
+		}
+	}
+
+	assetIterator, err := ctx.GetStub().GetStateByRange("asset", "user")
+	if err != nil {
+		return err
+	}
+	defer assetIterator.Close()
+
+	for assetIterator.HasNext() {
+		queryResponse, err := assetIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		var old legacyAsset
+		err = json.Unmarshal(queryResponse.Value, &old)
+		if err != nil {
+			return err
+		}
+		asset := Asset{
+			ID:             old.ID,
+			Brand:          old.Brand,
+			Model:          old.Model,
+			Year:           old.Year,
+			Color:          old.Color,
+			OwnerID:        old.OwnerID,
+			Damages:        []Damage{},
+			AppraisedValue: toCents(old.AppraisedValue),
+		}
+		for _, legacy := range old.Damages {
+			damage := Damage{Description: legacy.Description, Cost: toCents(legacy.Cost)}
+			err = stampUpdated(ctx, &damage.Audit)
+			if err != nil {
+				return err
+			}
+			asset.Damages = append(asset.Damages, damage)
+		}
+		err = putAsset(ctx, &asset)
+		if err != nil {
+			return fmt.Errorf("failed to put asset to world state. %v", err)
+		}
+	}
+
+	return markMoneyInCents(ctx)
+}
+
+// markMoneyInCents records that money amounts in world state are kept in cents
+func markMoneyInCents(ctx contractapi.TransactionContextInterface) error {
+	markerKey, err := ctx.GetStub().CreateCompositeKey(configObjectType, []string{moneyUnitConfig})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().PutState(markerKey, []byte("cents"))
+}
+
+// toCents converts a decimal money amount to integer cents
+func toCents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
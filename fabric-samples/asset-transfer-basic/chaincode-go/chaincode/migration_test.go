@@ -0,0 +1,50 @@
+package chaincode_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateMoneyToCents(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+
+	userIterator := &mocks.StateQueryIterator{}
+	userIterator.HasNextReturnsOnCall(0, true)
+	userIterator.HasNextReturnsOnCall(1, false)
+	userIterator.NextReturns(&queryresult.KV{Key: "user1", Value: []byte(`{"ID":"user1","name":"Marko","money":10000.5}`)}, nil)
+	assetIterator := &mocks.StateQueryIterator{}
+	assetIterator.HasNextReturnsOnCall(0, true)
+	assetIterator.HasNextReturnsOnCall(1, false)
+	assetIterator.NextReturns(&queryresult.KV{Key: "asset1", Value: []byte(`{"ID":"asset1","owner":"user1","damages":[{"description":"tyre","cost":34.99}],"AppraisedValue":7000}`)}, nil)
+	chaincodeStub.GetStateByRangeReturnsOnCall(0, userIterator, nil)
+	chaincodeStub.GetStateByRangeReturnsOnCall(1, assetIterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute admin not found"))
+	err := assetTransfer.MigrateMoneyToCents(transactionContext)
+	require.EqualError(t, err, "submitting client does not have attribute admin=true")
+
+	clientIdentity.AssertAttributeValueReturns(nil)
+	err = assetTransfer.MigrateMoneyToCents(transactionContext)
+	require.NoError(t, err)
+
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, &chaincode.User{ID: "user1", Name: "Marko", Money: 1000050, Audit: chaincode.Audit{UpdatedAt: time.Unix(1600000000, 0).UTC()}, Version: 1}, user)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	migrated := chaincode.Audit{UpdatedAt: time.Unix(1600000000, 0).UTC()}
+	require.Equal(t, &chaincode.Asset{DocType: "asset", ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{{Description: "tyre", Cost: 3499, Audit: migrated}}, AppraisedValue: 700000, Audit: migrated, Version: 1}, asset)
+
+	err = assetTransfer.MigrateMoneyToCents(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, 2, chaincodeStub.GetStateByRangeCallCount())
+}
@@ -0,0 +1,135 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const mileageObjectType = "mileage"
+
+// RecordMileage lets the owner record the odometer reading of the asset in kilometers. A reading
+// lower than the current mileage is not recorded; instead the asset is flagged as tampered and a
+// MileageAnomaly event is emitted. The transaction still succeeds so the flag is kept.
+func (s *SmartContract) RecordMileage(ctx contractapi.TransactionContextInterface, assetID string, km int64) error {
+	_, err := s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	_, err = recordMileage(ctx, asset, km)
+	if err != nil {
+		return err
+	}
+
+	return putAsset(ctx, asset)
+}
+
+// ClearMileageTamper lets a regulator clear the tamper flag of the asset once the rollback has been
+// investigated
+func (s *SmartContract) ClearMileageTamper(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string) error {
+	_, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if !asset.MileageTampered {
+		return fmt.Errorf("the asset %s is not flagged for mileage tampering", assetID)
+	}
+
+	asset.MileageTampered = false
+	return putAsset(ctx, asset)
+}
+
+// recordMileage adds an odometer reading to the history of the asset and updates its mileage. A
+// reading lower than the current one flags the asset as tampered and emits a MileageAnomaly event
+// instead; it returns whether the reading was recorded. The caller stores the asset.
+func recordMileage(ctx contractapi.TransactionContextInterface, asset *Asset, km int64) (bool, error) {
+	if km < asset.Mileage {
+		return false, flagMileageAnomaly(ctx, asset, km)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	reading := MileageReading{
+		AssetID:    asset.ID,
+		TxID:       ctx.GetStub().GetTxID(),
+		Mileage:    km,
+		RecordedAt: now,
+	}
+	readingKey, err := ctx.GetStub().CreateCompositeKey(mileageObjectType, []string{asset.ID, reading.TxID})
+	if err != nil {
+		return false, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	readingJSON, err := json.Marshal(reading)
+	if err != nil {
+		return false, err
+	}
+	err = ctx.GetStub().PutState(readingKey, readingJSON)
+	if err != nil {
+		return false, err
+	}
+
+	asset.Mileage = km
+	return true, nil
+}
+
+// flagMileageAnomaly marks the asset as tampered and emits a MileageAnomaly event for the rejected reading
+func flagMileageAnomaly(ctx contractapi.TransactionContextInterface, asset *Asset, km int64) error {
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	eventJSON, err := json.Marshal(MileageAnomaly{
+		AssetID:          asset.ID,
+		VIN:              asset.VIN,
+		RecordedMileage:  asset.Mileage,
+		SubmittedMileage: km,
+		SubmittedBy:      clientID,
+	})
+	if err != nil {
+		return err
+	}
+
+	asset.MileageTampered = true
+	return ctx.GetStub().SetEvent("MileageAnomaly", eventJSON)
+}
+
+// GetMileageHistory returns all recorded odometer readings of the asset, oldest first
+func (s *SmartContract) GetMileageHistory(ctx contractapi.TransactionContextInterface, assetID string) ([]*MileageReading, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(mileageObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var readings []*MileageReading
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var reading MileageReading
+		err = json.Unmarshal(queryResponse.Value, &reading)
+		if err != nil {
+			return nil, err
+		}
+		readings = append(readings, &reading)
+	}
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].RecordedAt.Before(readings[j].RecordedAt)
+	})
+
+	return readings, nil
+}
@@ -0,0 +1,71 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordMileage(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	chaincodeStub.GetTxIDReturns("tx2")
+	err := assetTransfer.RecordMileage(transactionContext, "asset1", 12000)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("tx1")
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600086400}, nil)
+	err = assetTransfer.RecordMileage(transactionContext, "asset1", 11000)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, int64(12000), asset.Mileage)
+	require.True(t, asset.MileageTampered)
+	name, payload := chaincodeStub.SetEventArgsForCall(0)
+	require.Equal(t, "MileageAnomaly", name)
+	require.JSONEq(t, `{"assetID":"asset1","vin":"","recordedMileage":12000,"submittedMileage":11000,"submittedBy":""}`, string(payload))
+	err = assetTransfer.RecordMileage(transactionContext, "asset1", 12500)
+	require.NoError(t, err)
+
+	readings, err := assetTransfer.GetMileageHistory(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.MileageReading{
+		{AssetID: "asset1", TxID: "tx2", Mileage: 12000, RecordedAt: time.Unix(1600000000, 0).UTC()},
+		{AssetID: "asset1", TxID: "tx1", Mileage: 12500, RecordedAt: time.Unix(1600086400, 0).UTC()},
+	}, readings)
+
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	transfers, err := assetTransfer.GetTransfers(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, int64(12500), transfers[0].Mileage)
+}
+
+func TestClearMileageTamper(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleRegulator}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Mileage: 12000, MileageTampered: true})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.ClearMileageTamper(transactionContext, "asset1", "user1")
+	require.EqualError(t, err, "the user user1 does not have role regulator")
+	err = assetTransfer.ClearMileageTamper(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.False(t, asset.MileageTampered)
+
+	err = assetTransfer.ClearMileageTamper(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "the asset asset1 is not flagged for mileage tampering")
+}
@@ -0,0 +1,54 @@
+package chaincode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// MintVehicle lets a manufacturer issue a new car with its factory data. productionDate is given as
+// YYYY-MM-DD and may not lie after the transaction. The car is owned by the manufacturer's account
+// until sold, typically to a dealer.
+func (s *SmartContract) MintVehicle(ctx contractapi.TransactionContextInterface, id string, manufacturerID string, vin string, brand string, model string, color string, productionDate string, specs FactorySpecs) error {
+	manufacturer, err := s.ReadUser(ctx, manufacturerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, manufacturer)
+	if err != nil {
+		return err
+	}
+	err = requireRole(manufacturer, RoleManufacturer)
+	if err != nil {
+		return err
+	}
+	produced, err := time.Parse("2006-01-02", productionDate)
+	if err != nil {
+		return fmt.Errorf("invalid production date %s, expected YYYY-MM-DD", productionDate)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if produced.After(now) {
+		return fmt.Errorf("production date %s is in the future", productionDate)
+	}
+
+	asset := Asset{
+		ID:      id,
+		VIN:     vin,
+		Brand:   brand,
+		Model:   model,
+		Year:    produced.Year(),
+		Color:   color,
+		OwnerID: manufacturerID,
+		Damages: []Damage{},
+		Factory: &FactoryData{
+			ManufacturerID: manufacturerID,
+			ProductionDate: produced,
+			Specs:          specs,
+		},
+	}
+	return s.issueAsset(ctx, &asset)
+}
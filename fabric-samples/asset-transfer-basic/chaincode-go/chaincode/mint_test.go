@@ -0,0 +1,50 @@
+package chaincode_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMintVehicle(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleManufacturer}})
+	assetTransfer := chaincode.SmartContract{}
+	specs := chaincode.FactorySpecs{Engine: "1.4 T-Jet", PowerKW: 88, Transmission: "manual", FuelType: "petrol", Seats: 5}
+
+	err := assetTransfer.MintVehicle(transactionContext, "asset1", "user1", "ZFA19900100123456", "fiat", "500L", "black", "2020-06-01", specs)
+	require.EqualError(t, err, "the user user1 does not have role manufacturer")
+	err = assetTransfer.MintVehicle(transactionContext, "asset1", "user2", "ZFA19900100123456", "fiat", "500L", "black", "2021-06-01", specs)
+	require.EqualError(t, err, "production date 2021-06-01 is in the future")
+	err = assetTransfer.MintVehicle(transactionContext, "asset1", "user2", "ZFA19900100123456", "fiat", "500L", "black", "2020-06-01", specs)
+	require.NoError(t, err)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Equal(t, 2020, asset.Year)
+	require.Equal(t, &chaincode.FactoryData{ManufacturerID: "user2", ProductionDate: time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), Specs: specs}, asset.Factory)
+	err = assetTransfer.MintVehicle(transactionContext, "asset2", "user2", "ZFA19900100123456", "fiat", "500L", "black", "2020-06-01", specs)
+	require.EqualError(t, err, "VIN ZFA19900100123456 is already registered to asset asset1")
+}
+
+func TestRegisterUsedCar(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute role not found"))
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.EqualError(t, err, "only dealers and the owner can register asset asset1")
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.NoError(t, err)
+}
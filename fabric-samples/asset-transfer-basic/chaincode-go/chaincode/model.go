@@ -0,0 +1,171 @@
+package chaincode
+
+import "github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/model"
+
+// The records the chaincode stores and returns are declared in the model package, so client
+// applications can share them. They are aliased here for the chaincode.
+type (
+	AccidentReport       = model.AccidentReport
+	DamageRef            = model.DamageRef
+	PriceAgreement       = model.PriceAgreement
+	Appraisal            = model.Appraisal
+	AssetDetails         = model.AssetDetails
+	PublicAsset          = model.PublicAsset
+	Auction              = model.Auction
+	Bid                  = model.Bid
+	SealedBid            = model.SealedBid
+	RevealedBid          = model.RevealedBid
+	Audit                = model.Audit
+	AuditEntry           = model.AuditEntry
+	BalanceDelta         = model.BalanceDelta
+	AssetInput           = model.AssetInput
+	BatteryReport        = model.BatteryReport
+	Claim                = model.Claim
+	OwnershipShare       = model.OwnershipShare
+	AssetAtRiskEvent     = model.AssetAtRiskEvent
+	BidDeposit           = model.BidDeposit
+	NotarizedDocument    = model.NotarizedDocument
+	EmissionsTest        = model.EmissionsTest
+	Escrow               = model.Escrow
+	Export               = model.Export
+	FleetItem            = model.FleetItem
+	ClaimFlag            = model.ClaimFlag
+	UserFreezeEvent      = model.UserFreezeEvent
+	AssetFreezeEvent     = model.AssetFreezeEvent
+	BlockedAttempt       = model.BlockedAttempt
+	FundsEvent           = model.FundsEvent
+	Payment              = model.Payment
+	BalanceRecord        = model.BalanceRecord
+	InspectionRecord     = model.InspectionRecord
+	InsurancePolicy      = model.InsurancePolicy
+	Invoice              = model.Invoice
+	InvoiceItem          = model.InvoiceItem
+	Lease                = model.Lease
+	LeaseDelinquentEvent = model.LeaseDelinquentEvent
+	Listing              = model.Listing
+	Loan                 = model.Loan
+	MileageReading       = model.MileageReading
+	MileageAnomaly       = model.MileageAnomaly
+	FactorySpecs         = model.FactorySpecs
+	FactoryData          = model.FactoryData
+	Offer                = model.Offer
+	UserPII              = model.UserPII
+	PurchasePlan         = model.PurchasePlan
+	PlateRecord          = model.PlateRecord
+	PolicyTerms          = model.PolicyTerms
+	SalePriceRecord      = model.SalePriceRecord
+	OwnershipPeriod      = model.OwnershipPeriod
+	ProvenanceReport     = model.ProvenanceReport
+	PaginatedQueryResult = model.PaginatedQueryResult
+	Rating               = model.Rating
+	MechanicRating       = model.MechanicRating
+	Recall               = model.Recall
+	RecallFix            = model.RecallFix
+	Registration         = model.Registration
+	RepairJob            = model.RepairJob
+	RepairQuote          = model.RepairQuote
+	TransferRecord       = model.TransferRecord
+	Seizure              = model.Seizure
+	ServiceBookEntry     = model.ServiceBookEntry
+	ServiceRecord        = model.ServiceRecord
+	ServiceRecordHash    = model.ServiceRecordHash
+	ShareHolding         = model.ShareHolding
+	Damage               = model.Damage
+	LiabilityShare       = model.LiabilityShare
+	User                 = model.User
+	Asset                = model.Asset
+	StolenEvent          = model.StolenEvent
+	Swap                 = model.Swap
+	UserInput            = model.UserInput
+	OfferInput           = model.OfferInput
+	TreasuryReport       = model.TreasuryReport
+	Warranty             = model.Warranty
+)
+
+// Values of the status, role and other enumerated fields of the model
+const (
+	AuctionSealed         = model.AuctionSealed
+	AuctionEnglish        = model.AuctionEnglish
+	AuctionDutch          = model.AuctionDutch
+	AuctionOpen           = model.AuctionOpen
+	AuctionClosed         = model.AuctionClosed
+	AuctionEnded          = model.AuctionEnded
+	AuctionFailed         = model.AuctionFailed
+	FuelElectric          = model.FuelElectric
+	ClaimFiled            = model.ClaimFiled
+	ClaimApproved         = model.ClaimApproved
+	ClaimRejected         = model.ClaimRejected
+	ClaimPaid             = model.ClaimPaid
+	EscrowFunded          = model.EscrowFunded
+	EscrowReleased        = model.EscrowReleased
+	EscrowRefunded        = model.EscrowRefunded
+	ExportRequested       = model.ExportRequested
+	ExportApproved        = model.ExportApproved
+	ExportImported        = model.ExportImported
+	ExportRejected        = model.ExportRejected
+	FlagUser              = model.FlagUser
+	FlagAsset             = model.FlagAsset
+	FlagClaim             = model.FlagClaim
+	PolicyPending         = model.PolicyPending
+	PolicyActive          = model.PolicyActive
+	PolicyCancelled       = model.PolicyCancelled
+	LeaseOffered          = model.LeaseOffered
+	LeaseActive           = model.LeaseActive
+	LeaseDelinquent       = model.LeaseDelinquent
+	LeaseCompleted        = model.LeaseCompleted
+	LeaseTerminated       = model.LeaseTerminated
+	LeaseBoughtOut        = model.LeaseBoughtOut
+	ListingOpen           = model.ListingOpen
+	ListingSold           = model.ListingSold
+	ListingWithdrawn      = model.ListingWithdrawn
+	LoanOffered           = model.LoanOffered
+	LoanActive            = model.LoanActive
+	LoanRepaid            = model.LoanRepaid
+	OfferOpen             = model.OfferOpen
+	OfferAccepted         = model.OfferAccepted
+	OfferCancelled        = model.OfferCancelled
+	PlanOffered           = model.PlanOffered
+	PlanActive            = model.PlanActive
+	PlanPaid              = model.PlanPaid
+	PlanCancelled         = model.PlanCancelled
+	PlateAssigned         = model.PlateAssigned
+	PlateRemoved          = model.PlateRemoved
+	RegistrationPending   = model.RegistrationPending
+	RegistrationConfirmed = model.RegistrationConfirmed
+	RegistrationRejected  = model.RegistrationRejected
+	RegistrationExpired   = model.RegistrationExpired
+	RepairRequested       = model.RepairRequested
+	RepairAccepted        = model.RepairAccepted
+	RepairInProgress      = model.RepairInProgress
+	RepairCompleted       = model.RepairCompleted
+	RepairPaid            = model.RepairPaid
+	QuotePending          = model.QuotePending
+	QuoteAccepted         = model.QuoteAccepted
+	RoleOwner             = model.RoleOwner
+	RoleMechanic          = model.RoleMechanic
+	RoleDealer            = model.RoleDealer
+	RoleInsurer           = model.RoleInsurer
+	RoleAssessor          = model.RoleAssessor
+	RoleRegulator         = model.RoleRegulator
+	RoleInspector         = model.RoleInspector
+	RoleDMV               = model.RoleDMV
+	RolePolice            = model.RolePolice
+	RoleEmissionsStation  = model.RoleEmissionsStation
+	RoleManufacturer      = model.RoleManufacturer
+	SeizureActive         = model.SeizureActive
+	SeizureReleased       = model.SeizureReleased
+	DamageOpen            = model.DamageOpen
+	DamageRepaired        = model.DamageRepaired
+	UserActive            = model.UserActive
+	UserClosed            = model.UserClosed
+	UserFrozen            = model.UserFrozen
+	AssetTotaled          = model.AssetTotaled
+	AssetSalvaged         = model.AssetSalvaged
+	AssetFrozen           = model.AssetFrozen
+	SwapProposed          = model.SwapProposed
+	SwapCompleted         = model.SwapCompleted
+	SwapCancelled         = model.SwapCancelled
+	WarrantyClaimPending  = model.WarrantyClaimPending
+	WarrantyClaimApproved = model.WarrantyClaimApproved
+	WarrantyClaimRejected = model.WarrantyClaimRejected
+)
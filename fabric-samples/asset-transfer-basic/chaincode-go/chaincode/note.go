@@ -0,0 +1,110 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	noteObjectType   = "note"
+	noteTransientKey = "asset_note"
+)
+
+// SetAssetNote lets the owner keep a private memo on the asset, such as "needs clutch soon". The
+// note is passed in the asset_note transient field and stored in the implicit private data
+// collection of the owner's organization. An empty note removes it. Notes are cleared when the
+// asset is sold.
+func (s *SmartContract) SetAssetNote(ctx contractapi.TransactionContextInterface, assetID string) error {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf("error getting transient: %v", err)
+	}
+	note, ok := transientMap[noteTransientKey]
+	if !ok {
+		return fmt.Errorf("the note must be passed in the %s transient field", noteTransientKey)
+	}
+
+	err = deleteAssetNote(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if len(note) == 0 {
+		return putAsset(ctx, asset)
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+	noteKey, err := ctx.GetStub().CreateCompositeKey(noteObjectType, []string{assetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().PutPrivateData(collection, noteKey, note)
+	if err != nil {
+		return fmt.Errorf("failed to put note to private data collection %s: %v", collection, err)
+	}
+
+	asset.NoteCollection = collection
+	return putAsset(ctx, asset)
+}
+
+// GetAssetNote returns the private note of the owner on the asset, empty when there is none.
+// Only the owner may read it.
+func (s *SmartContract) GetAssetNote(ctx contractapi.TransactionContextInterface, assetID string) (string, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+	if asset.NoteCollection == "" {
+		return "", nil
+	}
+	noteKey, err := ctx.GetStub().CreateCompositeKey(noteObjectType, []string{assetID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+	note, err := ctx.GetStub().GetPrivateData(asset.NoteCollection, noteKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from private data collection %s: %v", asset.NoteCollection, err)
+	}
+
+	return string(note), nil
+}
+
+// deleteAssetNote removes the owner's note on the asset from its collection. The caller stores the asset.
+func deleteAssetNote(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	if asset.NoteCollection == "" {
+		return nil
+	}
+	noteKey, err := ctx.GetStub().CreateCompositeKey(noteObjectType, []string{asset.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().DelPrivateData(asset.NoteCollection, noteKey)
+	if err != nil {
+		return fmt.Errorf("failed to delete from private data collection %s: %v", asset.NoteCollection, err)
+	}
+
+	asset.NoteCollection = ""
+	return nil
+}
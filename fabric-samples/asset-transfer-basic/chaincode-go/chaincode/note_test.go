@@ -0,0 +1,46 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetNote(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer", MSPID: "Org1MSP"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	note, err := assetTransfer.GetAssetNote(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Empty(t, note)
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"asset_note": []byte("needs clutch soon")}, nil)
+	err = assetTransfer.SetAssetNote(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []byte("needs clutch soon"), state["_implicit_org_Org1MSP/\x00note\x00asset1\x00"])
+	note, err = assetTransfer.GetAssetNote(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "needs clutch soon", note)
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	_, err = assetTransfer.GetAssetNote(transactionContext, "asset1")
+	require.Error(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	require.NotContains(t, state, "_implicit_org_Org1MSP/\x00note\x00asset1\x00")
+	clientIdentity.GetIDReturns("buyer", nil)
+	note, err = assetTransfer.GetAssetNote(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Empty(t, note)
+}
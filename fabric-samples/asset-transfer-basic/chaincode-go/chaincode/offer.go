@@ -0,0 +1,193 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const offerObjectType = "offer"
+
+// OfferAsset lets the owner of the asset offer it to the buyer at given price. When the sale is
+// brokered by a dealer, dealerID names the dealer who earns a commission; leave it empty otherwise.
+// It returns the ID of the new offer which the buyer passes to AcceptOffer.
+func (s *SmartContract) OfferAsset(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, price int64, dealerID string) (string, error) {
+	if price < 0 {
+		return "", fmt.Errorf("price must not be negative")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.OwnerID == buyerID {
+		return "", fmt.Errorf("New owner is same as current")
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return "", err
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return "", err
+	}
+	err = checkUserActive(buyer)
+	if err != nil {
+		return "", err
+	}
+	_, err = s.readDealer(ctx, dealerID, asset, buyerID)
+	if err != nil {
+		return "", err
+	}
+
+	offer := Offer{
+		ID:       ctx.GetStub().GetTxID(),
+		AssetID:  assetID,
+		SellerID: seller.ID,
+		BuyerID:  buyerID,
+		Price:    price,
+		DealerID: dealerID,
+		Status:   OfferOpen,
+	}
+	err = putOffer(ctx, &offer)
+	if err != nil {
+		return "", err
+	}
+
+	return offer.ID, nil
+}
+
+// AcceptOffer lets the buyer accept an open offer, paying the price and taking ownership of the asset.
+// While transfers are regulated the price is held until the registry confirms the sale.
+func (s *SmartContract) AcceptOffer(ctx contractapi.TransactionContextInterface, offerID string) error {
+	offer, err := s.ReadOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+	if offer.Status != OfferOpen {
+		return fmt.Errorf("the offer %s is %s", offerID, offer.Status)
+	}
+	buyer, err := s.ReadUser(ctx, offer.BuyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, offer.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, offer.AssetID)
+	if err != nil {
+		return err
+	}
+
+	dealer, err := s.readDealer(ctx, offer.DealerID, asset, buyer.ID)
+	if err != nil {
+		return err
+	}
+
+	_, err = settleOrRegister(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, dealer: dealer, price: offer.Price, upfront: offer.Price})
+	if err != nil {
+		return err
+	}
+
+	offer.Status = OfferAccepted
+	return putOffer(ctx, offer)
+}
+
+// CancelOffer lets either the seller or the buyer withdraw an open offer.
+func (s *SmartContract) CancelOffer(ctx contractapi.TransactionContextInterface, offerID string) error {
+	offer, err := s.ReadOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+	if offer.Status != OfferOpen {
+		return fmt.Errorf("the offer %s is %s", offerID, offer.Status)
+	}
+	seller, err := s.ReadUser(ctx, offer.SellerID)
+	if err != nil {
+		return err
+	}
+	buyer, err := s.ReadUser(ctx, offer.BuyerID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, seller) != nil && verifyUserIdentity(ctx, buyer) != nil {
+		return fmt.Errorf("only the seller or the buyer can cancel offer %s", offerID)
+	}
+
+	offer.Status = OfferCancelled
+	return putOffer(ctx, offer)
+}
+
+// ReadOffer returns the offer stored in the world state with given id.
+func (s *SmartContract) ReadOffer(ctx contractapi.TransactionContextInterface, offerID string) (*Offer, error) {
+	offerKey, err := ctx.GetStub().CreateCompositeKey(offerObjectType, []string{offerID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	offerJSON, err := ctx.GetStub().GetState(offerKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if offerJSON == nil {
+		return nil, fmt.Errorf("the offer %s does not exist", offerID)
+	}
+
+	var offer Offer
+	err = json.Unmarshal(offerJSON, &offer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &offer, nil
+}
+
+// GetOpenOffers returns all open offers where the user with given ID is the seller or the buyer
+func (s *SmartContract) GetOpenOffers(ctx contractapi.TransactionContextInterface, userID string) ([]*Offer, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(offerObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var offers []*Offer
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var offer Offer
+		err = json.Unmarshal(queryResponse.Value, &offer)
+		if err != nil {
+			return nil, err
+		}
+		if offer.Status == OfferOpen && (offer.SellerID == userID || offer.BuyerID == userID) {
+			offers = append(offers, &offer)
+		}
+	}
+
+	return offers, nil
+}
+
+// putOffer writes the given offer to the world state
+func putOffer(ctx contractapi.TransactionContextInterface, offer *Offer) error {
+	offerKey, err := ctx.GetStub().CreateCompositeKey(offerObjectType, []string{offer.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	offerJSON, err := json.Marshal(offer)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(offerKey, offerJSON)
+}
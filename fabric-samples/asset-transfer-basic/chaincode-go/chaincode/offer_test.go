@@ -0,0 +1,76 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOfferAndAccept(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 0, Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "buyer"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("buyer", nil)
+	_, err := assetTransfer.OfferAsset(transactionContext, "asset1", "user2", 4000, "")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("offer1")
+	offerID, err := assetTransfer.OfferAsset(transactionContext, "asset1", "user2", 4000, "")
+	require.NoError(t, err)
+	require.Equal(t, "offer1", offerID)
+
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user2")
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(1000), buyer.Money)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(4000), seller.Money)
+
+	offer, err := assetTransfer.ReadOffer(transactionContext, offerID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.OfferAccepted, offer.Status)
+
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.EqualError(t, err, "the offer offer1 is accepted")
+}
+
+func TestCancelOffer(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer"})
+	state.put(t, "\x00offer\x00offer1\x00", &chaincode.Offer{ID: "offer1", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Status: chaincode.OfferOpen})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("someone", nil)
+	err := assetTransfer.CancelOffer(transactionContext, "offer1")
+	require.EqualError(t, err, "only the seller or the buyer can cancel offer offer1")
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.CancelOffer(transactionContext, "offer1")
+	require.NoError(t, err)
+	offer, err := assetTransfer.ReadOffer(transactionContext, "offer1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.OfferCancelled, offer.Status)
+
+	err = assetTransfer.CancelOffer(transactionContext, "offer1")
+	require.EqualError(t, err, "the offer offer1 is cancelled")
+}
@@ -0,0 +1,93 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// Transactions may be restricted to clients of given organizations. The MSP IDs allowed to submit
+// a transaction are stored under allowedMSPs composite keys and changed only by admins.
+const allowedMSPsObjectType = "allowedMSPs"
+
+// SetAllowedMSPs restricts the transaction with given function name to clients of the
+// organizations with given MSP IDs. An empty list lifts the restriction. Only admins may
+// change the restrictions.
+func (s *SmartContract) SetAllowedMSPs(ctx contractapi.TransactionContextInterface, function string, mspIDs []string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if function == "" {
+		return fmt.Errorf("function name must not be empty")
+	}
+	allowedKey, err := ctx.GetStub().CreateCompositeKey(allowedMSPsObjectType, []string{function})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	if len(mspIDs) == 0 {
+		return ctx.GetStub().DelState(allowedKey)
+	}
+	mspIDsJSON, err := json.Marshal(mspIDs)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(allowedKey, mspIDsJSON)
+}
+
+// GetAllowedMSPs returns the MSP IDs of the organizations allowed to submit the transaction with
+// given function name, or an empty list when every organization may submit it
+func (s *SmartContract) GetAllowedMSPs(ctx contractapi.TransactionContextInterface, function string) ([]string, error) {
+	return readAllowedMSPs(ctx, function)
+}
+
+// readAllowedMSPs returns the MSP IDs allowed to submit the transaction with given function name
+func readAllowedMSPs(ctx contractapi.TransactionContextInterface, function string) ([]string, error) {
+	allowedKey, err := ctx.GetStub().CreateCompositeKey(allowedMSPsObjectType, []string{function})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	mspIDsJSON, err := ctx.GetStub().GetState(allowedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if mspIDsJSON == nil {
+		return []string{}, nil
+	}
+
+	var mspIDs []string
+	err = json.Unmarshal(mspIDsJSON, &mspIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return mspIDs, nil
+}
+
+// CheckAllowedMSP returns an error when the invoked transaction is restricted to organizations
+// the submitting client does not belong to. It runs before every transaction of the contract.
+func CheckAllowedMSP(ctx contractapi.TransactionContextInterface) error {
+	function, _ := ctx.GetStub().GetFunctionAndParameters()
+	// the function may be prefixed with the contract name
+	function = function[strings.LastIndex(function, ":")+1:]
+
+	mspIDs, err := readAllowedMSPs(ctx, function)
+	if err != nil {
+		return err
+	}
+	if len(mspIDs) == 0 {
+		return nil
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	if !contains(mspIDs, mspID) {
+		return fmt.Errorf("clients of %s are not allowed to submit %s", mspID, function)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedMSPs(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	chaincodeStub.GetFunctionAndParametersReturns("SmartContract:CompleteRepair", []string{"job1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := chaincode.CheckAllowedMSP(transactionContext)
+	require.NoError(t, err)
+
+	err = assetTransfer.SetAllowedMSPs(transactionContext, "CompleteRepair", []string{"RepairShopMSP"})
+	require.NoError(t, err)
+	mspIDs, err := assetTransfer.GetAllowedMSPs(transactionContext, "CompleteRepair")
+	require.NoError(t, err)
+	require.Equal(t, []string{"RepairShopMSP"}, mspIDs)
+	err = chaincode.CheckAllowedMSP(transactionContext)
+	require.EqualError(t, err, "clients of Org1MSP are not allowed to submit CompleteRepair")
+
+	clientIdentity.GetMSPIDReturns("RepairShopMSP", nil)
+	err = chaincode.CheckAllowedMSP(transactionContext)
+	require.NoError(t, err)
+
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	err = assetTransfer.SetAllowedMSPs(transactionContext, "CompleteRepair", nil)
+	require.NoError(t, err)
+	err = chaincode.CheckAllowedMSP(transactionContext)
+	require.NoError(t, err)
+}
+
+func TestCheckAllowedMSPIsValidBeforeTransaction(t *testing.T) {
+	_, err := contractapi.NewChaincode(&chaincode.SmartContract{
+		Contract: contractapi.Contract{BeforeTransaction: chaincode.CheckAllowedMSP},
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,221 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	piiObjectType      = "pii"
+	piiTransientKey    = "user_pii"
+	emailHashIndexName = "emailHash~user"
+)
+
+// SetPrivateUserPII lets the user move their name, lastname and email into the private data
+// collection of their organization. The UserPII is passed as JSON in the user_pii transient field
+// and must match the current public details; those are then cleared and replaced by the hash of the
+// PII. The email stays unique through an index keyed by the hash of the address.
+func (s *SmartContract) SetPrivateUserPII(ctx contractapi.TransactionContextInterface, userID string) error {
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.PIIHash != "" {
+		return fmt.Errorf("the personal data of user %s is already private", userID)
+	}
+	err = verifyUserIdentity(ctx, user)
+	if err != nil {
+		return err
+	}
+	piiJSON, err := transientPII(ctx)
+	if err != nil {
+		return err
+	}
+	if piiJSON == nil {
+		return fmt.Errorf("the personal data must be passed in the %s transient field", piiTransientKey)
+	}
+	pii, err := parsePII(piiJSON)
+	if err != nil {
+		return err
+	}
+	if pii.UserID != userID || pii.Name != user.Name || pii.Lastname != user.Lastname || pii.Email != user.Email {
+		return fmt.Errorf("the personal data does not match user %s", userID)
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+	piiKey, err := ctx.GetStub().CreateCompositeKey(piiObjectType, []string{userID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	err = ctx.GetStub().PutPrivateData(collection, piiKey, piiJSON)
+	if err != nil {
+		return fmt.Errorf("failed to put personal data to private data collection %s: %v", collection, err)
+	}
+	err = deleteEmailIndex(ctx, user.Email)
+	if err != nil {
+		return err
+	}
+	user.EmailHash = hashEmail(user.Email)
+	err = putEmailHashIndex(ctx, user.EmailHash, userID)
+	if err != nil {
+		return err
+	}
+	user.Name, user.Lastname, user.Email = "", "", ""
+	user.PIICollection = collection
+	user.PIIHash = hashPII(piiJSON)
+	return putUser(ctx, user)
+}
+
+// GetUserPII returns the personal data of the user. Private data is read from the user
+// organization's collection, or from the user_pii transient field on peers outside it.
+func (s *SmartContract) GetUserPII(ctx contractapi.TransactionContextInterface, userID string) (*UserPII, error) {
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.PIIPurged {
+		return nil, fmt.Errorf("the personal data of user %s was purged", userID)
+	}
+	if user.PIIHash == "" {
+		return &UserPII{UserID: user.ID, Name: user.Name, Lastname: user.Lastname, Email: user.Email}, nil
+	}
+	piiJSON, err := transientPII(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if piiJSON == nil {
+		piiKey, err := ctx.GetStub().CreateCompositeKey(piiObjectType, []string{userID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create composite key: %v", err)
+		}
+		piiJSON, err = ctx.GetStub().GetPrivateData(user.PIICollection, piiKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from private data collection %s: %v", user.PIICollection, err)
+		}
+	}
+	if piiJSON == nil {
+		return nil, fmt.Errorf("the personal data of user %s must be passed in the %s transient field", userID, piiTransientKey)
+	}
+	if hashPII(piiJSON) != user.PIIHash {
+		return nil, fmt.Errorf("the personal data does not match the hash of user %s", userID)
+	}
+
+	return parsePII(piiJSON)
+}
+
+// PurgeUserPII erases the personal data of the user. The user or an admin may purge it; the
+// balance, roles and ownership links of the user are kept. Private data is deleted from the
+// collection; the fabric-chaincode-go version this chaincode builds with has no PurgePrivateData,
+// so the collection should set a blockToLive for the deleted values to leave the peers' private
+// history too. Details that were public before remain in the block history.
+func (s *SmartContract) PurgeUserPII(ctx contractapi.TransactionContextInterface, userID string) error {
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, user) != nil {
+		err = requireAdmin(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if user.PIIPurged {
+		return fmt.Errorf("the personal data of user %s was already purged", userID)
+	}
+
+	if user.PIIHash != "" {
+		piiKey, err := ctx.GetStub().CreateCompositeKey(piiObjectType, []string{userID})
+		if err != nil {
+			return fmt.Errorf("failed to create composite key: %v", err)
+		}
+		err = ctx.GetStub().DelPrivateData(user.PIICollection, piiKey)
+		if err != nil {
+			return fmt.Errorf("failed to delete from private data collection %s: %v", user.PIICollection, err)
+		}
+		err = deleteEmailHashIndex(ctx, user.EmailHash)
+		if err != nil {
+			return err
+		}
+	} else {
+		err = deleteEmailIndex(ctx, user.Email)
+		if err != nil {
+			return err
+		}
+	}
+
+	user.Name, user.Lastname, user.Email = "", "", ""
+	user.EmailHash, user.PIICollection, user.PIIHash = "", "", ""
+	user.PIIPurged = true
+	return putUser(ctx, user)
+}
+
+// emailHashIndexKey returns the key of the index entry of an email address kept private
+func emailHashIndexKey(ctx contractapi.TransactionContextInterface, emailHash string) (string, error) {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(emailHashIndexName, []string{emailHash})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return indexKey, nil
+}
+
+// putEmailHashIndex maps the hash of a private email address to the user ID
+func putEmailHashIndex(ctx contractapi.TransactionContextInterface, emailHash string, userID string) error {
+	indexKey, err := emailHashIndexKey(ctx, emailHash)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte(userID))
+}
+
+// deleteEmailHashIndex removes the index entry of a private email address
+func deleteEmailHashIndex(ctx contractapi.TransactionContextInterface, emailHash string) error {
+	indexKey, err := emailHashIndexKey(ctx, emailHash)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().DelState(indexKey)
+}
+
+// transientPII returns the personal data passed in the transient data, or nil when none was passed
+func transientPII(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+
+	return transientMap[piiTransientKey], nil
+}
+
+// parsePII decodes the personal data JSON
+func parsePII(piiJSON []byte) (*UserPII, error) {
+	var pii UserPII
+	err := json.Unmarshal(piiJSON, &pii)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal personal data: %v", err)
+	}
+
+	return &pii, nil
+}
+
+// hashPII returns the hex encoded SHA-256 of the personal data JSON
+func hashPII(piiJSON []byte) string {
+	hash := sha256.Sum256(piiJSON)
+	return hex.EncodeToString(hash[:])
+}
+
+// hashEmail returns the hex encoded SHA-256 of the lower-cased email address
+func hashEmail(email string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(email)))
+	return hex.EncodeToString(hash[:])
+}
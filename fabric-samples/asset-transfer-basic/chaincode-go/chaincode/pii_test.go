@@ -0,0 +1,55 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrivateUserPII(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("marko", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Name: "Marko", Lastname: "Markovic", Email: "marko.markovic@email.com", Money: 500, Identity: "marko", MSPID: "Org1MSP"})
+	state["\x00email~user\x00marko.markovic@email.com\x00"] = []byte("user1")
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTransientReturns(map[string][]byte{"user_pii": []byte(`{"userID":"user1","name":"Marko","lastname":"Petrovic","email":"marko.markovic@email.com","salt":"beef"}`)}, nil)
+	err := assetTransfer.SetPrivateUserPII(transactionContext, "user1")
+	require.EqualError(t, err, "the personal data does not match user user1")
+
+	pii := []byte(`{"userID":"user1","name":"Marko","lastname":"Markovic","email":"marko.markovic@email.com","salt":"beef"}`)
+	chaincodeStub.GetTransientReturns(map[string][]byte{"user_pii": pii}, nil)
+	err = assetTransfer.SetPrivateUserPII(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, pii, state["_implicit_org_Org1MSP/\x00pii\x00user1\x00"])
+	require.NotContains(t, state, "\x00email~user\x00marko.markovic@email.com\x00")
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Empty(t, user.Email)
+
+	chaincodeStub.GetTransientReturns(nil, nil)
+	details, err := assetTransfer.GetUserPII(transactionContext, "user1")
+	require.NoError(t, err)
+	require.Equal(t, "Markovic", details.Lastname)
+	found, err := assetTransfer.GetUserByEmail(transactionContext, "Marko.Markovic@email.com")
+	require.NoError(t, err)
+	require.Equal(t, "user1", found.ID)
+	err = assetTransfer.CreateUser(transactionContext, "user2", "Marko", "Markovic", "marko.markovic@email.com", 0)
+	require.EqualError(t, err, "email marko.markovic@email.com is already used by user user1")
+
+	err = assetTransfer.PurgeUserPII(transactionContext, "user1")
+	require.NoError(t, err)
+	require.NotContains(t, state, "_implicit_org_Org1MSP/\x00pii\x00user1\x00")
+	state.get(t, "user1", user)
+	require.True(t, user.PIIPurged)
+	require.Equal(t, int64(500), user.Money)
+	_, err = assetTransfer.GetUserPII(transactionContext, "user1")
+	require.EqualError(t, err, "the personal data of user user1 was purged")
+	_, err = assetTransfer.GetUserByEmail(transactionContext, "marko.markovic@email.com")
+	require.EqualError(t, err, "no user with email marko.markovic@email.com")
+}
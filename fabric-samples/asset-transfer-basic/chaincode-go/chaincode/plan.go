@@ -0,0 +1,196 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const planObjectType = "plan"
+
+// OfferPurchasePlan lets the owner of the asset offer it to the buyer for price paid in installments,
+// starting with downPayment. It returns the ID of the new plan.
+func (s *SmartContract) OfferPurchasePlan(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, price int64, downPayment int64) (string, error) {
+	if price <= 0 || downPayment < 0 || downPayment > price {
+		return "", fmt.Errorf("down payment must be between 0 and the price")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.OwnerID == buyerID {
+		return "", fmt.Errorf("New owner is same as current")
+	}
+	seller, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, seller)
+	if err != nil {
+		return "", err
+	}
+
+	plan := PurchasePlan{
+		ID:          ctx.GetStub().GetTxID(),
+		AssetID:     assetID,
+		SellerID:    seller.ID,
+		BuyerID:     buyerID,
+		Price:       price,
+		DownPayment: downPayment,
+		Remaining:   price - downPayment,
+		Status:      PlanOffered,
+	}
+	err = putPlan(ctx, &plan)
+	if err != nil {
+		return "", err
+	}
+
+	return plan.ID, nil
+}
+
+// AcceptPurchasePlan lets the buyer pay the down payment and take ownership of the encumbered asset.
+func (s *SmartContract) AcceptPurchasePlan(ctx contractapi.TransactionContextInterface, planID string) error {
+	plan, err := s.ReadPurchasePlan(ctx, planID)
+	if err != nil {
+		return err
+	}
+	if plan.Status != PlanOffered {
+		return fmt.Errorf("the purchase plan %s is %s", planID, plan.Status)
+	}
+	buyer, err := s.ReadUser(ctx, plan.BuyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, plan.SellerID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, plan.AssetID)
+	if err != nil {
+		return err
+	}
+
+	err = settleSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, price: plan.Price, upfront: plan.DownPayment})
+	if err != nil {
+		return err
+	}
+
+	plan.Status = PlanActive
+	if plan.Remaining == 0 {
+		plan.Status = PlanPaid
+	} else {
+		asset.Encumbered = true
+		err = putAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return putPlan(ctx, plan)
+}
+
+// PayInstallment pays amount towards the remaining balance of an active purchase plan.
+// The asset is released once the balance reaches zero.
+func (s *SmartContract) PayInstallment(ctx contractapi.TransactionContextInterface, planID string, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	plan, err := s.ReadPurchasePlan(ctx, planID)
+	if err != nil {
+		return err
+	}
+	if plan.Status != PlanActive {
+		return fmt.Errorf("the purchase plan %s is %s", planID, plan.Status)
+	}
+	if amount > plan.Remaining {
+		return fmt.Errorf("amount exceeds the remaining balance of %d", plan.Remaining)
+	}
+	buyer, err := s.ReadUser(ctx, plan.BuyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, plan.SellerID)
+	if err != nil {
+		return err
+	}
+	if buyer.Money < amount {
+		return fmt.Errorf("user %s doesn't have enough money on his account", buyer.ID)
+	}
+	err = recordSpending(ctx, buyer, amount)
+	if err != nil {
+		return err
+	}
+
+	buyer.Money = buyer.Money - amount
+	seller.Money = seller.Money + amount
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	err = putUser(ctx, seller)
+	if err != nil {
+		return err
+	}
+
+	plan.Remaining = plan.Remaining - amount
+	if plan.Remaining == 0 {
+		plan.Status = PlanPaid
+		asset, err := s.ReadAsset(ctx, plan.AssetID)
+		if err != nil {
+			return err
+		}
+		asset.Encumbered = false
+		err = putAsset(ctx, asset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return putPlan(ctx, plan)
+}
+
+// ReadPurchasePlan returns the purchase plan stored in the world state with given id.
+func (s *SmartContract) ReadPurchasePlan(ctx contractapi.TransactionContextInterface, planID string) (*PurchasePlan, error) {
+	planKey, err := ctx.GetStub().CreateCompositeKey(planObjectType, []string{planID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	planJSON, err := ctx.GetStub().GetState(planKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if planJSON == nil {
+		return nil, fmt.Errorf("the purchase plan %s does not exist", planID)
+	}
+
+	var plan PurchasePlan
+	err = json.Unmarshal(planJSON, &plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+// putPlan writes the given purchase plan to the world state
+func putPlan(ctx contractapi.TransactionContextInterface, plan *PurchasePlan) error {
+	planKey, err := ctx.GetStub().CreateCompositeKey(planObjectType, []string{plan.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(planKey, planJSON)
+}
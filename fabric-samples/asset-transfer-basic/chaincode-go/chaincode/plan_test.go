@@ -0,0 +1,52 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPurchasePlan(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("plan1")
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	planID, err := assetTransfer.OfferPurchasePlan(transactionContext, "asset1", "user2", 6000, 1000)
+	require.NoError(t, err)
+	err = assetTransfer.AcceptPurchasePlan(transactionContext, planID)
+	require.NoError(t, err)
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.True(t, asset.Encumbered)
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user3", false, 6000)
+	require.EqualError(t, err, "the asset asset1 is encumbered and cannot be transferred")
+
+	err = assetTransfer.PayInstallment(transactionContext, planID, 5001)
+	require.EqualError(t, err, "amount exceeds the remaining balance of 5000")
+	err = assetTransfer.PayInstallment(transactionContext, planID, 2000)
+	require.NoError(t, err)
+	err = assetTransfer.PayInstallment(transactionContext, planID, 3000)
+	require.EqualError(t, err, "user user2 doesn't have enough money on his account")
+
+	require.NoError(t, assetTransfer.DepositFunds(transactionContext, "user2", 1000))
+	err = assetTransfer.PayInstallment(transactionContext, planID, 3000)
+	require.NoError(t, err)
+
+	plan, err := assetTransfer.ReadPurchasePlan(transactionContext, planID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.PlanPaid, plan.Status)
+	require.Equal(t, int64(0), plan.Remaining)
+	state.get(t, "asset1", asset)
+	require.False(t, asset.Encumbered)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(6000), seller.Money)
+}
@@ -0,0 +1,243 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	plateIndexName         = "plate~asset"
+	plateHistoryObjectType = "plateHistory"
+)
+
+var platePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{0,9}$`)
+
+// AssignPlate lets the registry assign a free license plate to the asset. The plate the asset had
+// before is released and may be assigned again.
+func (s *SmartContract) AssignPlate(ctx contractapi.TransactionContextInterface, assetID string, plate string, registrarID string) error {
+	registrar, err := s.readRegistrar(ctx, registrarID)
+	if err != nil {
+		return err
+	}
+	plate = strings.ToUpper(plate)
+	if !platePattern.MatchString(plate) {
+		return fmt.Errorf("invalid license plate %s", plate)
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	holder, err := plateHolder(ctx, plate)
+	if err != nil {
+		return err
+	}
+	if holder != "" {
+		return fmt.Errorf("license plate %s is already assigned to asset %s", plate, holder)
+	}
+
+	return s.changePlate(ctx, asset, plate, registrar)
+}
+
+// ReassignPlate lets the registry move a license plate from the asset holding it to another asset,
+// which gives up its own plate
+func (s *SmartContract) ReassignPlate(ctx contractapi.TransactionContextInterface, plate string, toAssetID string, registrarID string) error {
+	registrar, err := s.readRegistrar(ctx, registrarID)
+	if err != nil {
+		return err
+	}
+	plate = strings.ToUpper(plate)
+	holder, err := plateHolder(ctx, plate)
+	if err != nil {
+		return err
+	}
+	if holder == "" {
+		return fmt.Errorf("license plate %s is not assigned", plate)
+	}
+	if holder == toAssetID {
+		return fmt.Errorf("license plate %s is already assigned to asset %s", plate, holder)
+	}
+	from, err := s.ReadAsset(ctx, holder)
+	if err != nil {
+		return err
+	}
+	to, err := s.ReadAsset(ctx, toAssetID)
+	if err != nil {
+		return err
+	}
+
+	err = s.changePlate(ctx, from, "", registrar)
+	if err != nil {
+		return err
+	}
+	return s.changePlate(ctx, to, plate, registrar)
+}
+
+// RemovePlate lets the registry take the license plate off the asset, releasing it
+func (s *SmartContract) RemovePlate(ctx contractapi.TransactionContextInterface, assetID string, registrarID string) error {
+	registrar, err := s.readRegistrar(ctx, registrarID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.Plate == "" {
+		return fmt.Errorf("the asset %s has no license plate", assetID)
+	}
+
+	return s.changePlate(ctx, asset, "", registrar)
+}
+
+// GetAssetByPlate returns the asset the license plate is assigned to
+func (s *SmartContract) GetAssetByPlate(ctx contractapi.TransactionContextInterface, plate string) (*Asset, error) {
+	holder, err := plateHolder(ctx, strings.ToUpper(plate))
+	if err != nil {
+		return nil, err
+	}
+	if holder == "" {
+		return nil, fmt.Errorf("no asset with license plate %s", plate)
+	}
+
+	return s.ReadAsset(ctx, holder)
+}
+
+// GetPlateHistory returns the license plate changes of the asset, oldest first
+func (s *SmartContract) GetPlateHistory(ctx contractapi.TransactionContextInterface, assetID string) ([]*PlateRecord, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(plateHistoryObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var records []*PlateRecord
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var record PlateRecord
+		err = json.Unmarshal(queryResponse.Value, &record)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].At.Before(records[j].At)
+	})
+
+	return records, nil
+}
+
+// readRegistrar returns the registry user acting in the transaction
+func (s *SmartContract) readRegistrar(ctx contractapi.TransactionContextInterface, registrarID string) (*User, error) {
+	registrar, err := s.ReadUser(ctx, registrarID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, registrar)
+	if err != nil {
+		return nil, err
+	}
+	err = requireRole(registrar, RoleDMV)
+	if err != nil {
+		return nil, err
+	}
+
+	return registrar, nil
+}
+
+// changePlate replaces the license plate of the asset, keeping the plate index and the history of
+// the asset up to date. An empty plate leaves the asset without one.
+func (s *SmartContract) changePlate(ctx contractapi.TransactionContextInterface, asset *Asset, plate string, registrar *User) error {
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	record := PlateRecord{
+		AssetID:     asset.ID,
+		TxID:        ctx.GetStub().GetTxID(),
+		RegistrarID: registrar.ID,
+		At:          now,
+	}
+
+	if asset.Plate != "" {
+		indexKey, err := plateIndexKey(ctx, asset.Plate)
+		if err != nil {
+			return err
+		}
+		err = ctx.GetStub().DelState(indexKey)
+		if err != nil {
+			return err
+		}
+		record.Plate, record.Action = asset.Plate, PlateRemoved
+		err = putPlateRecord(ctx, &record, 0)
+		if err != nil {
+			return err
+		}
+	}
+	if plate != "" {
+		indexKey, err := plateIndexKey(ctx, plate)
+		if err != nil {
+			return err
+		}
+		err = ctx.GetStub().PutState(indexKey, []byte(asset.ID))
+		if err != nil {
+			return err
+		}
+		record.Plate, record.Action = plate, PlateAssigned
+		err = putPlateRecord(ctx, &record, 1)
+		if err != nil {
+			return err
+		}
+	}
+
+	asset.Plate = plate
+	return putAsset(ctx, asset)
+}
+
+// plateHolder returns the ID of the asset the license plate is assigned to, empty when it is free
+func plateHolder(ctx contractapi.TransactionContextInterface, plate string) (string, error) {
+	indexKey, err := plateIndexKey(ctx, plate)
+	if err != nil {
+		return "", err
+	}
+	assetID, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to read from world state: %v", err)
+	}
+
+	return string(assetID), nil
+}
+
+// plateIndexKey returns the key of the plate index entry for given license plate
+func plateIndexKey(ctx contractapi.TransactionContextInterface, plate string) (string, error) {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(plateIndexName, []string{plate})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return indexKey, nil
+}
+
+// putPlateRecord writes the plate history record to the world state. A transaction removing one
+// plate and assigning another writes two records, told apart by seq.
+func putPlateRecord(ctx contractapi.TransactionContextInterface, record *PlateRecord, seq int) error {
+	recordKey, err := ctx.GetStub().CreateCompositeKey(plateHistoryObjectType, []string{record.AssetID, record.TxID, fmt.Sprint(seq)})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(recordKey, recordJSON)
+}
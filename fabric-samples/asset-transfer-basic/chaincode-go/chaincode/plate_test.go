@@ -0,0 +1,55 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicensePlates(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleDMV}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.AssignPlate(transactionContext, "asset1", "bg-123-aa", "user1")
+	require.EqualError(t, err, "the user user1 does not have role dmv")
+	err = assetTransfer.AssignPlate(transactionContext, "asset1", "bg 123", "user2")
+	require.EqualError(t, err, "invalid license plate BG 123")
+	chaincodeStub.GetTxIDReturns("tx1")
+	err = assetTransfer.AssignPlate(transactionContext, "asset1", "bg-123-aa", "user2")
+	require.NoError(t, err)
+	err = assetTransfer.AssignPlate(transactionContext, "asset2", "BG-123-AA", "user2")
+	require.EqualError(t, err, "license plate BG-123-AA is already assigned to asset asset1")
+	chaincodeStub.GetTxIDReturns("tx2")
+	err = assetTransfer.AssignPlate(transactionContext, "asset2", "NS-456-BB", "user2")
+	require.NoError(t, err)
+
+	chaincodeStub.GetTxIDReturns("tx3")
+	err = assetTransfer.ReassignPlate(transactionContext, "BG-123-AA", "asset2", "user2")
+	require.NoError(t, err)
+	asset, err := assetTransfer.GetAssetByPlate(transactionContext, "bg-123-aa")
+	require.NoError(t, err)
+	require.Equal(t, "asset2", asset.ID)
+	asset, err = assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Empty(t, asset.Plate)
+	_, err = assetTransfer.GetAssetByPlate(transactionContext, "NS-456-BB")
+	require.EqualError(t, err, "no asset with license plate NS-456-BB")
+
+	history, err := assetTransfer.GetPlateHistory(transactionContext, "asset2")
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+	require.Equal(t, []string{"NS-456-BB", "NS-456-BB", "BG-123-AA"}, []string{history[0].Plate, history[1].Plate, history[2].Plate})
+	require.Equal(t, []string{chaincode.PlateAssigned, chaincode.PlateRemoved, chaincode.PlateAssigned}, []string{history[0].Action, history[1].Action, history[2].Action})
+
+	err = assetTransfer.RemovePlate(transactionContext, "asset2", "user2")
+	require.NoError(t, err)
+	err = assetTransfer.RemovePlate(transactionContext, "asset2", "user2")
+	require.EqualError(t, err, "the asset asset2 has no license plate")
+}
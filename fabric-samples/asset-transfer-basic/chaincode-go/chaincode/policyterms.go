@@ -0,0 +1,104 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const policyTermsTransientKey = "policy_terms"
+
+// VerifyPolicyTerms reports whether the given policy terms document is the one the policy was created with.
+func (s *SmartContract) VerifyPolicyTerms(ctx contractapi.TransactionContextInterface, policyID string, termsJSON string) (bool, error) {
+	policy, err := readPolicy(ctx, policyID)
+	if err != nil {
+		return false, err
+	}
+
+	return hashPolicyTerms([]byte(termsJSON)) == policy.TermsHash, nil
+}
+
+// readPolicyTerms returns the terms of the policy, taken from the policy_terms transient field when
+// present and from the insurer's private data collection otherwise. Either way they must match the
+// hash on the policy.
+func readPolicyTerms(ctx contractapi.TransactionContextInterface, policy *InsurancePolicy) (*PolicyTerms, error) {
+	termsJSON, err := transientPolicyTerms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if termsJSON == nil {
+		termsJSON, err = ctx.GetStub().GetPrivateData(policy.TermsCollection, policy.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from private data collection %s: %v", policy.TermsCollection, err)
+		}
+	}
+	if termsJSON == nil {
+		return nil, fmt.Errorf("the terms of policy %s must be passed in the %s transient field", policy.ID, policyTermsTransientKey)
+	}
+	if hashPolicyTerms(termsJSON) != policy.TermsHash {
+		return nil, fmt.Errorf("the terms do not match the hash of policy %s", policy.ID)
+	}
+
+	return parsePolicyTerms(termsJSON)
+}
+
+// transientPolicyTerms returns the policy terms passed in the transient data, or nil when none were passed
+func transientPolicyTerms(ctx contractapi.TransactionContextInterface) ([]byte, error) {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return nil, fmt.Errorf("error getting transient: %v", err)
+	}
+
+	return transientMap[policyTermsTransientKey], nil
+}
+
+// parsePolicyTerms decodes and validates the policy terms JSON
+func parsePolicyTerms(termsJSON []byte) (*PolicyTerms, error) {
+	var terms PolicyTerms
+	err := json.Unmarshal(termsJSON, &terms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy terms: %v", err)
+	}
+	if terms.Premium <= 0 || terms.Coverage <= 0 {
+		return nil, fmt.Errorf("premium and coverage must be positive")
+	}
+	if terms.Deductible < 0 {
+		return nil, fmt.Errorf("deductible must not be negative")
+	}
+
+	return &terms, nil
+}
+
+// hashPolicyTerms returns the hex encoded SHA-256 of the policy terms JSON
+func hashPolicyTerms(termsJSON []byte) string {
+	hash := sha256.Sum256(termsJSON)
+	return hex.EncodeToString(hash[:])
+}
+
+// clientOrgCollection returns the implicit private data collection of the submitting client's organization
+func clientOrgCollection(ctx contractapi.TransactionContextInterface) (string, error) {
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return orgCollection(mspID), nil
+}
+
+// orgCollection returns the implicit private data collection of the organization with given MSP ID
+func orgCollection(mspID string) string {
+	return "_implicit_org_" + mspID
+}
+
+// clientMSPID returns the MSP ID of the submitting client's organization
+func clientMSPID(ctx contractapi.TransactionContextInterface) (string, error) {
+	mspID, err := ctx.GetClientIdentity().GetMSPID()
+	if err != nil {
+		return "", fmt.Errorf("failed getting client's orgID: %v", err)
+	}
+
+	return mspID, nil
+}
@@ -0,0 +1,70 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const salePriceObjectType = "asset~salePrice"
+
+// GetPriceHistory returns the prices the asset was sold for, oldest first. Gifts are not sales and
+// are left out.
+func (s *SmartContract) GetPriceHistory(ctx contractapi.TransactionContextInterface, assetID string) ([]*SalePriceRecord, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(salePriceObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var records []*SalePriceRecord
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var record SalePriceRecord
+		err = json.Unmarshal(queryResponse.Value, &record)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, &record)
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].SoldAt.Before(records[j].SoldAt)
+	})
+
+	return records, nil
+}
+
+// recordSalePrice adds the price of a completed sale to the price history of the asset
+func recordSalePrice(ctx contractapi.TransactionContextInterface, asset *Asset, price int64) error {
+	if price == 0 {
+		return nil
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	record := SalePriceRecord{
+		AssetID: asset.ID,
+		TxID:    ctx.GetStub().GetTxID(),
+		Price:   price,
+		Mileage: asset.Mileage,
+		SoldAt:  now,
+	}
+	recordKey, err := ctx.GetStub().CreateCompositeKey(salePriceObjectType, []string{asset.ID, record.TxID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(recordKey, recordJSON)
+}
@@ -0,0 +1,43 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriceHistory(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "buyer"})
+	state.put(t, "user3", &chaincode.User{ID: "user3"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000, Mileage: 40000})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("seller", nil)
+	chaincodeStub.GetTxIDReturns("offer1")
+	offerID, err := assetTransfer.OfferAsset(transactionContext, "asset1", "user2", 4000, "")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("tx1")
+	err = assetTransfer.AcceptOffer(transactionContext, offerID)
+	require.NoError(t, err)
+
+	chaincodeStub.GetTxIDReturns("tx2")
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600086400}, nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+
+	history, err := assetTransfer.GetPriceHistory(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.SalePriceRecord{
+		{AssetID: "asset1", TxID: "tx1", Price: 4000, Mileage: 40000, SoldAt: time.Unix(1600000000, 0).UTC()},
+	}, history)
+}
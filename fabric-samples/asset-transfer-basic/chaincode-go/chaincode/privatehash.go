@@ -0,0 +1,41 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetPrivateDataHash returns the hex encoded hash of the private data stored under key in the
+// collection. Peers keep the hashes of every collection, so organizations that are not members of
+// the collection can read it too.
+func (s *SmartContract) GetPrivateDataHash(ctx contractapi.TransactionContextInterface, collection string, key string) (string, error) {
+	hash, err := ctx.GetStub().GetPrivateDataHash(collection, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to read private data hash from collection %s: %v", collection, err)
+	}
+	if hash == nil {
+		return "", fmt.Errorf("no private data under key %s in collection %s", key, collection)
+	}
+
+	return hex.EncodeToString(hash), nil
+}
+
+// VerifyAssetPrivateHash reports whether providedJSON is the private AssetDetails of the asset
+// stored in the collection. A counterparty that received the details out of band can prove them
+// this way without being a member of the collection.
+func (s *SmartContract) VerifyAssetPrivateHash(ctx contractapi.TransactionContextInterface, collection string, assetID string, providedJSON string) (bool, error) {
+	detailsKey, err := ctx.GetStub().CreateCompositeKey(assetDetailsObjectType, []string{assetID})
+	if err != nil {
+		return false, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	storedHash, err := s.GetPrivateDataHash(ctx, collection, detailsKey)
+	if err != nil {
+		return false, err
+	}
+	hash := sha256.Sum256([]byte(providedJSON))
+
+	return hex.EncodeToString(hash[:]) == storedHash, nil
+}
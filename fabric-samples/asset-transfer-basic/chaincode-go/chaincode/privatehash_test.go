@@ -0,0 +1,33 @@
+package chaincode_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyAssetPrivateHash(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	details := `{"assetID":"asset1","brand":"Toyota","model":"Prius","year":2015,"color":"blue","value":3000,"salt":"beef"}`
+	state["_implicit_org_Org1MSP/\x00details\x00asset1\x00"] = []byte(details)
+
+	assetTransfer := chaincode.SmartContract{}
+	hash, err := assetTransfer.GetPrivateDataHash(transactionContext, "_implicit_org_Org1MSP", "\x00details\x00asset1\x00")
+	require.NoError(t, err)
+	expected := sha256.Sum256([]byte(details))
+	require.Equal(t, hex.EncodeToString(expected[:]), hash)
+
+	verified, err := assetTransfer.VerifyAssetPrivateHash(transactionContext, "_implicit_org_Org1MSP", "asset1", details)
+	require.NoError(t, err)
+	require.True(t, verified)
+	verified, err = assetTransfer.VerifyAssetPrivateHash(transactionContext, "_implicit_org_Org1MSP", "asset1", `{"assetID":"asset1"}`)
+	require.NoError(t, err)
+	require.False(t, verified)
+
+	_, err = assetTransfer.VerifyAssetPrivateHash(transactionContext, "_implicit_org_Org2MSP", "asset1", details)
+	require.EqualError(t, err, "no private data under key \x00details\x00asset1\x00 in collection _implicit_org_Org2MSP")
+}
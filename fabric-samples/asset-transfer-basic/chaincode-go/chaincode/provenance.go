@@ -0,0 +1,103 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// GetOwnershipChain reconstructs the owners of the asset from the history of its key, with the
+// date and price of every change of ownership
+func (s *SmartContract) GetOwnershipChain(ctx contractapi.TransactionContextInterface, assetID string) (*ProvenanceReport, error) {
+	resultsIterator, err := ctx.GetStub().GetHistoryForKey(assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	type version struct {
+		txID      string
+		timestamp time.Time
+		ownerID   string
+		isDelete  bool
+	}
+	var versions []*version
+	for resultsIterator.HasNext() {
+		modification, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		timestamp, err := ptypes.Timestamp(modification.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		v := version{txID: modification.TxId, timestamp: timestamp, isDelete: modification.IsDelete}
+		if !modification.IsDelete {
+			var asset Asset
+			err = decodeAsset(modification.Value, &asset)
+			if err != nil {
+				return nil, err
+			}
+			v.ownerID = asset.OwnerID
+		}
+		versions = append(versions, &v)
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("the asset %s does not exist", assetID)
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].timestamp.Before(versions[j].timestamp)
+	})
+
+	report := ProvenanceReport{AssetID: assetID, Owners: []*OwnershipPeriod{}}
+	var current *OwnershipPeriod
+	for _, v := range versions {
+		report.Deleted = v.isDelete
+		if current != nil && (v.isDelete || v.ownerID != current.OwnerID) {
+			current.Until = v.timestamp
+			current = nil
+		}
+		if v.isDelete || current != nil {
+			continue
+		}
+		current = &OwnershipPeriod{OwnerID: v.ownerID, TxID: v.txID, From: v.timestamp}
+		transfer, err := readTransferRecord(ctx, assetID, v.txID)
+		if err != nil {
+			return nil, err
+		}
+		if transfer != nil {
+			current.Price = transfer.Price
+		}
+		report.Owners = append(report.Owners, current)
+	}
+
+	return &report, nil
+}
+
+// readTransferRecord returns the sale of the asset recorded by given transaction, nil when it recorded none
+func readTransferRecord(ctx contractapi.TransactionContextInterface, assetID string, txID string) (*TransferRecord, error) {
+	transferKey, err := ctx.GetStub().CreateCompositeKey(transferObjectType, []string{assetID, txID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	transferJSON, err := ctx.GetStub().GetState(transferKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if transferJSON == nil {
+		return nil, nil
+	}
+
+	var transfer TransferRecord
+	err = json.Unmarshal(transferJSON, &transfer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
@@ -0,0 +1,46 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOwnershipChain(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "\x00transfer\x00asset1\x00tx3\x00", &chaincode.TransferRecord{TxID: "tx3", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 5000})
+	iterator := &mocks.HistoryQueryIterator{}
+	modifications := []*queryresult.KeyModification{
+		{TxId: "tx3", Value: []byte(`{"ID":"asset1","owner":"user2"}`), Timestamp: &timestamp.Timestamp{Seconds: 30}},
+		{TxId: "tx1", Value: []byte(`{"ID":"asset1","owner":"user1"}`), Timestamp: &timestamp.Timestamp{Seconds: 10}},
+		{TxId: "tx2", Value: []byte(`{"ID":"asset1","owner":"user1","color":"red"}`), Timestamp: &timestamp.Timestamp{Seconds: 20}},
+		{TxId: "tx4", Value: []byte(`{"ID":"asset1","owner":"user3"}`), Timestamp: &timestamp.Timestamp{Seconds: 40}},
+	}
+	for i, modification := range modifications {
+		iterator.HasNextReturnsOnCall(i, true)
+		iterator.NextReturnsOnCall(i, modification, nil)
+	}
+	chaincodeStub.GetHistoryForKeyReturns(iterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	report, err := assetTransfer.GetOwnershipChain(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.ProvenanceReport{
+		AssetID: "asset1",
+		Owners: []*chaincode.OwnershipPeriod{
+			{OwnerID: "user1", TxID: "tx1", From: time.Unix(10, 0).UTC(), Until: time.Unix(30, 0).UTC()},
+			{OwnerID: "user2", TxID: "tx3", From: time.Unix(30, 0).UTC(), Until: time.Unix(40, 0).UTC(), Price: 5000},
+			{OwnerID: "user3", TxID: "tx4", From: time.Unix(40, 0).UTC()},
+		},
+	}, report)
+
+	chaincodeStub.GetHistoryForKeyReturns(&mocks.HistoryQueryIterator{}, nil)
+	_, err = assetTransfer.GetOwnershipChain(transactionContext, "asset9")
+	require.EqualError(t, err, "the asset asset9 does not exist")
+}
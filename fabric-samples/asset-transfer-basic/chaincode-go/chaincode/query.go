@@ -0,0 +1,140 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// assetDocType marks assets in the world state for the CouchDB indexes shipped in
+// META-INF/statedb/couchdb/indexes
+const assetDocType = "asset"
+
+// CouchDB design documents and indexes of the asset queries
+const (
+	ownerIndexDoc      = "indexOwnerDoc"
+	ownerIndex         = "indexOwner"
+	brandModelIndexDoc = "indexBrandModelDoc"
+	brandModelIndex    = "indexBrandModel"
+	statusIndexDoc     = "indexStatusDoc"
+	statusIndex        = "indexStatus"
+	frozenIndexDoc     = "indexFrozenDoc"
+	frozenIndex        = "indexFrozen"
+)
+
+// QueryAssetsByOwner returns the assets of the owner using the owner index.
+// Rich queries require CouchDB as the state database.
+func (s *SmartContract) QueryAssetsByOwner(ctx contractapi.TransactionContextInterface, owner string) ([]*Asset, error) {
+	return queryAssets(ctx, map[string]interface{}{"owner": owner}, ownerIndexDoc, ownerIndex)
+}
+
+// QueryAssetsByBrandModel returns the assets of given brand and model using the brand and model
+// index. An empty model returns every model of the brand.
+func (s *SmartContract) QueryAssetsByBrandModel(ctx contractapi.TransactionContextInterface, brand string, model string) ([]*Asset, error) {
+	selector := map[string]interface{}{"brand": brand}
+	if model != "" {
+		selector["model"] = model
+	}
+
+	return queryAssets(ctx, selector, brandModelIndexDoc, brandModelIndex)
+}
+
+// QueryAssetsByStatus returns the assets with given status using the status index. An empty status
+// returns the roadworthy assets. AssetFrozen returns the assets on administrative hold, whatever
+// their status, using the frozen index.
+func (s *SmartContract) QueryAssetsByStatus(ctx contractapi.TransactionContextInterface, status string) ([]*Asset, error) {
+	if status == AssetFrozen {
+		return queryAssets(ctx, map[string]interface{}{"frozen": true}, frozenIndexDoc, frozenIndex)
+	}
+
+	return queryAssets(ctx, map[string]interface{}{"status": status}, statusIndexDoc, statusIndex)
+}
+
+// queryAssets runs a CouchDB query for assets matching the selector against given index. The
+// query is built by marshaling, so field values cannot change the shape of the selector.
+func queryAssets(ctx contractapi.TransactionContextInterface, selector map[string]interface{}, designDoc string, index string) ([]*Asset, error) {
+	selector["docType"] = assetDocType
+	queryJSON, err := json.Marshal(map[string]interface{}{
+		"selector":  selector,
+		"use_index": []string{"_design/" + designDoc, index},
+	})
+	if err != nil {
+		return nil, err
+	}
+	resultsIterator, err := ctx.GetStub().GetQueryResult(string(queryJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assets: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	return readAssetResults(ctx, resultsIterator)
+}
+
+// maxPageSize bounds the number of assets returned in one page
+const maxPageSize = 200
+
+// QueryAssetsWithPagination returns a page of at most pageSize assets matching the CouchDB
+// selector, given as a JSON object, starting at bookmark. An empty bookmark starts at the first
+// page. Only assets are matched, whatever the selector says about docType.
+func (s *SmartContract) QueryAssetsWithPagination(ctx contractapi.TransactionContextInterface, selector string, pageSize int32, bookmark string) (*PaginatedQueryResult, error) {
+	if pageSize <= 0 || pageSize > maxPageSize {
+		return nil, fmt.Errorf("page size must be between 1 and %d", maxPageSize)
+	}
+	var fields map[string]interface{}
+	if selector != "" {
+		err := json.Unmarshal([]byte(selector), &fields)
+		if err != nil {
+			return nil, fmt.Errorf("selector must be a JSON object: %v", err)
+		}
+	}
+	if fields == nil {
+		fields = map[string]interface{}{}
+	}
+	fields["docType"] = assetDocType
+	queryJSON, err := json.Marshal(map[string]interface{}{"selector": fields})
+	if err != nil {
+		return nil, err
+	}
+	resultsIterator, metadata, err := ctx.GetStub().GetQueryResultWithPagination(string(queryJSON), pageSize, bookmark)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query assets: %v", err)
+	}
+	defer resultsIterator.Close()
+
+	assets, err := readAssetResults(ctx, resultsIterator)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PaginatedQueryResult{
+		Records:      assets,
+		FetchedCount: metadata.FetchedRecordsCount,
+		Bookmark:     metadata.Bookmark,
+	}, nil
+}
+
+// readAssetResults returns the assets of a query result together with their damages
+func readAssetResults(ctx contractapi.TransactionContextInterface, resultsIterator shim.StateQueryIteratorInterface) ([]*Asset, error) {
+	var assets []*Asset
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var asset Asset
+		err = decodeAsset(queryResponse.Value, &asset)
+		if err != nil {
+			return nil, err
+		}
+		err = loadDamages(ctx, &asset)
+		if err != nil {
+			return nil, err
+		}
+		assets = append(assets, &asset)
+	}
+
+	return assets, nil
+}
@@ -0,0 +1,76 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAssets(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "asset1", &chaincode.Asset{DocType: "asset", ID: "asset1", Brand: "fiat", Model: "punto", OwnerID: "user1"})
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Key: "asset1", Value: state["asset1"]}, nil)
+	chaincodeStub.GetQueryResultReturns(iterator, nil)
+	assetTransfer := chaincode.SmartContract{}
+
+	assets, err := assetTransfer.QueryAssetsByBrandModel(transactionContext, "fiat", "punto")
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	require.Equal(t, "asset1", assets[0].ID)
+	require.Equal(t, `{"selector":{"brand":"fiat","docType":"asset","model":"punto"},"use_index":["_design/indexBrandModelDoc","indexBrandModel"]}`, chaincodeStub.GetQueryResultArgsForCall(0))
+
+	chaincodeStub.GetQueryResultReturns(&mocks.StateQueryIterator{}, nil)
+	assets, err = assetTransfer.QueryAssetsByOwner(transactionContext, `user1","$or":[{}]`)
+	require.NoError(t, err)
+	require.Empty(t, assets)
+	require.Equal(t, `{"selector":{"docType":"asset","owner":"user1\",\"$or\":[{}]"},"use_index":["_design/indexOwnerDoc","indexOwner"]}`, chaincodeStub.GetQueryResultArgsForCall(1))
+
+	_, err = assetTransfer.QueryAssetsByStatus(transactionContext, chaincode.AssetTotaled)
+	require.NoError(t, err)
+	require.Equal(t, `{"selector":{"docType":"asset","status":"totaled"},"use_index":["_design/indexStatusDoc","indexStatus"]}`, chaincodeStub.GetQueryResultArgsForCall(2))
+
+	_, err = assetTransfer.QueryAssetsByStatus(transactionContext, chaincode.AssetFrozen)
+	require.NoError(t, err)
+	require.Equal(t, `{"selector":{"docType":"asset","frozen":true},"use_index":["_design/indexFrozenDoc","indexFrozen"]}`, chaincodeStub.GetQueryResultArgsForCall(3))
+}
+
+func TestQueryAssetsWithPagination(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "asset1", &chaincode.Asset{DocType: "asset", ID: "asset1", Color: "blue"})
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Key: "asset1", Value: state["asset1"]}, nil)
+	chaincodeStub.GetQueryResultWithPaginationReturns(iterator, &peer.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "next"}, nil)
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.QueryAssetsWithPagination(transactionContext, `{"color":"blue"}`, 0, "")
+	require.EqualError(t, err, "page size must be between 1 and 200")
+	_, err = assetTransfer.QueryAssetsWithPagination(transactionContext, `["color"]`, 10, "")
+	require.Error(t, err)
+
+	page, err := assetTransfer.QueryAssetsWithPagination(transactionContext, `{"color":"blue","docType":"user"}`, 10, "start")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), page.FetchedCount)
+	require.Equal(t, "next", page.Bookmark)
+	require.Len(t, page.Records, 1)
+	require.Equal(t, "asset1", page.Records[0].ID)
+	query, pageSize, bookmark := chaincodeStub.GetQueryResultWithPaginationArgsForCall(0)
+	require.Equal(t, `{"selector":{"color":"blue","docType":"asset"}}`, query)
+	require.Equal(t, int32(10), pageSize)
+	require.Equal(t, "start", bookmark)
+
+	_, err = assetTransfer.QueryAssetsWithPagination(transactionContext, "null", 10, "")
+	require.NoError(t, err)
+	query, _, _ = chaincodeStub.GetQueryResultWithPaginationArgsForCall(1)
+	require.Equal(t, `{"selector":{"docType":"asset"}}`, query)
+}
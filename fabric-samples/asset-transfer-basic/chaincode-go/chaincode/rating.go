@@ -0,0 +1,91 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	ratingObjectType     = "mechanic~rating"
+	maxRatingCommentSize = 280
+)
+
+// RateMechanic lets the owner rate the mechanic of a completed repair job with a score from 1 to 5
+// and a short comment. Each job can be rated once.
+func (s *SmartContract) RateMechanic(ctx contractapi.TransactionContextInterface, jobID string, score int, comment string) error {
+	if score < 1 || score > 5 {
+		return fmt.Errorf("score must be between 1 and 5")
+	}
+	if len(comment) > maxRatingCommentSize {
+		return fmt.Errorf("comment must not be longer than %d characters", maxRatingCommentSize)
+	}
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status != RepairCompleted && job.Status != RepairPaid {
+		return fmt.Errorf("the repair job %s is %s, only completed repairs can be rated", jobID, job.Status)
+	}
+	owner, err := s.readRepairOwner(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	ratingKey, err := ctx.GetStub().CreateCompositeKey(ratingObjectType, []string{job.MechanicID, jobID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	existing, err := ctx.GetStub().GetState(ratingKey)
+	if err != nil {
+		return fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if existing != nil {
+		return fmt.Errorf("the repair job %s is already rated", jobID)
+	}
+
+	ratingJSON, err := json.Marshal(Rating{
+		MechanicID: job.MechanicID,
+		JobID:      jobID,
+		OwnerID:    owner.ID,
+		Score:      score,
+		Comment:    comment,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(ratingKey, ratingJSON)
+}
+
+// GetMechanicRating returns the average score and the number of ratings of the mechanic with given ID
+func (s *SmartContract) GetMechanicRating(ctx contractapi.TransactionContextInterface, mechanicID string) (*MechanicRating, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(ratingObjectType, []string{mechanicID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	summary := MechanicRating{MechanicID: mechanicID}
+	total := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var rating Rating
+		err = json.Unmarshal(queryResponse.Value, &rating)
+		if err != nil {
+			return nil, err
+		}
+		total = total + rating.Score
+		summary.Count++
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(total) / float64(summary.Count)
+	}
+
+	return &summary, nil
+}
@@ -0,0 +1,51 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateMechanic(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", OwnerID: "user1", MechanicID: "user3", Status: chaincode.RepairInProgress})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.RateMechanic(transactionContext, "job1", 6, "")
+	require.EqualError(t, err, "score must be between 1 and 5")
+
+	err = assetTransfer.RateMechanic(transactionContext, "job1", 5, "fast and clean")
+	require.EqualError(t, err, "the repair job job1 is InProgress, only completed repairs can be rated")
+
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", OwnerID: "user1", MechanicID: "user3", Status: chaincode.RepairPaid})
+	err = assetTransfer.RateMechanic(transactionContext, "job1", 5, "fast and clean")
+	require.NoError(t, err)
+	rating := &chaincode.Rating{}
+	state.get(t, "\x00mechanic~rating\x00user3\x00job1\x00", rating)
+	require.Equal(t, &chaincode.Rating{MechanicID: "user3", JobID: "job1", OwnerID: "user1", Score: 5, Comment: "fast and clean"}, rating)
+
+	err = assetTransfer.RateMechanic(transactionContext, "job1", 1, "")
+	require.EqualError(t, err, "the repair job job1 is already rated")
+}
+
+func TestGetMechanicRating(t *testing.T) {
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, true)
+	iterator.HasNextReturnsOnCall(2, false)
+	iterator.NextReturnsOnCall(0, &queryresult.KV{Value: []byte(`{"mechanicID":"user3","score":5}`)}, nil)
+	iterator.NextReturnsOnCall(1, &queryresult.KV{Value: []byte(`{"mechanicID":"user3","score":2}`)}, nil)
+
+	transactionContext, chaincodeStub := prepMocks(worldState{})
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(iterator, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	rating, err := assetTransfer.GetMechanicRating(transactionContext, "user3")
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.MechanicRating{MechanicID: "user3", Average: 3.5, Count: 2}, rating)
+}
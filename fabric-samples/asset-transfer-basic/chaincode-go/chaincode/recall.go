@@ -0,0 +1,178 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	recallObjectType    = "recall"
+	recallFixObjectType = "recallFix"
+)
+
+// OpenRecall lets a manufacturer open a recall for the cars of the brand and model built from
+// fromYear to toYear. Every such car is flagged until a mechanic fixes it with CompleteRecallFix.
+// It returns the ID of the new recall.
+func (s *SmartContract) OpenRecall(ctx contractapi.TransactionContextInterface, manufacturerID string, brand string, model string, fromYear int, toYear int, description string) (string, error) {
+	if fromYear > toYear {
+		return "", fmt.Errorf("invalid year range %d-%d", fromYear, toYear)
+	}
+	if description == "" {
+		return "", fmt.Errorf("description must not be empty")
+	}
+	manufacturer, err := s.ReadUser(ctx, manufacturerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, manufacturer)
+	if err != nil {
+		return "", err
+	}
+	err = requireRole(manufacturer, RoleManufacturer)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+	assets, err := s.GetAllAssets(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	recall := Recall{
+		ID:             ctx.GetStub().GetTxID(),
+		ManufacturerID: manufacturerID,
+		Brand:          brand,
+		Model:          model,
+		FromYear:       fromYear,
+		ToYear:         toYear,
+		Description:    description,
+		OpenedAt:       now,
+	}
+	for _, asset := range assets {
+		if !strings.EqualFold(asset.Brand, brand) || !strings.EqualFold(asset.Model, model) || asset.Year < fromYear || asset.Year > toYear {
+			continue
+		}
+		asset.Recalls = append(asset.Recalls, recall.ID)
+		err = putAsset(ctx, asset)
+		if err != nil {
+			return "", err
+		}
+		recall.Affected++
+	}
+	err = putRecall(ctx, &recall)
+	if err != nil {
+		return "", err
+	}
+
+	return recall.ID, nil
+}
+
+// CompleteRecallFix lets a mechanic record fixing the recalled defect of the asset, clearing its
+// recall flag
+func (s *SmartContract) CompleteRecallFix(ctx contractapi.TransactionContextInterface, recallID string, assetID string, mechanicID string) error {
+	_, err := s.readMechanic(ctx, mechanicID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if !contains(asset.Recalls, recallID) {
+		return fmt.Errorf("the asset %s is not affected by open recall %s", assetID, recallID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	fix := RecallFix{RecallID: recallID, AssetID: assetID, MechanicID: mechanicID, FixedAt: now}
+	fixKey, err := ctx.GetStub().CreateCompositeKey(recallFixObjectType, []string{recallID, assetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	fixJSON, err := json.Marshal(fix)
+	if err != nil {
+		return err
+	}
+	err = ctx.GetStub().PutState(fixKey, fixJSON)
+	if err != nil {
+		return err
+	}
+
+	recalls := []string{}
+	for _, id := range asset.Recalls {
+		if id != recallID {
+			recalls = append(recalls, id)
+		}
+	}
+	asset.Recalls = recalls
+	return putAsset(ctx, asset)
+}
+
+// GetRecallOwners returns the IDs of the users owning cars not yet fixed for the recall, so they
+// can be notified
+func (s *SmartContract) GetRecallOwners(ctx contractapi.TransactionContextInterface, recallID string) ([]string, error) {
+	_, err := s.ReadRecall(ctx, recallID)
+	if err != nil {
+		return nil, err
+	}
+	assets, err := s.GetAllAssets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	owners := []string{}
+	for _, asset := range assets {
+		if contains(asset.Recalls, recallID) && !contains(owners, asset.OwnerID) {
+			owners = append(owners, asset.OwnerID)
+		}
+	}
+	sort.Strings(owners)
+
+	return owners, nil
+}
+
+// ReadRecall returns the recall stored in the world state with given id
+func (s *SmartContract) ReadRecall(ctx contractapi.TransactionContextInterface, recallID string) (*Recall, error) {
+	recallKey, err := ctx.GetStub().CreateCompositeKey(recallObjectType, []string{recallID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	recallJSON, err := ctx.GetStub().GetState(recallKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if recallJSON == nil {
+		return nil, fmt.Errorf("the recall %s does not exist", recallID)
+	}
+
+	var recall Recall
+	err = json.Unmarshal(recallJSON, &recall)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recall, nil
+}
+
+// putRecall writes the given recall to the world state
+func putRecall(ctx contractapi.TransactionContextInterface, recall *Recall) error {
+	recallKey, err := ctx.GetStub().CreateCompositeKey(recallObjectType, []string{recall.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	recallJSON, err := json.Marshal(recall)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(recallKey, recallJSON)
+}
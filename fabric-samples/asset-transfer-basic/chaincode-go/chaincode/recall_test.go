@@ -0,0 +1,47 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecall(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleManufacturer}})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", Brand: "fiat", Model: "500L", Year: 2018, OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", Brand: "Fiat", Model: "500l", Year: 2016, OwnerID: "user2"})
+	state.put(t, "asset3", &chaincode.Asset{ID: "asset3", Brand: "fiat", Model: "500L", Year: 2013, OwnerID: "user2"})
+	state.put(t, "asset4", &chaincode.Asset{ID: "asset4", Brand: "fiat", Model: "punto", Year: 2017, OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.OpenRecall(transactionContext, "user1", "fiat", "500L", 2015, 2019, "airbag inflator")
+	require.EqualError(t, err, "the user user1 does not have role manufacturer")
+	chaincodeStub.GetTxIDReturns("recall1")
+	recallID, err := assetTransfer.OpenRecall(transactionContext, "user3", "fiat", "500L", 2015, 2019, "airbag inflator")
+	require.NoError(t, err)
+	recall, err := assetTransfer.ReadRecall(transactionContext, recallID)
+	require.NoError(t, err)
+	require.Equal(t, 2, recall.Affected)
+
+	owners, err := assetTransfer.GetRecallOwners(transactionContext, recallID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user1", "user2"}, owners)
+
+	err = assetTransfer.CompleteRecallFix(transactionContext, recallID, "asset3", "user4")
+	require.EqualError(t, err, "the asset asset3 is not affected by open recall recall1")
+	err = assetTransfer.CompleteRecallFix(transactionContext, recallID, "asset1", "user4")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Empty(t, asset.Recalls)
+	owners, err = assetTransfer.GetRecallOwners(transactionContext, recallID)
+	require.NoError(t, err)
+	require.Equal(t, []string{"user2"}, owners)
+}
@@ -0,0 +1,304 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const registrationObjectType = "registration"
+
+const (
+	// registrationRequiredConfig is set to 1 when agreed sales only change ownership once the registry confirms them
+	registrationRequiredConfig = "registrationRequired"
+	registrationTimeoutConfig  = "registrationTimeout" // in seconds
+	defaultRegistrationTimeout = 7 * 24 * 60 * 60
+)
+
+// SetRegistrationRequired turns on or off regulated transfers. While on, sales agreed with
+// TransferAsset, AcceptOffer or SettleAgreedSale create a pending registration that a registry user
+// must confirm before the asset changes hands, and every other way of selling an asset is refused.
+// Only admins may change it.
+func (s *SmartContract) SetRegistrationRequired(ctx contractapi.TransactionContextInterface, required bool) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	value := int64(0)
+	if required {
+		value = 1
+	}
+
+	return putConfigInt(ctx, registrationRequiredConfig, value)
+}
+
+// SetRegistrationTimeout sets how many seconds the registry has to confirm a registration before it
+// expires. Only admins may change it.
+func (s *SmartContract) SetRegistrationTimeout(ctx contractapi.TransactionContextInterface, seconds int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if seconds <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	return putConfigInt(ctx, registrationTimeoutConfig, seconds)
+}
+
+// ConfirmRegistration lets a registry user confirm the pending registration, settling the sale with
+// the held price
+func (s *SmartContract) ConfirmRegistration(ctx contractapi.TransactionContextInterface, registrationID string, dmvID string) error {
+	registration, err := s.readPendingRegistration(ctx, registrationID)
+	if err != nil {
+		return err
+	}
+	_, err = s.readRegistrar(ctx, dmvID)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if now.After(registration.Deadline) {
+		return fmt.Errorf("the registration %s expired at %s", registrationID, registration.Deadline.Format(time.RFC3339))
+	}
+	asset, err := s.ReadAsset(ctx, registration.AssetID)
+	if err != nil {
+		return err
+	}
+	seller, err := s.ReadUser(ctx, registration.SellerID)
+	if err != nil {
+		return err
+	}
+	buyer, err := s.ReadUser(ctx, registration.BuyerID)
+	if err != nil {
+		return err
+	}
+	dealer, err := s.readDealer(ctx, registration.DealerID, asset, buyer.ID)
+	if err != nil {
+		return err
+	}
+
+	buyer.Money = buyer.Money + registration.Price
+	asset.RegistrationID = ""
+	err = settleSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, dealer: dealer, price: registration.Price, upfront: registration.Price, registered: true})
+	if err != nil {
+		return err
+	}
+
+	registration.Status = RegistrationConfirmed
+	registration.DMVID = dmvID
+	return putRegistration(ctx, registration)
+}
+
+// RejectRegistration lets a registry user reject the pending registration, returning the held price
+// to the buyer. The asset stays with the seller.
+func (s *SmartContract) RejectRegistration(ctx contractapi.TransactionContextInterface, registrationID string, dmvID string, reason string) error {
+	registration, err := s.readPendingRegistration(ctx, registrationID)
+	if err != nil {
+		return err
+	}
+	_, err = s.readRegistrar(ctx, dmvID)
+	if err != nil {
+		return err
+	}
+
+	registration.Status = RegistrationRejected
+	registration.DMVID = dmvID
+	registration.Reason = reason
+	return s.closeRegistration(ctx, registration)
+}
+
+// ExpireRegistration returns the held price to the buyer of a registration the registry did not
+// confirm before its deadline. Anyone may expire it.
+func (s *SmartContract) ExpireRegistration(ctx contractapi.TransactionContextInterface, registrationID string) error {
+	registration, err := s.readPendingRegistration(ctx, registrationID)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if !now.After(registration.Deadline) {
+		return fmt.Errorf("the registration %s is open until %s", registrationID, registration.Deadline.Format(time.RFC3339))
+	}
+
+	registration.Status = RegistrationExpired
+	return s.closeRegistration(ctx, registration)
+}
+
+// GetPendingRegistrations returns all registrations waiting for the registry
+func (s *SmartContract) GetPendingRegistrations(ctx contractapi.TransactionContextInterface) ([]*Registration, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(registrationObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var registrations []*Registration
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var registration Registration
+		err = json.Unmarshal(queryResponse.Value, &registration)
+		if err != nil {
+			return nil, err
+		}
+		if registration.Status == RegistrationPending {
+			registrations = append(registrations, &registration)
+		}
+	}
+
+	return registrations, nil
+}
+
+// ReadRegistration returns the registration stored in the world state with given id
+func (s *SmartContract) ReadRegistration(ctx contractapi.TransactionContextInterface, registrationID string) (*Registration, error) {
+	registrationKey, err := ctx.GetStub().CreateCompositeKey(registrationObjectType, []string{registrationID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	registrationJSON, err := ctx.GetStub().GetState(registrationKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if registrationJSON == nil {
+		return nil, fmt.Errorf("the registration %s does not exist", registrationID)
+	}
+
+	var registration Registration
+	err = json.Unmarshal(registrationJSON, &registration)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registration, nil
+}
+
+// settleOrRegister settles the sale, or files it for the registry to confirm while transfers are
+// regulated. It returns the ID of the registration, empty when the sale settled.
+func settleOrRegister(ctx contractapi.TransactionContextInterface, terms *saleTerms) (string, error) {
+	required, err := getConfigInt(ctx, registrationRequiredConfig, 0)
+	if err != nil {
+		return "", err
+	}
+	if required == 0 {
+		return "", settleSale(ctx, terms)
+	}
+
+	asset, buyer := terms.asset, terms.buyer
+	if asset.OwnerID != terms.seller.ID {
+		return "", fmt.Errorf("the asset %s is not owned by user %s", asset.ID, terms.seller.ID)
+	}
+	if asset.RegistrationID != "" {
+		return "", fmt.Errorf("the asset %s has pending registration %s", asset.ID, asset.RegistrationID)
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return "", err
+	}
+	if terms.price < 0 {
+		return "", fmt.Errorf("sale price must not be negative")
+	}
+	if buyer.Money < terms.price {
+		return "", fmt.Errorf("Customer doesn't have enough money on his account")
+	}
+	timeout, err := getConfigInt(ctx, registrationTimeoutConfig, defaultRegistrationTimeout)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	registration := Registration{
+		ID:       ctx.GetStub().GetTxID(),
+		AssetID:  asset.ID,
+		SellerID: terms.seller.ID,
+		BuyerID:  buyer.ID,
+		Price:    terms.price,
+		Status:   RegistrationPending,
+		FiledAt:  now,
+		Deadline: now.Add(time.Duration(timeout) * time.Second),
+	}
+	if terms.dealer != nil {
+		registration.DealerID = terms.dealer.ID
+	}
+	buyer.Money = buyer.Money - terms.price
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return "", err
+	}
+	asset.RegistrationID = registration.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+	err = putRegistration(ctx, &registration)
+	if err != nil {
+		return "", err
+	}
+
+	return registration.ID, nil
+}
+
+// readPendingRegistration returns the registration, checking it still waits for the registry
+func (s *SmartContract) readPendingRegistration(ctx contractapi.TransactionContextInterface, registrationID string) (*Registration, error) {
+	registration, err := s.ReadRegistration(ctx, registrationID)
+	if err != nil {
+		return nil, err
+	}
+	if registration.Status != RegistrationPending {
+		return nil, fmt.Errorf("the registration %s is %s", registrationID, registration.Status)
+	}
+
+	return registration, nil
+}
+
+// closeRegistration returns the held price to the buyer and releases the asset of a registration
+// that did not go through
+func (s *SmartContract) closeRegistration(ctx contractapi.TransactionContextInterface, registration *Registration) error {
+	buyer, err := s.ReadUser(ctx, registration.BuyerID)
+	if err != nil {
+		return err
+	}
+	buyer.Money = buyer.Money + registration.Price
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, registration.AssetID)
+	if err != nil {
+		return err
+	}
+	asset.RegistrationID = ""
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	return putRegistration(ctx, registration)
+}
+
+// putRegistration writes the given registration to the world state
+func putRegistration(ctx contractapi.TransactionContextInterface, registration *Registration) error {
+	registrationKey, err := ctx.GetStub().CreateCompositeKey(registrationObjectType, []string{registration.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	registrationJSON, err := json.Marshal(registration)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(registrationKey, registrationJSON)
+}
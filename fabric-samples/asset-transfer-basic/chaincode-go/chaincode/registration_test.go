@@ -0,0 +1,122 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegulatedTransfer(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer", Money: 10000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "dmv", Roles: []string{chaincode.RoleDMV}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "\x00offer\x00offer1\x00", &chaincode.Offer{ID: "offer1", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 6000, Status: chaincode.OfferOpen})
+	state.put(t, "\x00offer\x00offer2\x00", &chaincode.Offer{ID: "offer2", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 7000, Status: chaincode.OfferOpen})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetRegistrationRequired(transactionContext, true)
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("registration1")
+	err = assetTransfer.AcceptOffer(transactionContext, "offer1")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	require.Equal(t, "registration1", asset.RegistrationID)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(4000), user.Money)
+	err = assetTransfer.AcceptOffer(transactionContext, "offer2")
+	require.EqualError(t, err, "the asset asset1 has pending registration registration1")
+
+	clientIdentity.GetIDReturns("dmv", nil)
+	err = assetTransfer.RejectRegistration(transactionContext, "registration1", "user3", "seller is not the registered keeper")
+	require.NoError(t, err)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(10000), user.Money)
+	state.get(t, "asset1", asset)
+	require.Empty(t, asset.RegistrationID)
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	chaincodeStub.GetTxIDReturns("registration2")
+	err = assetTransfer.AcceptOffer(transactionContext, "offer2")
+	require.NoError(t, err)
+	clientIdentity.GetIDReturns("dmv", nil)
+	err = assetTransfer.ConfirmRegistration(transactionContext, "registration2", "user3")
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Empty(t, asset.RegistrationID)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(3000), user.Money)
+	state.get(t, "user1", user)
+	require.Equal(t, int64(7000), user.Money)
+	registration, err := assetTransfer.ReadRegistration(transactionContext, "registration2")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.RegistrationConfirmed, registration.Status)
+}
+
+func TestExpireRegistration(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleDMV}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.SetRegistrationRequired(transactionContext, true)
+	require.NoError(t, err)
+	err = assetTransfer.SetRegistrationTimeout(transactionContext, 3600)
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("registration1")
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 5000)
+	require.NoError(t, err)
+	err = assetTransfer.ExpireRegistration(transactionContext, "registration1")
+	require.EqualError(t, err, "the registration registration1 is open until 2020-09-13T13:26:40Z")
+
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000 + 3601}, nil)
+	err = assetTransfer.ConfirmRegistration(transactionContext, "registration1", "user3")
+	require.EqualError(t, err, "the registration registration1 expired at 2020-09-13T13:26:40Z")
+	err = assetTransfer.ExpireRegistration(transactionContext, "registration1")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(5000), user.Money)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	require.Empty(t, asset.RegistrationID)
+}
+
+func TestRegulatedTransferRefusesOtherSales(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "buyer", Money: 10000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.SetRegistrationRequired(transactionContext, true))
+
+	clientIdentity.GetIDReturns("seller", nil)
+	err := assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "transfers must be confirmed by the registry, sell the asset asset1 with TransferAsset, AcceptOffer or SettleAgreedSale")
+	require.NoError(t, assetTransfer.ListForSale(transactionContext, "asset1", 5000, ""))
+
+	clientIdentity.GetIDReturns("buyer", nil)
+	err = assetTransfer.BuyListedAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "transfers must be confirmed by the registry, sell the asset asset1 with TransferAsset, AcceptOffer or SettleAgreedSale")
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+}
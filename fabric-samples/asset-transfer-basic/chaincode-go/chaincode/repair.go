@@ -0,0 +1,509 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	repairObjectType = "repair"
+	quoteObjectType  = "repair~quote"
+)
+
+// RequestRepair lets the owner of the asset ask mechanics for quotes on repairing the damage
+// with given ID, or all open damages of the asset when damageID is empty. A damage can only be in
+// one repair job.
+// It returns the ID of the new repair job.
+func (s *SmartContract) RequestRepair(ctx contractapi.TransactionContextInterface, assetID string, damageID string) (string, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.Status == AssetTotaled {
+		return "", fmt.Errorf("the asset %s is totaled, request a salvage restoration instead", assetID)
+	}
+
+	return s.requestRepair(ctx, asset, damageID, false)
+}
+
+// RequestSalvageRestoration lets the owner of a totaled asset ask mechanics for quotes on
+// repairing all its damages. Once the repair is completed the asset is marked salvaged and
+// can be transferred again. It returns the ID of the new repair job.
+func (s *SmartContract) RequestSalvageRestoration(ctx contractapi.TransactionContextInterface, assetID string) (string, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.Status != AssetTotaled {
+		return "", fmt.Errorf("the asset %s is not totaled", assetID)
+	}
+
+	return s.requestRepair(ctx, asset, "", true)
+}
+
+// requestRepair opens a repair job for the damage with given ID, or all open damages of the asset
+func (s *SmartContract) requestRepair(ctx contractapi.TransactionContextInterface, asset *Asset, damageID string, salvage bool) (string, error) {
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return "", err
+	}
+
+	var damages []Damage
+	var damageIDs []string
+	var liability []LiabilityShare
+	for _, damage := range asset.Damages {
+		if damageID == "" || damage.ID == damageID {
+			if damage.RepairJobID != "" {
+				return "", fmt.Errorf("the damage %s is already in repair job %s", damage.ID, damage.RepairJobID)
+			}
+			damages = append(damages, damage)
+			damageIDs = append(damageIDs, damage.ID)
+			if len(damage.Liability) > 0 {
+				liability = damage.Liability
+			}
+		}
+	}
+	if len(damageIDs) == 0 {
+		if damageID != "" {
+			return "", fmt.Errorf("the damage %s is not open on asset %s", damageID, asset.ID)
+		}
+		return "", fmt.Errorf("the asset %s has no damages to repair", asset.ID)
+	}
+	if liability != nil && len(damageIDs) > 1 {
+		return "", fmt.Errorf("damages with shared liability on asset %s must be repaired one at a time", asset.ID)
+	}
+
+	job := RepairJob{
+		ID:        ctx.GetStub().GetTxID(),
+		AssetID:   asset.ID,
+		OwnerID:   owner.ID,
+		DamageIDs: damageIDs,
+		Status:    RepairRequested,
+		Salvage:   salvage,
+		Liability: liability,
+	}
+	err = putRepairJob(ctx, &job)
+	if err != nil {
+		return "", err
+	}
+	for _, damage := range damages {
+		if damage.ID == "" {
+			// damages reported before they had their own keys are only kept on the asset
+			continue
+		}
+		damage.RepairJobID = job.ID
+		err = putDamage(ctx, &damage)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return job.ID, nil
+}
+
+// SubmitRepairQuote lets a mechanic offer to do the requested repair for given price. The mechanic
+// must not be the owner or one of the users liable for the damage, who would pay themselves.
+// It returns the ID of the new quote.
+func (s *SmartContract) SubmitRepairQuote(ctx contractapi.TransactionContextInterface, jobID string, mechanicID string, price int64) (string, error) {
+	if price <= 0 {
+		return "", fmt.Errorf("price must be positive")
+	}
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	err = checkRepairStatus(job, RepairRequested)
+	if err != nil {
+		return "", err
+	}
+	if mechanicID == job.OwnerID || isLiable(job.Liability, mechanicID) {
+		return "", fmt.Errorf("the mechanic %s cannot quote for a repair they pay for", mechanicID)
+	}
+	mechanic, err := s.readMechanic(ctx, mechanicID)
+	if err != nil {
+		return "", err
+	}
+	err = checkUserActive(mechanic)
+	if err != nil {
+		return "", err
+	}
+
+	quote := RepairQuote{
+		ID:         ctx.GetStub().GetTxID(),
+		JobID:      jobID,
+		MechanicID: mechanicID,
+		Price:      price,
+		Status:     QuotePending,
+	}
+	err = putRepairQuote(ctx, &quote)
+	if err != nil {
+		return "", err
+	}
+
+	return quote.ID, nil
+}
+
+// AcceptRepairQuote lets the owner pick the quote of the mechanic who will do the repair.
+func (s *SmartContract) AcceptRepairQuote(ctx contractapi.TransactionContextInterface, jobID string, quoteID string) error {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	err = checkRepairStatus(job, RepairRequested)
+	if err != nil {
+		return err
+	}
+	_, err = s.readRepairOwner(ctx, job)
+	if err != nil {
+		return err
+	}
+	quote, err := s.ReadRepairQuote(ctx, jobID, quoteID)
+	if err != nil {
+		return err
+	}
+
+	quote.Status = QuoteAccepted
+	err = putRepairQuote(ctx, quote)
+	if err != nil {
+		return err
+	}
+	job.Status = RepairAccepted
+	job.QuoteID = quote.ID
+	job.MechanicID = quote.MechanicID
+	job.Price = quote.Price
+	return putRepairJob(ctx, job)
+}
+
+// StartRepair lets the mechanic of the accepted quote start working on the repair job.
+func (s *SmartContract) StartRepair(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	err = checkRepairStatus(job, RepairAccepted)
+	if err != nil {
+		return err
+	}
+	_, err = s.readMechanic(ctx, job.MechanicID)
+	if err != nil {
+		return err
+	}
+
+	job.Status = RepairInProgress
+	return putRepairJob(ctx, job)
+}
+
+// CompleteRepair lets the mechanic working on the repair job mark its damages as repaired.
+func (s *SmartContract) CompleteRepair(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	err = checkRepairStatus(job, RepairInProgress)
+	if err != nil {
+		return err
+	}
+	_, err = s.readMechanic(ctx, job.MechanicID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, job.AssetID)
+	if err != nil {
+		return err
+	}
+	if asset.Status == AssetTotaled && !job.Salvage {
+		return fmt.Errorf("the asset %s is totaled and can only be restored by a salvage repair", asset.ID)
+	}
+
+	remaining := []Damage{}
+	for _, damage := range asset.Damages {
+		if !contains(job.DamageIDs, damage.ID) {
+			remaining = append(remaining, damage)
+			continue
+		}
+		err = markDamageRepaired(ctx, damage, job.MechanicID)
+		if err != nil {
+			return err
+		}
+	}
+	asset.Damages = remaining
+	if job.Salvage {
+		asset.Status = AssetSalvaged
+	}
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	job.Status = RepairCompleted
+	return putRepairJob(ctx, job)
+}
+
+// PayRepair lets the owner pay the mechanic the quoted price for a completed repair job.
+// When the repaired damage has shared liability, each liable user is debited their share, and an
+// approved warranty claim is paid by the warranty issuer.
+// An invoice listing the repaired damages is issued for the payment.
+func (s *SmartContract) PayRepair(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	err = checkRepairStatus(job, RepairCompleted)
+	if err != nil {
+		return err
+	}
+	if job.WarrantyClaim == WarrantyClaimPending {
+		return fmt.Errorf("the warranty claim on repair job %s is pending", jobID)
+	}
+	_, err = s.readRepairOwner(ctx, job)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, job.AssetID)
+	if err != nil {
+		return err
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+
+	liability := job.Liability
+	if len(liability) == 0 {
+		liability = []LiabilityShare{{UserID: job.OwnerID, Share: 10000}}
+	}
+	// a payer may also be the mechanic, e.g. the issuer of a warranty, and reads within a transaction
+	// do not see its own writes, so every account is read and written once
+	accounts := map[string]*User{}
+	var accountIDs []string
+	account := func(userID string) (*User, error) {
+		if user, ok := accounts[userID]; ok {
+			return user, nil
+		}
+		user, err := s.ReadUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		accounts[userID] = user
+		accountIDs = append(accountIDs, userID)
+		return user, nil
+	}
+	remaining := job.Price
+	for i, share := range liability {
+		payer, err := account(share.UserID)
+		if err != nil {
+			return err
+		}
+		amount := job.Price * share.Share / 10000
+		if i == len(liability)-1 {
+			// the last payer covers the rounding remainder
+			amount = remaining
+		}
+		remaining = remaining - amount
+		if payer.Money < amount {
+			return fmt.Errorf("user %s doesn't have enough money on his account", payer.ID)
+		}
+		err = recordSpending(ctx, payer, amount)
+		if err != nil {
+			return err
+		}
+		payer.Money = payer.Money - amount
+	}
+
+	mechanic, err := account(job.MechanicID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(mechanic)
+	if err != nil {
+		return err
+	}
+	mechanic.Money = mechanic.Money + job.Price
+	for _, userID := range accountIDs {
+		err = putUser(ctx, accounts[userID])
+		if err != nil {
+			return err
+		}
+	}
+	err = s.issueInvoice(ctx, job)
+	if err != nil {
+		return err
+	}
+
+	job.Status = RepairPaid
+	return putRepairJob(ctx, job)
+}
+
+// ReadRepairJob returns the repair job stored in the world state with given id.
+func (s *SmartContract) ReadRepairJob(ctx contractapi.TransactionContextInterface, jobID string) (*RepairJob, error) {
+	jobKey, err := ctx.GetStub().CreateCompositeKey(repairObjectType, []string{jobID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	jobJSON, err := ctx.GetStub().GetState(jobKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if jobJSON == nil {
+		return nil, fmt.Errorf("the repair job %s does not exist", jobID)
+	}
+
+	var job RepairJob
+	err = json.Unmarshal(jobJSON, &job)
+	if err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// ReadRepairQuote returns the quote with given ID submitted for the repair job with given ID.
+func (s *SmartContract) ReadRepairQuote(ctx contractapi.TransactionContextInterface, jobID string, quoteID string) (*RepairQuote, error) {
+	quoteKey, err := ctx.GetStub().CreateCompositeKey(quoteObjectType, []string{jobID, quoteID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	quoteJSON, err := ctx.GetStub().GetState(quoteKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if quoteJSON == nil {
+		return nil, fmt.Errorf("the quote %s does not exist for repair job %s", quoteID, jobID)
+	}
+
+	var quote RepairQuote
+	err = json.Unmarshal(quoteJSON, &quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// GetRepairQuotes returns all quotes submitted for the repair job with given ID
+func (s *SmartContract) GetRepairQuotes(ctx contractapi.TransactionContextInterface, jobID string) ([]*RepairQuote, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(quoteObjectType, []string{jobID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var quotes []*RepairQuote
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var quote RepairQuote
+		err = json.Unmarshal(queryResponse.Value, &quote)
+		if err != nil {
+			return nil, err
+		}
+		quotes = append(quotes, &quote)
+	}
+
+	return quotes, nil
+}
+
+// readRepairOwner returns the owner who requested the repair job, provided the owner submitted the transaction
+func (s *SmartContract) readRepairOwner(ctx contractapi.TransactionContextInterface, job *RepairJob) (*User, error) {
+	owner, err := s.ReadUser(ctx, job.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+// readMechanic returns the mechanic with given ID, provided the mechanic submitted the transaction.
+// The mechanic needs either the on-chain mechanic role or a role=mechanic certificate attribute,
+// so repair payments only ever go to users who vouched for themselves as mechanics.
+func (s *SmartContract) readMechanic(ctx contractapi.TransactionContextInterface, mechanicID string) (*User, error) {
+	mechanic, err := s.ReadUser(ctx, mechanicID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, mechanic)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRole(mechanic, RoleMechanic) && !hasAttr(ctx, attr(roleAttribute, RoleMechanic)) {
+		return nil, fmt.Errorf("User %s is not a mechanic", mechanicID)
+	}
+
+	return mechanic, nil
+}
+
+// isLiable returns true when the user pays a share of the repair
+func isLiable(liability []LiabilityShare, userID string) bool {
+	for _, share := range liability {
+		if share.UserID == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkRepairStatus returns an error unless the repair job is in given status
+func checkRepairStatus(job *RepairJob, status string) error {
+	if job.Status != status {
+		return fmt.Errorf("the repair job %s is %s, expected %s", job.ID, job.Status, status)
+	}
+
+	return nil
+}
+
+// putRepairJob writes the given repair job to the world state and emits an event named
+// after its status, e.g. RepairJobAccepted
+func putRepairJob(ctx contractapi.TransactionContextInterface, job *RepairJob) error {
+	err := writeRepairJob(ctx, job)
+	if err != nil {
+		return err
+	}
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent("RepairJob"+job.Status, jobJSON)
+}
+
+// writeRepairJob writes the given repair job to the world state without announcing a transition
+func writeRepairJob(ctx contractapi.TransactionContextInterface, job *RepairJob) error {
+	jobKey, err := ctx.GetStub().CreateCompositeKey(repairObjectType, []string{job.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(jobKey, jobJSON)
+}
+
+// putRepairQuote writes the given quote under its repair~quote composite key
+func putRepairQuote(ctx contractapi.TransactionContextInterface, quote *RepairQuote) error {
+	quoteKey, err := ctx.GetStub().CreateCompositeKey(quoteObjectType, []string{quote.JobID, quote.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	quoteJSON, err := json.Marshal(quote)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(quoteKey, quoteJSON)
+}
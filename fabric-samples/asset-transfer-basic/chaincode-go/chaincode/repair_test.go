@@ -0,0 +1,283 @@
+package chaincode_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairJob(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute role not found"))
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "owner2", Status: chaincode.UserActive, Roles: []string{chaincode.RoleOwner}})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "mechanic", Status: chaincode.UserActive, Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{{ID: "damage1", AssetID: "asset1", Description: "tyre", Cost: 400, Status: chaincode.DamageOpen}}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.NoError(t, err)
+	require.Equal(t, "job1", jobID)
+	eventName, _ := chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "RepairJobRequested", eventName)
+
+	err = assetTransfer.StartRepair(transactionContext, jobID)
+	require.EqualError(t, err, "the repair job job1 is Requested, expected Accepted")
+
+	clientIdentity.GetIDReturns("owner2", nil)
+	_, err = assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user2", 300)
+	require.EqualError(t, err, "User user2 is not a mechanic")
+
+	clientIdentity.GetIDReturns("mechanic", nil)
+	chaincodeStub.GetTxIDReturns("quote1")
+	quoteID, err := assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user3", 350)
+	require.NoError(t, err)
+
+	err = assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID)
+	require.NoError(t, err)
+	quote, err := assetTransfer.ReadRepairQuote(transactionContext, jobID, quoteID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.QuoteAccepted, quote.Status)
+
+	err = assetTransfer.StartRepair(transactionContext, jobID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user3")
+
+	clientIdentity.GetIDReturns("mechanic", nil)
+	require.NoError(t, assetTransfer.StartRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.CompleteRepair(transactionContext, jobID))
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Empty(t, asset.Damages)
+
+	err = assetTransfer.PayRepair(transactionContext, jobID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.PayRepair(transactionContext, jobID)
+	require.NoError(t, err)
+	eventName, _ = chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "RepairJobPaid", eventName)
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(650), owner.Money)
+	mechanic := &chaincode.User{}
+	state.get(t, "user3", mechanic)
+	require.Equal(t, int64(350), mechanic.Money)
+
+	job, err := assetTransfer.ReadRepairJob(transactionContext, jobID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.RepairPaid, job.Status)
+
+	err = assetTransfer.PayRepair(transactionContext, jobID)
+	require.EqualError(t, err, "the repair job job1 is Paid, expected Completed")
+}
+
+func TestRepairSingleDamage(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTxIDReturns("damage1")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "tyre", 400))
+	chaincodeStub.GetTxIDReturns("damage2")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "mirror", 100))
+
+	_, err := assetTransfer.RequestRepair(transactionContext, "asset1", "damage3")
+	require.EqualError(t, err, "the damage damage3 is not open on asset asset1")
+
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestRepair(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("job2")
+	_, err = assetTransfer.RequestRepair(transactionContext, "asset1", "damage1")
+	require.EqualError(t, err, "the damage damage1 is already in repair job job1")
+	_, err = assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.EqualError(t, err, "the damage damage1 is already in repair job job1")
+	chaincodeStub.GetTxIDReturns("quote1")
+	quoteID, err := assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user3", 450)
+	require.NoError(t, err)
+	require.NoError(t, assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID))
+	require.NoError(t, assetTransfer.StartRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.CompleteRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.PayRepair(transactionContext, jobID))
+
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(550), owner.Money)
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, asset.Damages, 1)
+	require.Equal(t, "damage2", asset.Damages[0].ID)
+	damage, err := assetTransfer.ReadDamage(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.DamageRepaired, damage.Status)
+	require.Equal(t, "user3", damage.RepairedBy)
+	require.Equal(t, "job1", damage.RepairJobID)
+}
+
+func TestSalvageRestoration(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 5000, Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Status: chaincode.UserActive})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTxIDReturns("damage1")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "engine", 3500))
+
+	err := assetTransfer.TransferAsset(transactionContext, "asset1", "user2", true, 1000)
+	require.EqualError(t, err, "the asset asset1 is totaled and cannot be transferred")
+	_, err = assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.EqualError(t, err, "the asset asset1 is totaled, request a salvage restoration instead")
+
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestSalvageRestoration(transactionContext, "asset1")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("quote1")
+	quoteID, err := assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user3", 3200)
+	require.NoError(t, err)
+	require.NoError(t, assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID))
+	require.NoError(t, assetTransfer.StartRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.CompleteRepair(transactionContext, jobID))
+
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, chaincode.AssetSalvaged, asset.Status)
+	require.Empty(t, asset.Damages)
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 1000)
+	require.NoError(t, err)
+}
+
+func TestRepairByCertifiedMechanic(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user4", &chaincode.User{ID: "user4", Identity: "mechanic", Status: chaincode.UserActive, Roles: []string{chaincode.RoleOwner}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{{ID: "damage1", Cost: 400}}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("mechanic", nil)
+	clientIdentity.AssertAttributeValueStub = func(name string, value string) error {
+		if name == "role" && value == chaincode.RoleMechanic {
+			return nil
+		}
+		return fmt.Errorf("attribute %s is not %s", name, value)
+	}
+	_, err = assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user4", 350)
+	require.NoError(t, err)
+
+	clientIdentity.AssertAttributeValueStub = nil
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute role not found"))
+	_, err = assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user4", 350)
+	require.EqualError(t, err, "User user4 is not a mechanic")
+}
+
+func TestRepairSharedDamage(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 1000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateSharedAssetDamage(transactionContext, "asset1", "bumper", 600, "user1", "user1", 3000)
+	require.EqualError(t, err, "the damage cost must be split between two different users")
+
+	chaincodeStub.GetTxIDReturns("damage1")
+	require.NoError(t, assetTransfer.CreateSharedAssetDamage(transactionContext, "asset1", "bumper", 600, "user1", "user2", 3000))
+	chaincodeStub.GetTxIDReturns("damage2")
+	require.NoError(t, assetTransfer.CreateAssetDamage(transactionContext, "asset1", "mirror", 100))
+
+	_, err = assetTransfer.RequestRepair(transactionContext, "asset1", "")
+	require.EqualError(t, err, "damages with shared liability on asset asset1 must be repaired one at a time")
+
+	chaincodeStub.GetTxIDReturns("job1")
+	jobID, err := assetTransfer.RequestRepair(transactionContext, "asset1", "damage1")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("quote1")
+	quoteID, err := assetTransfer.SubmitRepairQuote(transactionContext, jobID, "user3", 500)
+	require.NoError(t, err)
+	require.NoError(t, assetTransfer.AcceptRepairQuote(transactionContext, jobID, quoteID))
+	require.NoError(t, assetTransfer.StartRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.CompleteRepair(transactionContext, jobID))
+	require.NoError(t, assetTransfer.PayRepair(transactionContext, jobID))
+
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(850), owner.Money)
+	other := &chaincode.User{}
+	state.get(t, "user2", other)
+	require.Equal(t, int64(650), other.Money)
+	mechanic := &chaincode.User{}
+	state.get(t, "user3", mechanic)
+	require.Equal(t, int64(500), mechanic.Money)
+}
+
+func TestPayRepairToPayingMechanic(t *testing.T) {
+	state := worldState{}
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Status: chaincode.UserActive, Roles: []string{chaincode.RoleOwner, chaincode.RoleMechanic}})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 1000, Status: chaincode.UserActive, Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage1\x00", &chaincode.Damage{ID: "damage1", AssetID: "asset1", Description: "tyre", Cost: 400, Status: chaincode.DamageRepaired})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", AssetID: "asset1", OwnerID: "user1", DamageIDs: []string{"damage1"}, Status: chaincode.RepairRequested})
+
+	transactionContext, _ := prepStrictMocks(state)
+	assetTransfer := chaincode.SmartContract{}
+	_, err := assetTransfer.SubmitRepairQuote(transactionContext, "job1", "user1", 900)
+	require.EqualError(t, err, "the mechanic user1 cannot quote for a repair they pay for")
+
+	// jobs quoted before mechanics were checked, and warranty issuers repairing what they cover
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", AssetID: "asset1", OwnerID: "user1", DamageIDs: []string{"damage1"}, Status: chaincode.RepairCompleted, MechanicID: "user1", Price: 900})
+	state.put(t, "\x00repair\x00job2\x00", &chaincode.RepairJob{ID: "job2", AssetID: "asset1", OwnerID: "user1", DamageIDs: []string{"damage1"}, Status: chaincode.RepairCompleted, MechanicID: "user2", Price: 900, Liability: []chaincode.LiabilityShare{{UserID: "user2", Share: 10000}}})
+	for _, jobID := range []string{"job1", "job2"} {
+		transactionContext, _ = prepStrictMocks(state)
+		err = assetTransfer.PayRepair(transactionContext, jobID)
+		require.NoError(t, err)
+	}
+	for _, userID := range []string{"user1", "user2"} {
+		user := &chaincode.User{}
+		state.get(t, userID, user)
+		require.Equal(t, int64(1000), user.Money)
+	}
+}
+
+func TestPayRepairToFrozenMechanic(t *testing.T) {
+	state := worldState{}
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Status: chaincode.UserActive})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Status: chaincode.UserFrozen, Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+	state.put(t, "\x00asset~damage\x00asset1\x00damage1\x00", &chaincode.Damage{ID: "damage1", AssetID: "asset1", Description: "tyre", Cost: 400, Status: chaincode.DamageRepaired})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", AssetID: "asset1", OwnerID: "user1", DamageIDs: []string{"damage1"}, Status: chaincode.RepairCompleted, MechanicID: "user3", Price: 400})
+
+	transactionContext, _ := prepStrictMocks(state)
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.PayRepair(transactionContext, "job1")
+	require.EqualError(t, err, "USER_FROZEN: the user user3 is frozen")
+	owner := &chaincode.User{}
+	state.get(t, "user1", owner)
+	require.Equal(t, int64(1000), owner.Money)
+}
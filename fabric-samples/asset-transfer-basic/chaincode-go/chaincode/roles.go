@@ -0,0 +1,68 @@
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+var knownRoles = []string{RoleOwner, RoleMechanic, RoleDealer, RoleInsurer, RoleAssessor, RoleRegulator, RoleInspector, RoleDMV, RolePolice, RoleEmissionsStation, RoleManufacturer}
+
+// GrantRole adds the role to user with given ID. Only admins may grant roles.
+func (s *SmartContract) GrantRole(ctx contractapi.TransactionContextInterface, userID string, role string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if !contains(knownRoles, role) {
+		return fmt.Errorf("unknown role %s", role)
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if hasRole(user, role) {
+		return fmt.Errorf("the user %s already has role %s", userID, role)
+	}
+
+	user.Roles = append(user.Roles, role)
+	return putUser(ctx, user)
+}
+
+// RevokeRole removes the role from user with given ID. Only admins may revoke roles.
+func (s *SmartContract) RevokeRole(ctx contractapi.TransactionContextInterface, userID string, role string) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !hasRole(user, role) {
+		return fmt.Errorf("the user %s does not have role %s", userID, role)
+	}
+
+	roles := []string{}
+	for _, r := range user.Roles {
+		if r != role {
+			roles = append(roles, r)
+		}
+	}
+	user.Roles = roles
+	return putUser(ctx, user)
+}
+
+// hasRole returns true when the user has been granted given role
+func hasRole(user *User, role string) bool {
+	return contains(user.Roles, role)
+}
+
+// requireRole returns an error when the user has not been granted given role
+func requireRole(user *User, role string) error {
+	if !hasRole(user, role) {
+		return fmt.Errorf("the user %s does not have role %s", user.ID, role)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package chaincode_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrantRole(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Roles: []string{chaincode.RoleOwner}})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.GrantRole(transactionContext, "user1", chaincode.RoleMechanic)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, []string{chaincode.RoleOwner, chaincode.RoleMechanic}, user.Roles)
+
+	err = assetTransfer.GrantRole(transactionContext, "user1", chaincode.RoleMechanic)
+	require.EqualError(t, err, "the user user1 already has role mechanic")
+
+	err = assetTransfer.GrantRole(transactionContext, "user1", "pilot")
+	require.EqualError(t, err, "unknown role pilot")
+
+	transactionContext.GetClientIdentity().(*mocks.ClientIdentity).AssertAttributeValueReturns(fmt.Errorf("attribute admin not found"))
+	err = assetTransfer.GrantRole(transactionContext, "user1", chaincode.RoleDealer)
+	require.EqualError(t, err, "submitting client does not have attribute admin=true")
+}
+
+func TestRevokeRole(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Roles: []string{chaincode.RoleOwner, chaincode.RoleMechanic}})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.RevokeRole(transactionContext, "user1", chaincode.RoleMechanic)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, []string{chaincode.RoleOwner}, user.Roles)
+
+	err = assetTransfer.RevokeRole(transactionContext, "user1", chaincode.RoleMechanic)
+	require.EqualError(t, err, "the user user1 does not have role mechanic")
+}
@@ -0,0 +1,332 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const transferObjectType = "transfer"
+
+// GetTransfers returns all recorded ownership changes of asset with given ID
+func (s *SmartContract) GetTransfers(ctx contractapi.TransactionContextInterface, assetID string) ([]*TransferRecord, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(transferObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var transfers []*TransferRecord
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var transfer TransferRecord
+		err = json.Unmarshal(queryResponse.Value, &transfer)
+		if err != nil {
+			return nil, err
+		}
+		transfers = append(transfers, &transfer)
+	}
+
+	return transfers, nil
+}
+
+// GiftAsset hands asset with given id over to newOwner without any payment.
+// The transfer is still recorded with a zero price.
+func (s *SmartContract) GiftAsset(ctx contractapi.TransactionContextInterface, id string, newOwner string) error {
+	asset, err := s.ReadAsset(ctx, id)
+	if err != nil {
+		return err
+	}
+	if asset.OwnerID == newOwner {
+		return fmt.Errorf("New owner is same as current")
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	recipient, err := s.ReadUser(ctx, newOwner)
+	if err != nil {
+		return err
+	}
+
+	return executeSale(ctx, asset, owner, recipient, 0)
+}
+
+// saleTerms describes a sale to be settled by settleSale
+type saleTerms struct {
+	asset   *Asset
+	seller  *User
+	buyer   *User
+	dealer  *User // optional broker earning a commission from the seller's proceeds
+	price   int64
+	upfront int64 // part of the price paid to the seller now
+	// confirmed by the registry, see settleOrRegister; other sales are refused while transfers are regulated
+	registered bool
+}
+
+const dealerCommissionRateConfig = "dealerCommissionRate"
+
+// SetDealerCommissionRate sets the share of a brokered sale paid to the dealer, in basis points.
+// Only admins may change the rate.
+func (s *SmartContract) SetDealerCommissionRate(ctx contractapi.TransactionContextInterface, basisPoints int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if basisPoints < 0 || basisPoints > 10000 {
+		return fmt.Errorf("commission rate must be between 0 and 10000 basis points")
+	}
+
+	return putConfigInt(ctx, dealerCommissionRateConfig, basisPoints)
+}
+
+// executeSale moves price from buyer to seller and hands the asset over to the buyer.
+func executeSale(ctx contractapi.TransactionContextInterface, asset *Asset, seller *User, buyer *User, price int64) error {
+	return settleSale(ctx, &saleTerms{asset: asset, seller: seller, buyer: buyer, price: price, upfront: price})
+}
+
+// settleSale pays the seller, the treasury and the dealer and hands the asset over to the buyer.
+// Every way of selling an asset settles through here, so sale rules are enforced in one place.
+func settleSale(ctx contractapi.TransactionContextInterface, terms *saleTerms) error {
+	asset, seller, buyer, dealer := terms.asset, terms.seller, terms.buyer, terms.dealer
+	err := checkSale(ctx, terms)
+	if err != nil {
+		return err
+	}
+	err = transferInsurance(ctx, asset, buyer.ID)
+	if err != nil {
+		return err
+	}
+	err = recordSpending(ctx, buyer, terms.upfront)
+	if err != nil {
+		return err
+	}
+
+	tax, err := collectTransferTax(ctx, terms.upfront)
+	if err != nil {
+		return err
+	}
+	commission := int64(0)
+	if dealer != nil {
+		rate, err := getConfigInt(ctx, dealerCommissionRateConfig, 0)
+		if err != nil {
+			return err
+		}
+		commission = terms.upfront * rate / 10000
+		err = creditUser(ctx, dealer, commission, "commission")
+		if err != nil {
+			return err
+		}
+		if !dealer.HotAccount {
+			err = putUser(ctx, dealer)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	buyer.Money = buyer.Money - terms.upfront
+	err = splitProceeds(ctx, asset, seller, buyer, terms.upfront-tax-commission)
+	if err != nil {
+		return err
+	}
+	// hot accounts are credited through balance deltas and left alone
+	if !seller.HotAccount {
+		err = putUser(ctx, seller)
+		if err != nil {
+			return err
+		}
+	}
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	err = handOverAsset(ctx, asset, seller, buyer)
+	if err != nil {
+		return err
+	}
+
+	record := TransferRecord{
+		TxID:           ctx.GetStub().GetTxID(),
+		AssetID:        asset.ID,
+		SellerID:       seller.ID,
+		BuyerID:        buyer.ID,
+		Price:          terms.price,
+		AppraisedValue: asset.AppraisedValue,
+		Tax:            tax,
+		Commission:     commission,
+		Mileage:        asset.Mileage,
+	}
+	if dealer != nil {
+		record.DealerID = dealer.ID
+	}
+	err = recordSalePrice(ctx, asset, terms.price)
+	if err != nil {
+		return err
+	}
+	return putTransferRecord(ctx, &record)
+}
+
+// checkSale returns an error unless the sale may be settled. It writes nothing, so callers that
+// must not fail once they took money, like auctions, can check the sale before settling it.
+func checkSale(ctx contractapi.TransactionContextInterface, terms *saleTerms) error {
+	asset, seller, buyer, dealer := terms.asset, terms.seller, terms.buyer, terms.dealer
+	if terms.price < 0 {
+		return fmt.Errorf("sale price must not be negative")
+	}
+	if terms.upfront < 0 || terms.upfront > terms.price {
+		return fmt.Errorf("upfront payment must be between 0 and the sale price")
+	}
+	if !terms.registered {
+		regulated, err := getConfigInt(ctx, registrationRequiredConfig, 0)
+		if err != nil {
+			return err
+		}
+		if regulated != 0 {
+			return fmt.Errorf("transfers must be confirmed by the registry, sell the asset %s with TransferAsset, AcceptOffer or SettleAgreedSale", asset.ID)
+		}
+	}
+	err := checkAssetSale(ctx, asset, seller, buyer)
+	if err != nil {
+		return err
+	}
+	if dealer != nil {
+		err = checkDealerNotParty(dealer.ID, asset, buyer.ID)
+		if err != nil {
+			return err
+		}
+	}
+	err = checkUserActive(seller)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(buyer)
+	if err != nil {
+		return err
+	}
+	err = checkKYC(ctx, buyer, terms.price)
+	if err != nil {
+		return err
+	}
+	if buyer.Money < terms.upfront {
+		return fmt.Errorf("Customer doesn't have enough money on his account")
+	}
+
+	return checkSpendingLimit(ctx, buyer, terms.upfront)
+}
+
+// checkAssetSale returns an error unless the asset may be sold by the seller to the buyer
+func checkAssetSale(ctx contractapi.TransactionContextInterface, asset *Asset, seller *User, buyer *User) error {
+	if asset.OwnerID != seller.ID {
+		return fmt.Errorf("the asset %s is not owned by user %s", asset.ID, seller.ID)
+	}
+	if asset.Encumbered {
+		return fmt.Errorf("the asset %s is encumbered and cannot be transferred", asset.ID)
+	}
+	if asset.ExportID != "" {
+		return fmt.Errorf("the asset %s is being exported", asset.ID)
+	}
+	if asset.RegistrationID != "" {
+		return fmt.Errorf("the asset %s has pending registration %s", asset.ID, asset.RegistrationID)
+	}
+	if asset.AuctionID != "" {
+		return fmt.Errorf("the asset %s is up for auction %s", asset.ID, asset.AuctionID)
+	}
+	if asset.LienID != "" && asset.LienTransferApproval != buyer.ID {
+		return fmt.Errorf("the asset %s has a lien held by user %s who must approve the transfer", asset.ID, asset.LienholderID)
+	}
+	err := checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	if asset.Status == AssetTotaled {
+		return fmt.Errorf("the asset %s is totaled and cannot be transferred", asset.ID)
+	}
+	err = checkNotTokenized(asset)
+	if err != nil {
+		return err
+	}
+	err = checkCoOwnerApproval(ctx, asset, buyer.ID)
+	if err != nil {
+		return err
+	}
+
+	return checkInspection(ctx, asset)
+}
+
+// handOverAsset makes the buyer the sole owner of the asset and stores it
+func handOverAsset(ctx contractapi.TransactionContextInterface, asset *Asset, seller *User, buyer *User) error {
+	asset.OwnerID = buyer.ID
+	asset.CoOwners = nil
+	asset.SaleApprovals, asset.SaleApprovalBuyer = nil, ""
+	asset.Delegate = ""
+	asset.LienTransferApproval = ""
+	// the seller's note is theirs, not the car's
+	err := deleteAssetNote(ctx, asset)
+	if err != nil {
+		return err
+	}
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	// neither organization can change the asset alone after a sale between them
+	return setKeyEndorsement(ctx, asset.ID, seller.MSPID, buyer.MSPID)
+}
+
+// readDealer returns the user brokering the sale of the asset, or nil when dealerID is empty. The
+// dealer must not take part in the sale otherwise, see checkDealerNotParty.
+func (s *SmartContract) readDealer(ctx contractapi.TransactionContextInterface, dealerID string, asset *Asset, partyIDs ...string) (*User, error) {
+	if dealerID == "" {
+		return nil, nil
+	}
+	err := checkDealerNotParty(dealerID, asset, partyIDs...)
+	if err != nil {
+		return nil, err
+	}
+	dealer, err := s.ReadUser(ctx, dealerID)
+	if err != nil {
+		return nil, err
+	}
+	err = requireRole(dealer, RoleDealer)
+	if err != nil {
+		return nil, err
+	}
+
+	return dealer, nil
+}
+
+// checkDealerNotParty returns an error when the dealer owns or co-owns the asset or is one of the
+// other parties to its sale. The dealer's account is settled apart from theirs, and since reads
+// within a transaction do not see its own writes one of the updates would be lost.
+func checkDealerNotParty(dealerID string, asset *Asset, partyIDs ...string) error {
+	if dealerID == asset.OwnerID || ownershipShare(asset.CoOwners, dealerID) > 0 || contains(partyIDs, dealerID) {
+		return fmt.Errorf("the dealer %s cannot be a party to the sale of asset %s", dealerID, asset.ID)
+	}
+
+	return nil
+}
+
+// putTransferRecord writes the transfer record under the asset~transfer composite key
+func putTransferRecord(ctx contractapi.TransactionContextInterface, transfer *TransferRecord) error {
+	transferKey, err := ctx.GetStub().CreateCompositeKey(transferObjectType, []string{transfer.AssetID, transfer.TxID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	transferJSON, err := json.Marshal(transfer)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(transferKey, transferJSON)
+}
@@ -0,0 +1,68 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-chaincode-go/pkg/statebased"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftAsset(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100, Identity: "giver"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 0, Identity: "recipient"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("recipient", nil)
+	err := assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+
+	clientIdentity.GetIDReturns("giver", nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	giver := &chaincode.User{}
+	state.get(t, "user1", giver)
+	require.Equal(t, int64(100), giver.Money)
+	transfer := &chaincode.TransferRecord{}
+	state.get(t, "\x00transfer\x00asset1\x00tx1\x00", transfer)
+	require.Equal(t, int64(0), transfer.Price)
+}
+
+func TestSaleBetweenOrgsSetsEndorsementPolicy(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	enrollClients(clientIdentity, map[string]string{"giver": "Org1MSP", "recipient": "Org2MSP", "friend": "Org2MSP"})
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "giver", MSPID: "Org1MSP"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "recipient", MSPID: "Org2MSP"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "friend", MSPID: "Org2MSP"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("giver", nil)
+	err := assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+	require.Equal(t, 1, chaincodeStub.SetStateValidationParameterCallCount())
+	key, policy := chaincodeStub.SetStateValidationParameterArgsForCall(0)
+	require.Equal(t, "asset1", key)
+	endorsementPolicy, err := statebased.NewStateEP(policy)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"Org1MSP", "Org2MSP"}, endorsementPolicy.ListOrgs())
+
+	clientIdentity.GetIDReturns("recipient", nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+	_, policy = chaincodeStub.SetStateValidationParameterArgsForCall(1)
+	endorsementPolicy, err = statebased.NewStateEP(policy)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Org2MSP"}, endorsementPolicy.ListOrgs())
+}
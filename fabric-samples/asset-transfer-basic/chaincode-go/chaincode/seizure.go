@@ -0,0 +1,218 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const seizureObjectType = "asset~seizure"
+
+// SeizeAsset lets a regulator take the asset into custody. Ownership moves to the custodian's
+// account and the original owner is kept on the seizure so the asset can be returned by
+// ReleaseSeizedAsset. A seized asset cannot be sold, listed or paid for repairs.
+// It returns the ID of the new seizure.
+func (s *SmartContract) SeizeAsset(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string, custodianID string, reason string) (string, error) {
+	if reason == "" {
+		return "", fmt.Errorf("reason must not be empty")
+	}
+	regulator, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return "", err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.SeizureID != "" {
+		return "", fmt.Errorf("the asset %s is already seized", assetID)
+	}
+	if asset.OwnerID == custodianID {
+		return "", fmt.Errorf("the custodian must not be the owner of the asset")
+	}
+	custodian, err := s.ReadUser(ctx, custodianID)
+	if err != nil {
+		return "", err
+	}
+	err = checkUserActive(custodian)
+	if err != nil {
+		return "", err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	seizure := Seizure{
+		ID:          ctx.GetStub().GetTxID(),
+		AssetID:     assetID,
+		OwnerID:     asset.OwnerID,
+		CustodianID: custodian.ID,
+		RegulatorID: regulator.ID,
+		Reason:      reason,
+		SeizedAt:    now,
+		Status:      SeizureActive,
+	}
+	err = putSeizure(ctx, &seizure)
+	if err != nil {
+		return "", err
+	}
+
+	asset.OwnerID = custodian.ID
+	asset.Delegate = ""
+	asset.SeizureID = seizure.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+	err = setSeizureEvent(ctx, "AssetSeized", &seizure)
+	if err != nil {
+		return "", err
+	}
+
+	return seizure.ID, nil
+}
+
+// ReleaseSeizedAsset lets a regulator carry out the court order releasing a seized asset, which
+// returns it to the owner it was seized from
+func (s *SmartContract) ReleaseSeizedAsset(ctx contractapi.TransactionContextInterface, assetID string, regulatorID string, courtOrder string) error {
+	if courtOrder == "" {
+		return fmt.Errorf("court order must not be empty")
+	}
+	_, err := s.readRegulator(ctx, regulatorID)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.SeizureID == "" {
+		return fmt.Errorf("the asset %s is not seized", assetID)
+	}
+	seizure, err := s.ReadSeizure(ctx, assetID, asset.SeizureID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, seizure.OwnerID)
+	if err != nil {
+		return err
+	}
+	if owner.Status == UserClosed {
+		return fmt.Errorf("the user %s is closed", owner.ID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+
+	seizure.CourtOrder = courtOrder
+	seizure.ReleasedAt = now
+	seizure.Status = SeizureReleased
+	err = putSeizure(ctx, seizure)
+	if err != nil {
+		return err
+	}
+
+	asset.OwnerID = owner.ID
+	asset.SeizureID = ""
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	return setSeizureEvent(ctx, "AssetReleased", seizure)
+}
+
+// ReadSeizure returns the seizure with given ID of the asset with given ID
+func (s *SmartContract) ReadSeizure(ctx contractapi.TransactionContextInterface, assetID string, seizureID string) (*Seizure, error) {
+	seizureKey, err := ctx.GetStub().CreateCompositeKey(seizureObjectType, []string{assetID, seizureID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	seizureJSON, err := ctx.GetStub().GetState(seizureKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if seizureJSON == nil {
+		return nil, fmt.Errorf("the seizure %s does not exist on asset %s", seizureID, assetID)
+	}
+
+	var seizure Seizure
+	err = json.Unmarshal(seizureJSON, &seizure)
+	if err != nil {
+		return nil, err
+	}
+
+	return &seizure, nil
+}
+
+// GetSeizures returns every seizure of the asset with given ID, released ones included
+func (s *SmartContract) GetSeizures(ctx contractapi.TransactionContextInterface, assetID string) ([]*Seizure, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(seizureObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var seizures []*Seizure
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var seizure Seizure
+		err = json.Unmarshal(queryResponse.Value, &seizure)
+		if err != nil {
+			return nil, err
+		}
+		seizures = append(seizures, &seizure)
+	}
+
+	return seizures, nil
+}
+
+// readRegulator returns the user with given ID after checking that the submitting client acts for
+// them and that they are a regulator
+func (s *SmartContract) readRegulator(ctx contractapi.TransactionContextInterface, regulatorID string) (*User, error) {
+	regulator, err := s.ReadUser(ctx, regulatorID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, regulator)
+	if err != nil {
+		return nil, err
+	}
+	err = requireRole(regulator, RoleRegulator)
+	if err != nil {
+		return nil, err
+	}
+
+	return regulator, nil
+}
+
+// putSeizure writes the seizure under its asset~seizure composite key
+func putSeizure(ctx contractapi.TransactionContextInterface, seizure *Seizure) error {
+	seizureKey, err := ctx.GetStub().CreateCompositeKey(seizureObjectType, []string{seizure.AssetID, seizure.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	seizureJSON, err := json.Marshal(seizure)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(seizureKey, seizureJSON)
+}
+
+// setSeizureEvent emits an event carrying the seizure
+func setSeizureEvent(ctx contractapi.TransactionContextInterface, name string, seizure *Seizure) error {
+	eventJSON, err := json.Marshal(seizure)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventJSON)
+}
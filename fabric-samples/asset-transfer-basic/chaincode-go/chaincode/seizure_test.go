@@ -0,0 +1,64 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeizeAndReleaseAsset(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	chaincodeStub.GetTxIDReturns("seizure1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "owner", Status: chaincode.UserActive})
+	state.put(t, "user7", &chaincode.User{ID: "user7", Identity: "regulator", Status: chaincode.UserActive, Roles: []string{chaincode.RoleRegulator}})
+	state.put(t, "user8", &chaincode.User{ID: "user8", Status: chaincode.UserActive})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", AppraisedValue: 3000, Delegate: "user2"})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("owner", nil)
+	_, err := assetTransfer.SeizeAsset(transactionContext, "asset1", "user7", "user8", "smuggling")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user7")
+
+	clientIdentity.GetIDReturns("regulator", nil)
+	seizureID, err := assetTransfer.SeizeAsset(transactionContext, "asset1", "user7", "user8", "smuggling")
+	require.NoError(t, err)
+	name, _ := chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "AssetSeized", name)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user8", asset.OwnerID)
+	require.Equal(t, seizureID, asset.SeizureID)
+	require.Empty(t, asset.Delegate)
+	_, err = assetTransfer.SeizeAsset(transactionContext, "asset1", "user7", "user8", "smuggling")
+	require.EqualError(t, err, "the asset asset1 is already seized")
+
+	state.put(t, "user8", &chaincode.User{ID: "user8", Identity: "custody", Status: chaincode.UserActive})
+	clientIdentity.GetIDReturns("custody", nil)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "ASSET_SEIZED: the asset asset1 is seized")
+
+	clientIdentity.GetIDReturns("regulator", nil)
+
+	err = assetTransfer.ReleaseSeizedAsset(transactionContext, "asset1", "user7", "")
+	require.EqualError(t, err, "court order must not be empty")
+	err = assetTransfer.ReleaseSeizedAsset(transactionContext, "asset1", "user7", "K-123/20")
+	require.NoError(t, err)
+	name, _ = chaincodeStub.SetEventArgsForCall(chaincodeStub.SetEventCallCount() - 1)
+	require.Equal(t, "AssetReleased", name)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	require.Empty(t, asset.SeizureID)
+
+	seizure, err := assetTransfer.ReadSeizure(transactionContext, "asset1", seizureID)
+	require.NoError(t, err)
+	require.Equal(t, chaincode.SeizureReleased, seizure.Status)
+	require.Equal(t, "user1", seizure.OwnerID)
+	require.Equal(t, "K-123/20", seizure.CourtOrder)
+	err = assetTransfer.ReleaseSeizedAsset(transactionContext, "asset1", "user7", "K-123/20")
+	require.EqualError(t, err, "the asset asset1 is not seized")
+}
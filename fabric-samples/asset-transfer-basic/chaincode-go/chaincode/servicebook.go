@@ -0,0 +1,96 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const serviceBookObjectType = "asset~serviceBook"
+
+// AddServiceBookEntry lets a mechanic append the work they performed on the asset to its service
+// book. The odometer reading of the entry is recorded as the mileage of the asset. A reading lower
+// than the current one flags the asset as tampered instead and no entry is added. It returns the ID
+// of the entry, empty when none was added.
+func (s *SmartContract) AddServiceBookEntry(ctx contractapi.TransactionContextInterface, assetID string, mechanicID string, mileage int64, workPerformed string) (string, error) {
+	if workPerformed == "" {
+		return "", fmt.Errorf("work performed must not be empty")
+	}
+	_, err := s.readMechanic(ctx, mechanicID)
+	if err != nil {
+		return "", err
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	recorded, err := recordMileage(ctx, asset, mileage)
+	if err != nil {
+		return "", err
+	}
+	if !recorded {
+		return "", putAsset(ctx, asset)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	entry := ServiceBookEntry{
+		ID:            ctx.GetStub().GetTxID(),
+		AssetID:       assetID,
+		Date:          now,
+		Mileage:       mileage,
+		WorkPerformed: workPerformed,
+		MechanicID:    mechanicID,
+	}
+	entryKey, err := ctx.GetStub().CreateCompositeKey(serviceBookObjectType, []string{assetID, entry.ID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	err = ctx.GetStub().PutState(entryKey, entryJSON)
+	if err != nil {
+		return "", err
+	}
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return entry.ID, nil
+}
+
+// GetServiceBook returns the service book of the asset, ordered by mileage
+func (s *SmartContract) GetServiceBook(ctx contractapi.TransactionContextInterface, assetID string) ([]*ServiceBookEntry, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(serviceBookObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var entries []*ServiceBookEntry
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var entry ServiceBookEntry
+		err = json.Unmarshal(queryResponse.Value, &entry)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Mileage < entries[j].Mileage
+	})
+
+	return entries, nil
+}
@@ -0,0 +1,42 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceBook(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "user3", &chaincode.User{ID: "user3"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Mileage: 10000})
+	assetTransfer := chaincode.SmartContract{}
+
+	entryID, err := assetTransfer.AddServiceBookEntry(transactionContext, "asset1", "user2", 9000, "oil change")
+	require.NoError(t, err)
+	require.Empty(t, entryID)
+	chaincodeStub.GetTxIDReturns("tx2")
+	_, err = assetTransfer.AddServiceBookEntry(transactionContext, "asset1", "user2", 15000, "oil change")
+	require.NoError(t, err)
+	chaincodeStub.GetTxIDReturns("tx1")
+	_, err = assetTransfer.AddServiceBookEntry(transactionContext, "asset1", "user2", 30000, "timing belt")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, int64(30000), asset.Mileage)
+	require.True(t, asset.MileageTampered)
+
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+	entries, err := assetTransfer.GetServiceBook(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "oil change", entries[0].WorkPerformed)
+	require.Equal(t, "timing belt", entries[1].WorkPerformed)
+	require.Equal(t, "user2", entries[1].MechanicID)
+}
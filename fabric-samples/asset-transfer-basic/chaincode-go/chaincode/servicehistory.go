@@ -0,0 +1,250 @@
+package chaincode
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	serviceObjectType         = "service"
+	serviceHashObjectType     = "asset~serviceHash"
+	serviceRecordTransientKey = "service_record"
+)
+
+// AddServiceRecord lets the owner add a service record of the asset. The ServiceRecord is passed as
+// JSON in the service_record transient field and stored in the private data collection of the
+// owner's organization. It returns the ID of the record.
+func (s *SmartContract) AddServiceRecord(ctx contractapi.TransactionContextInterface, assetID string) (string, error) {
+	_, err := s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return "", fmt.Errorf("error getting transient: %v", err)
+	}
+	recordJSON, ok := transientMap[serviceRecordTransientKey]
+	if !ok {
+		return "", fmt.Errorf("the service record must be passed in the %s transient field", serviceRecordTransientKey)
+	}
+	var record ServiceRecord
+	err = json.Unmarshal(recordJSON, &record)
+	if err != nil {
+		return "", fmt.Errorf("failed to unmarshal service record: %v", err)
+	}
+	if record.AssetID != assetID {
+		return "", fmt.Errorf("the service record is for asset %s, not %s", record.AssetID, assetID)
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	recordHash := ServiceRecordHash{
+		AssetID:    assetID,
+		RecordID:   ctx.GetStub().GetTxID(),
+		Collection: collection,
+		Hash:       hashServiceRecord(recordJSON),
+	}
+	recordKey, err := serviceRecordKey(ctx, assetID, recordHash.RecordID)
+	if err != nil {
+		return "", err
+	}
+	err = ctx.GetStub().PutPrivateData(collection, recordKey, recordJSON)
+	if err != nil {
+		return "", fmt.Errorf("failed to put service record to private data collection %s: %v", collection, err)
+	}
+	hashKey, err := ctx.GetStub().CreateCompositeKey(serviceHashObjectType, []string{assetID, recordHash.RecordID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+	hashJSON, err := json.Marshal(recordHash)
+	if err != nil {
+		return "", err
+	}
+	err = ctx.GetStub().PutState(hashKey, hashJSON)
+	if err != nil {
+		return "", err
+	}
+
+	return recordHash.RecordID, nil
+}
+
+// GetServiceHistory returns the service records of the asset available to the submitting client's
+// organization: its own records and copies shared with it by the owner. Every record is checked
+// against its public hash.
+func (s *SmartContract) GetServiceHistory(ctx contractapi.TransactionContextInterface, assetID string) ([]*ServiceRecord, error) {
+	hashes, err := getServiceRecordHashes(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*ServiceRecord
+	for _, recordHash := range hashes {
+		recordJSON, err := readServiceRecord(ctx, collection, recordHash)
+		if err != nil {
+			return nil, err
+		}
+		var record ServiceRecord
+		err = json.Unmarshal(recordJSON, &record)
+		if err != nil {
+			return nil, err
+		}
+		record.ID = recordHash.RecordID
+		records = append(records, &record)
+	}
+
+	return records, nil
+}
+
+// ShareServiceHistory lets the owner copy the service records of the asset into the implicit
+// collection of the organization with given MSP ID, typically a prospective buyer's, until
+// UnshareServiceHistory removes the copies again.
+func (s *SmartContract) ShareServiceHistory(ctx contractapi.TransactionContextInterface, assetID string, mspID string) error {
+	_, err := s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	hashes, err := getServiceRecordHashes(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	collection, err := clientOrgCollection(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, recordHash := range hashes {
+		recordJSON, err := readServiceRecord(ctx, collection, recordHash)
+		if err != nil {
+			return err
+		}
+		recordKey, err := serviceRecordKey(ctx, assetID, recordHash.RecordID)
+		if err != nil {
+			return err
+		}
+		err = ctx.GetStub().PutPrivateData(orgCollection(mspID), recordKey, recordJSON)
+		if err != nil {
+			return fmt.Errorf("failed to put service record to private data collection %s: %v", orgCollection(mspID), err)
+		}
+	}
+
+	return nil
+}
+
+// UnshareServiceHistory lets the owner remove the service records of the asset shared with the
+// organization with given MSP ID.
+func (s *SmartContract) UnshareServiceHistory(ctx contractapi.TransactionContextInterface, assetID string, mspID string) error {
+	_, err := s.verifyAssetOwner(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	hashes, err := getServiceRecordHashes(ctx, assetID)
+	if err != nil {
+		return err
+	}
+
+	for _, recordHash := range hashes {
+		if recordHash.Collection == orgCollection(mspID) {
+			continue
+		}
+		recordKey, err := serviceRecordKey(ctx, assetID, recordHash.RecordID)
+		if err != nil {
+			return err
+		}
+		err = ctx.GetStub().DelPrivateData(orgCollection(mspID), recordKey)
+		if err != nil {
+			return fmt.Errorf("failed to delete from private data collection %s: %v", orgCollection(mspID), err)
+		}
+	}
+
+	return nil
+}
+
+// verifyAssetOwner returns the owner of the asset after checking that the submitting client acts for them
+func (s *SmartContract) verifyAssetOwner(ctx contractapi.TransactionContextInterface, assetID string) (*User, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return owner, nil
+}
+
+// readServiceRecord returns the record JSON from the collection after checking it against its public hash
+func readServiceRecord(ctx contractapi.TransactionContextInterface, collection string, recordHash *ServiceRecordHash) ([]byte, error) {
+	recordKey, err := serviceRecordKey(ctx, recordHash.AssetID, recordHash.RecordID)
+	if err != nil {
+		return nil, err
+	}
+	recordJSON, err := ctx.GetStub().GetPrivateData(collection, recordKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from private data collection %s: %v", collection, err)
+	}
+	if recordJSON == nil {
+		return nil, fmt.Errorf("the service record %s of asset %s is not shared with collection %s", recordHash.RecordID, recordHash.AssetID, collection)
+	}
+	if hashServiceRecord(recordJSON) != recordHash.Hash {
+		return nil, fmt.Errorf("the service record %s does not match its hash", recordHash.RecordID)
+	}
+
+	return recordJSON, nil
+}
+
+// getServiceRecordHashes returns the public hashes of all service records of the asset
+func getServiceRecordHashes(ctx contractapi.TransactionContextInterface, assetID string) ([]*ServiceRecordHash, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(serviceHashObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var hashes []*ServiceRecordHash
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var recordHash ServiceRecordHash
+		err = json.Unmarshal(queryResponse.Value, &recordHash)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, &recordHash)
+	}
+
+	return hashes, nil
+}
+
+// serviceRecordKey returns the private data key of the service record
+func serviceRecordKey(ctx contractapi.TransactionContextInterface, assetID string, recordID string) (string, error) {
+	key, err := ctx.GetStub().CreateCompositeKey(serviceObjectType, []string{assetID, recordID})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return key, nil
+}
+
+// hashServiceRecord returns the hex encoded SHA-256 of the service record JSON
+func hashServiceRecord(recordJSON []byte) string {
+	hash := sha256.Sum256(recordJSON)
+	return hex.EncodeToString(hash[:])
+}
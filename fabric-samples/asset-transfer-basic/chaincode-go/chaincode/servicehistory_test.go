@@ -0,0 +1,60 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareServiceHistory(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("owner", nil)
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner", MSPID: "Org1MSP"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	chaincodeStub.GetTxIDReturns("service1")
+	chaincodeStub.GetTransientReturns(map[string][]byte{"service_record": []byte(`{"assetID":"asset1","mileage":120000,"description":"timing belt replaced","cost":45000,"salt":"beef"}`)}, nil)
+	recordID, err := assetTransfer.AddServiceRecord(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "service1", recordID)
+	require.Contains(t, state, "_implicit_org_Org1MSP/\x00service\x00asset1\x00service1\x00")
+
+	records, err := assetTransfer.GetServiceHistory(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, "service1", records[0].ID)
+	require.Equal(t, "timing belt replaced", records[0].Description)
+
+	// a prospective buyer in another organization sees the history only while it is shared
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	_, err = assetTransfer.GetServiceHistory(transactionContext, "asset1")
+	require.EqualError(t, err, "the service record service1 of asset asset1 is not shared with collection _implicit_org_Org2MSP")
+
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	err = assetTransfer.ShareServiceHistory(transactionContext, "asset1", "Org2MSP")
+	require.NoError(t, err)
+	clientIdentity.GetMSPIDReturns("Org2MSP", nil)
+	records, err = assetTransfer.GetServiceHistory(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+
+	state["_implicit_org_Org2MSP/\x00service\x00asset1\x00service1\x00"] = []byte(`{"assetID":"asset1","mileage":20000}`)
+	_, err = assetTransfer.GetServiceHistory(transactionContext, "asset1")
+	require.EqualError(t, err, "the service record service1 does not match its hash")
+
+	clientIdentity.GetMSPIDReturns("Org1MSP", nil)
+	err = assetTransfer.UnshareServiceHistory(transactionContext, "asset1", "Org2MSP")
+	require.NoError(t, err)
+	require.NotContains(t, state, "_implicit_org_Org2MSP/\x00service\x00asset1\x00service1\x00")
+
+	clientIdentity.GetIDReturns("stranger", nil)
+	err = assetTransfer.ShareServiceHistory(transactionContext, "asset1", "Org2MSP")
+	require.Error(t, err)
+}
@@ -0,0 +1,361 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const shareHoldingObjectType = "shareToken"
+
+// TokenizeAsset divides the asset into totalShares fungible share tokens, all held by the owner
+// until they transfer them with TransferShareTokens. The owner stays the registered owner of the car,
+// which cannot be sold whole until a buy-back consolidates the shares again.
+func (s *SmartContract) TokenizeAsset(ctx contractapi.TransactionContextInterface, assetID string, totalShares int64) error {
+	if totalShares < 2 {
+		return fmt.Errorf("an asset must be divided into at least 2 shares")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if asset.ShareSupply > 0 {
+		return fmt.Errorf("the asset %s is already divided into %d shares", assetID, asset.ShareSupply)
+	}
+	if len(asset.CoOwners) > 0 {
+		return fmt.Errorf("the asset %s has co-owners and cannot be tokenized", assetID)
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+
+	asset.ShareSupply = totalShares
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	return putShareHolding(ctx, &ShareHolding{AssetID: assetID, UserID: owner.ID, Shares: totalShares})
+}
+
+// TransferShareTokens moves amount share tokens of the asset from one user to another
+func (s *SmartContract) TransferShareTokens(ctx contractapi.TransactionContextInterface, assetID string, fromUserID string, toUserID string, amount int64) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive")
+	}
+	if fromUserID == toUserID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	asset, err := s.readTokenizedAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	err = checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	// the held funds pay for the shares outstanding when the buy-back started
+	if asset.BuyBackUserID != "" {
+		return fmt.Errorf("the shares of asset %s are being bought back by user %s", assetID, asset.BuyBackUserID)
+	}
+	from, err := s.ReadUser(ctx, fromUserID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, from)
+	if err != nil {
+		return err
+	}
+	to, err := s.ReadUser(ctx, toUserID)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(to)
+	if err != nil {
+		return err
+	}
+
+	return moveShares(ctx, assetID, fromUserID, toUserID, amount)
+}
+
+// GetShareHoldings returns the holdings of all users holding share tokens of the asset
+func (s *SmartContract) GetShareHoldings(ctx contractapi.TransactionContextInterface, assetID string) ([]*ShareHolding, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(shareHoldingObjectType, []string{assetID})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	var holdings []*ShareHolding
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var holding ShareHolding
+		err = json.Unmarshal(queryResponse.Value, &holding)
+		if err != nil {
+			return nil, err
+		}
+		holdings = append(holdings, &holding)
+	}
+
+	return holdings, nil
+}
+
+// StartShareBuyBack lets a shareholder offer to buy all outstanding shares of the asset at
+// pricePerShare. The price of every share they do not hold is taken from their account and held
+// until the other shareholders tender their shares with TenderShares.
+func (s *SmartContract) StartShareBuyBack(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, pricePerShare int64) error {
+	if pricePerShare < 0 {
+		return fmt.Errorf("price must not be negative")
+	}
+	asset, err := s.readTokenizedAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.BuyBackUserID != "" {
+		return fmt.Errorf("user %s is already buying back the shares of asset %s", asset.BuyBackUserID, assetID)
+	}
+	buyer, err := s.ReadUser(ctx, buyerID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(buyer)
+	if err != nil {
+		return err
+	}
+	holding, err := readShareHolding(ctx, assetID, buyerID)
+	if err != nil {
+		return err
+	}
+	if holding.Shares == 0 {
+		return fmt.Errorf("user %s holds no shares of asset %s", buyerID, assetID)
+	}
+
+	escrow := (asset.ShareSupply - holding.Shares) * pricePerShare
+	if buyer.Money < escrow {
+		return fmt.Errorf("Customer doesn't have enough money on his account")
+	}
+	buyer.Money = buyer.Money - escrow
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	asset.BuyBackUserID = buyerID
+	asset.BuyBackPrice = pricePerShare
+	asset.BuyBackEscrow = escrow
+	return putAsset(ctx, asset)
+}
+
+// TenderShares sells all share tokens the holder has of the asset to the shareholder buying them
+// back, paid from the held funds. Once the buyer holds every share the asset is consolidated: the
+// tokens are retired and the buyer becomes its sole owner.
+func (s *SmartContract) TenderShares(ctx contractapi.TransactionContextInterface, assetID string, holderID string) error {
+	asset, err := s.readTokenizedAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.BuyBackUserID == "" {
+		return fmt.Errorf("no one is buying back the shares of asset %s", assetID)
+	}
+	if asset.BuyBackUserID == holderID {
+		return fmt.Errorf("New owner is same as current")
+	}
+	holder, err := s.ReadUser(ctx, holderID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, holder)
+	if err != nil {
+		return err
+	}
+	holding, err := readShareHolding(ctx, assetID, holderID)
+	if err != nil {
+		return err
+	}
+	if holding.Shares == 0 {
+		return fmt.Errorf("user %s holds no shares of asset %s", holderID, assetID)
+	}
+	buyerHolding, err := readShareHolding(ctx, assetID, asset.BuyBackUserID)
+	if err != nil {
+		return err
+	}
+
+	payment := holding.Shares * asset.BuyBackPrice
+	holder.Money = holder.Money + payment
+	err = putUser(ctx, holder)
+	if err != nil {
+		return err
+	}
+	asset.BuyBackEscrow = asset.BuyBackEscrow - payment
+	err = deleteShareHolding(ctx, holding)
+	if err != nil {
+		return err
+	}
+	buyerHolding.Shares = buyerHolding.Shares + holding.Shares
+	if buyerHolding.Shares < asset.ShareSupply {
+		err = putShareHolding(ctx, buyerHolding)
+		if err != nil {
+			return err
+		}
+		return putAsset(ctx, asset)
+	}
+
+	// the buyer holds every share, the car is theirs alone again
+	err = deleteShareHolding(ctx, buyerHolding)
+	if err != nil {
+		return err
+	}
+	seller := asset.OwnerID
+	asset.OwnerID = asset.BuyBackUserID
+	asset.ShareSupply = 0
+	asset.BuyBackUserID, asset.BuyBackPrice, asset.BuyBackEscrow = "", 0, 0
+	asset.Delegate = ""
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	if seller == asset.OwnerID {
+		return nil
+	}
+	return putTransferRecord(ctx, &TransferRecord{TxID: ctx.GetStub().GetTxID(), AssetID: assetID, SellerID: seller, BuyerID: asset.OwnerID})
+}
+
+// CancelShareBuyBack lets the buying shareholder stop the buy-back, returning the funds still held.
+// The shares already tendered stay theirs.
+func (s *SmartContract) CancelShareBuyBack(ctx contractapi.TransactionContextInterface, assetID string) error {
+	asset, err := s.readTokenizedAsset(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	if asset.BuyBackUserID == "" {
+		return fmt.Errorf("no one is buying back the shares of asset %s", assetID)
+	}
+	buyer, err := s.ReadUser(ctx, asset.BuyBackUserID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, buyer)
+	if err != nil {
+		return err
+	}
+
+	buyer.Money = buyer.Money + asset.BuyBackEscrow
+	err = putUser(ctx, buyer)
+	if err != nil {
+		return err
+	}
+	asset.BuyBackUserID, asset.BuyBackPrice, asset.BuyBackEscrow = "", 0, 0
+	return putAsset(ctx, asset)
+}
+
+// checkNotTokenized returns an error when the asset is divided into share tokens
+func checkNotTokenized(asset *Asset) error {
+	if asset.ShareSupply > 0 {
+		return fmt.Errorf("the asset %s is divided into %d shares, they must be bought back first", asset.ID, asset.ShareSupply)
+	}
+
+	return nil
+}
+
+// readTokenizedAsset returns the asset, checking it is divided into share tokens
+func (s *SmartContract) readTokenizedAsset(ctx contractapi.TransactionContextInterface, assetID string) (*Asset, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.ShareSupply == 0 {
+		return nil, fmt.Errorf("the asset %s is not divided into shares", assetID)
+	}
+
+	return asset, nil
+}
+
+// moveShares moves amount share tokens of the asset between two different users
+func moveShares(ctx contractapi.TransactionContextInterface, assetID string, fromUserID string, toUserID string, amount int64) error {
+	from, err := readShareHolding(ctx, assetID, fromUserID)
+	if err != nil {
+		return err
+	}
+	if from.Shares < amount {
+		return fmt.Errorf("user %s holds %d shares of asset %s", fromUserID, from.Shares, assetID)
+	}
+	to, err := readShareHolding(ctx, assetID, toUserID)
+	if err != nil {
+		return err
+	}
+
+	from.Shares = from.Shares - amount
+	to.Shares = to.Shares + amount
+	if from.Shares == 0 {
+		err = deleteShareHolding(ctx, from)
+	} else {
+		err = putShareHolding(ctx, from)
+	}
+	if err != nil {
+		return err
+	}
+	return putShareHolding(ctx, to)
+}
+
+// readShareHolding returns the shares of the asset the user holds, an empty holding when they hold none
+func readShareHolding(ctx contractapi.TransactionContextInterface, assetID string, userID string) (*ShareHolding, error) {
+	holdingKey, err := ctx.GetStub().CreateCompositeKey(shareHoldingObjectType, []string{assetID, userID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	holdingJSON, err := ctx.GetStub().GetState(holdingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	holding := ShareHolding{AssetID: assetID, UserID: userID}
+	if holdingJSON == nil {
+		return &holding, nil
+	}
+	err = json.Unmarshal(holdingJSON, &holding)
+	if err != nil {
+		return nil, err
+	}
+
+	return &holding, nil
+}
+
+// putShareHolding writes the given share holding to the world state
+func putShareHolding(ctx contractapi.TransactionContextInterface, holding *ShareHolding) error {
+	holdingKey, err := ctx.GetStub().CreateCompositeKey(shareHoldingObjectType, []string{holding.AssetID, holding.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	holdingJSON, err := json.Marshal(holding)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(holdingKey, holdingJSON)
+}
+
+// deleteShareHolding removes the given share holding from the world state
+func deleteShareHolding(ctx contractapi.TransactionContextInterface, holding *ShareHolding) error {
+	holdingKey, err := ctx.GetStub().CreateCompositeKey(shareHoldingObjectType, []string{holding.AssetID, holding.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return ctx.GetStub().DelState(holdingKey)
+}
@@ -0,0 +1,61 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareTokens(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "investor1", Money: 5000})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "investor2"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.TokenizeAsset(transactionContext, "asset1", 100)
+	require.NoError(t, err)
+	err = assetTransfer.TransferShareTokens(transactionContext, "asset1", "user1", "user2", 60)
+	require.NoError(t, err)
+	err = assetTransfer.TransferShareTokens(transactionContext, "asset1", "user1", "user3", 50)
+	require.EqualError(t, err, "user user1 holds 40 shares of asset asset1")
+	err = assetTransfer.TransferShareTokens(transactionContext, "asset1", "user1", "user3", 40)
+	require.NoError(t, err)
+	holdings, err := assetTransfer.GetShareHoldings(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, []*chaincode.ShareHolding{{AssetID: "asset1", UserID: "user2", Shares: 60}, {AssetID: "asset1", UserID: "user3", Shares: 40}}, holdings)
+
+	state.put(t, "user4", &chaincode.User{ID: "user4", Identity: "owner"})
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user4")
+	require.EqualError(t, err, "the asset asset1 is divided into 100 shares, they must be bought back first")
+
+	clientIdentity.GetIDReturns("investor1", nil)
+	err = assetTransfer.StartShareBuyBack(transactionContext, "asset1", "user2", 100)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user2", user)
+	require.Equal(t, int64(1000), user.Money)
+
+	clientIdentity.GetIDReturns("investor2", nil)
+	err = assetTransfer.TransferShareTokens(transactionContext, "asset1", "user3", "user1", 10)
+	require.EqualError(t, err, "the shares of asset asset1 are being bought back by user user2")
+	err = assetTransfer.TenderShares(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(4000), user.Money)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	require.Equal(t, int64(0), asset.ShareSupply)
+	require.Empty(t, asset.BuyBackUserID)
+	holdings, err = assetTransfer.GetShareHoldings(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Empty(t, holdings)
+}
@@ -3,6 +3,8 @@ package chaincode
 import (
 	"encoding/json"
 	"fmt"
+	"net/mail"
+	"strings"
 
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 )
@@ -12,52 +14,55 @@ type SmartContract struct {
 	contractapi.Contract
 }
 
-// Damage describes car damages
-type Damage struct {
-	// ID          string  `json:"ID"`
-	Description string  `json:"description"`
-	Cost        float64 `json:"cost"`
-}
-
-// User describes user details (car owner, repairman, ...)
-type User struct {
-	ID       string  `json:"ID"`
-	Name     string  `json:"name"`
-	Lastname string  `json:"lastname"`
-	Email    string  `json:"email"`
-	Money    float64 `json:"money"`
-}
-
-// Asset describes basic details of what makes up a simple asset (car)
-type Asset struct {
-	ID             string   `json:"ID"`
-	Brand          string   `json:"brand"`
-	Model          string   `json:"model"`
-	Year           int      `json:"year"`
-	Color          string   `json:"color"`
-	OwnerID        string   `json:"owner"`
-	Damages        []Damage `json:"damages"`
-	AppraisedValue float64  `json:"appraisedValue`
-}
-
-// InitLedger adds a base set of assets to the ledger
+// InitLedger adds a base set of assets to the ledger. Only admins may initialize the ledger, and
+// only once, so live data is never overwritten with the base set.
 func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	initialized, err := getConfigInt(ctx, ledgerInitializedConfig, 0)
+	if err != nil {
+		return err
+	}
+	if initialized != 0 {
+		return fmt.Errorf("the ledger is already initialized")
+	}
+
 	users := []User{
-		{ID: "user1", Name: "Marko", Lastname: "Markovic", Email: "marko.markovic@email.com", Money: 10000.00},
-		{ID: "user2", Name: "Jovan", Lastname: "Jovanovic", Email: "jovan.jovanovic@email.com", Money: 5000.00},
-		{ID: "user3", Name: "Lazar", Lastname: "Lazarevic", Email: "lazar.lazarevic@email.com", Money: 3750.00},
+		{ID: "user1", Name: "Marko", Lastname: "Markovic", Email: "marko.markovic@email.com", Money: 1000000, Status: UserActive, Roles: []string{RoleOwner}},
+		{ID: "user2", Name: "Jovan", Lastname: "Jovanovic", Email: "jovan.jovanovic@email.com", Money: 500000, Status: UserActive, Roles: []string{RoleOwner}},
+		{ID: "user3", Name: "Lazar", Lastname: "Lazarevic", Email: "lazar.lazarevic@email.com", Money: 375000, Status: UserActive, Roles: []string{RoleOwner, RoleMechanic}},
 	}
 	assets := []Asset{
-		{ID: "asset1", Brand: "fiat", Model: "500L", Year: 2018, Color: "black", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 7000.00},
-		{ID: "asset2", Brand: "audi", Model: "A6", Year: 2016, Color: "blue", OwnerID: "user2", Damages: []Damage{}, AppraisedValue: 5000.00},
-		{ID: "asset3", Brand: "bmw", Model: "500L", Year: 2017, Color: "red", OwnerID: "user2", Damages: []Damage{}, AppraisedValue: 12000.00},
-		{ID: "asset4", Brand: "ford", Model: "500L", Year: 2013, Color: "gray", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 7350.00},
-		{ID: "asset5", Brand: "toyota", Model: "500L", Year: 2017, Color: "black", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 4600.00},
-		{ID: "asset6", Brand: "opel", Model: "astra", Year: 2018, Color: "black", OwnerID: "user3", Damages: []Damage{}, AppraisedValue: 6300.00},
+		{ID: "asset1", VIN: "ZFA19900100123456", Brand: "fiat", Model: "500L", Year: 2018, Color: "black", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 700000},
+		{ID: "asset2", VIN: "WAUZZZ4G0GN012345", Brand: "audi", Model: "A6", Year: 2016, Color: "blue", OwnerID: "user2", Damages: []Damage{}, AppraisedValue: 500000},
+		{ID: "asset3", VIN: "WBA8E1C50HK123456", Brand: "bmw", Model: "500L", Year: 2017, Color: "red", OwnerID: "user2", Damages: []Damage{}, AppraisedValue: 1200000},
+		{ID: "asset4", VIN: "1FADP3F27DL123456", Brand: "ford", Model: "500L", Year: 2013, Color: "gray", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 735000},
+		{ID: "asset5", VIN: "JTDKN3DUXH5123456", Brand: "toyota", Model: "500L", Year: 2017, Color: "black", OwnerID: "user1", Damages: []Damage{}, AppraisedValue: 460000},
+		{ID: "asset6", VIN: "W0L0AHL4608123456", Brand: "opel", Model: "astra", Year: 2018, Color: "black", OwnerID: "user3", Damages: []Damage{}, AppraisedValue: 630000},
+	}
+
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
+	var audit Audit
+	err = stampCreated(ctx, &audit)
+	if err != nil {
+		return err
 	}
 
 	for _, user := range users {
-		userJSON, err := json.Marshal(user)
+		user.Identity = clientID
+		user.MSPID = mspID
+		user.Audit = audit
+		user.Version = 1
+		userJSON, err := encodeUser(ctx, &user)
 		if err != nil {
 			return err
 		}
@@ -66,35 +71,39 @@ func (s *SmartContract) InitLedger(ctx contractapi.TransactionContextInterface)
 		if err != nil {
 			return fmt.Errorf("failed to put user to world state. %v", err)
 		}
-	}
-
-	for _, asset := range assets {
-		assetJSON, err := json.Marshal(asset)
+		err = putEmailIndex(ctx, user.Email, user.ID)
 		if err != nil {
 			return err
 		}
+	}
 
-		err = ctx.GetStub().PutState(asset.ID, assetJSON)
+	// assets are stored like any new asset, so they get their doc type and audit trail
+	for _, asset := range assets {
+		err = writeNewAsset(ctx, &asset)
 		if err != nil {
-			return fmt.Errorf("failed to put asset to world state. %v", err)
+			return err
 		}
 	}
+	err = putConfigInt(ctx, ledgerInitializedConfig, 1)
+	if err != nil {
+		return err
+	}
 
-	return nil
+	return markMoneyInCents(ctx)
 }
 
-// CreateAsset issues a new asset to the world state with given details.
-func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, id string, brand string, model string, year int, color string, owner string, appraisedValue float64) error {
-	exists, err := s.AssetExists(ctx, id)
+// CreateAsset registers a used car in the world state with given details. The ID must start with
+// "asset", and the VIN must be valid and not registered to another asset. Only dealers and the owner of the car may register it; new cars
+// are issued by their manufacturer with MintVehicle.
+func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface, id string, vin string, brand string, model string, year int, color string, owner string, appraisedValue int64) error {
+	err := s.checkCanRegister(ctx, id, owner)
 	if err != nil {
 		return err
 	}
-	if exists {
-		return fmt.Errorf("the asset %s already exists", id)
-	}
 
 	asset := Asset{
 		ID:             id,
+		VIN:            vin,
 		Brand:          brand,
 		Model:          model,
 		Year:           year,
@@ -103,38 +112,450 @@ func (s *SmartContract) CreateAsset(ctx contractapi.TransactionContextInterface,
 		AppraisedValue: appraisedValue,
 		Damages:        []Damage{},
 	}
-	assetJSON, err := json.Marshal(asset)
+	return s.issueAsset(ctx, &asset)
+}
+
+// checkCanRegister returns an error unless the submitting client is a dealer or acts for the owner
+func (s *SmartContract) checkCanRegister(ctx contractapi.TransactionContextInterface, id string, owner string) error {
+	if hasAttr(ctx, attr(roleAttribute, RoleDealer)) {
+		return nil
+	}
+	ownerUser, err := s.ReadUser(ctx, owner)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, ownerUser)
+	if err != nil {
+		return fmt.Errorf("only dealers and the owner can register asset %s", id)
+	}
+
+	return nil
+}
+
+// issueAsset writes a new asset to the world state after checking its ID and VIN are not taken
+func (s *SmartContract) issueAsset(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	err := s.checkNewAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+
+	return writeNewAsset(ctx, asset)
+}
+
+// checkNewAsset checks the ID and the VIN of a new asset are valid and not taken and upper-cases the VIN
+func (s *SmartContract) checkNewAsset(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	if !strings.HasPrefix(asset.ID, "asset") {
+		return fmt.Errorf("asset ID %s must start with \"asset\"", asset.ID)
+	}
+	exists, err := s.AssetExists(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return fmt.Errorf("the asset %s already exists", asset.ID)
+	}
+	err = validateVIN(asset.VIN)
 	if err != nil {
 		return err
 	}
+	asset.VIN = strings.ToUpper(asset.VIN)
 
-	return ctx.GetStub().PutState(id, assetJSON)
+	return checkVINAvailable(ctx, asset.VIN)
+}
+
+// writeNewAsset stores an asset that passed checkNewAsset together with its VIN index entry
+func writeNewAsset(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	err := stampCreated(ctx, &asset.Audit)
+	if err != nil {
+		return err
+	}
+	err = putVINIndex(ctx, asset.VIN, asset.ID)
+	if err != nil {
+		return err
+	}
+
+	return putAsset(ctx, asset)
 }
 
 // CreateUser issues a new user to the world state with given details.
-func (s *SmartContract) CreateUser(ctx contractapi.TransactionContextInterface, id string, name string, lastname string, email string, money float64) error {
-	exists, err := s.AssetExists(ctx, id)
+func (s *SmartContract) CreateUser(ctx contractapi.TransactionContextInterface, id string, name string, lastname string, email string, initialBalance int64) error {
+	if !strings.HasPrefix(id, "user") {
+		return fmt.Errorf("user ID %s must start with \"user\"", id)
+	}
+	err := validateContactDetails(name, lastname, email)
+	if err != nil {
+		return err
+	}
+	if initialBalance < 0 {
+		return fmt.Errorf("initial balance must not be negative")
+	}
+
+	exists, err := s.UserExists(ctx, id)
 	if err != nil {
 		return err
 	}
 	if exists {
 		return fmt.Errorf("the user %s already exists", id)
 	}
+	err = checkEmailAvailable(ctx, email)
+	if err != nil {
+		return err
+	}
+	clientID, err := submittingClientID(ctx)
+	if err != nil {
+		return err
+	}
+	mspID, err := clientMSPID(ctx)
+	if err != nil {
+		return err
+	}
 
 	user := User{
 		ID:       id,
 		Name:     name,
 		Lastname: lastname,
 		Email:    email,
-		Money:    money,
+		Money:    initialBalance,
+		Status:   UserActive,
+		Roles:    []string{RoleOwner},
+		Identity: clientID,
+		MSPID:    mspID,
+	}
+	err = stampCreated(ctx, &user.Audit)
+	if err != nil {
+		return err
+	}
+	err = putEmailIndex(ctx, email, id)
+	if err != nil {
+		return err
+	}
+
+	return putUser(ctx, &user)
+}
+
+// UpdateUser changes contact details of the user with given id. The submitting client must be bound to the user.
+func (s *SmartContract) UpdateUser(ctx contractapi.TransactionContextInterface, id string, name string, lastname string, email string) error {
+	err := validateContactDetails(name, lastname, email)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.ReadUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, user)
+	if err != nil {
+		return err
+	}
+	if user.PIIHash != "" || user.PIIPurged {
+		return fmt.Errorf("the personal data of user %s is private or purged", id)
+	}
+
+	if !strings.EqualFold(user.Email, email) {
+		err = checkEmailAvailable(ctx, email)
+		if err != nil {
+			return err
+		}
+		err = deleteEmailIndex(ctx, user.Email)
+		if err != nil {
+			return err
+		}
+		err = putEmailIndex(ctx, email, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	user.Name = name
+	user.Lastname = lastname
+	user.Email = email
+	return putUser(ctx, user)
+}
+
+// CloseUserAccount pays out the remaining balance of the user to payoutUserID and marks the user closed.
+// Pending credits of a hot account are consolidated and paid out too. Users that still own or co-own
+// assets, hold share tokens or funds in auctions, or take part in open offers, running leases, loans
+// or escrows cannot be closed.
+func (s *SmartContract) CloseUserAccount(ctx contractapi.TransactionContextInterface, userID string, payoutUserID string) error {
+	if userID == payoutUserID {
+		return fmt.Errorf("cannot pay out the balance to the closed account")
+	}
+	user, err := s.ReadUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, user)
+	if err != nil {
+		return err
+	}
+	err = checkUserActive(user)
+	if err != nil {
+		return err
+	}
+	err = s.checkUserUninvolved(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// credits of a hot account not consolidated yet are paid out as well
+	_, err = consolidateDeltas(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	if user.Money > 0 {
+		payout, err := s.ReadUser(ctx, payoutUserID)
+		if err != nil {
+			return err
+		}
+		err = checkUserActive(payout)
+		if err != nil {
+			return err
+		}
+		err = creditUser(ctx, payout, user.Money, "closure")
+		if err != nil {
+			return err
+		}
+		user.Money = 0
+		if !payout.HotAccount {
+			err = putUser(ctx, payout)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	user.Status = UserClosed
+	return putUser(ctx, user)
+}
+
+// checkUserUninvolved returns an error when the user still owns or co-owns an asset, holds share
+// tokens, takes part in an open offer, running lease, loan or escrow, or has funds held by an auction
+func (s *SmartContract) checkUserUninvolved(ctx contractapi.TransactionContextInterface, userID string) error {
+	assets, err := s.GetAllAssets(ctx)
+	if err != nil {
+		return err
+	}
+	owned, coOwned := 0, 0
+	for _, asset := range assets {
+		if asset.OwnerID == userID {
+			owned++
+		} else if ownershipShare(asset.CoOwners, userID) > 0 {
+			coOwned++
+		}
+	}
+	if owned > 0 {
+		return fmt.Errorf("the user %s still owns %d assets", userID, owned)
+	}
+	if coOwned > 0 {
+		return fmt.Errorf("the user %s still co-owns %d assets", userID, coOwned)
+	}
+	offers, err := s.GetOpenOffers(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(offers) > 0 {
+		return fmt.Errorf("the user %s still has %d open offers", userID, len(offers))
+	}
+	leases, err := s.GetActiveLeases(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(leases) > 0 {
+		return fmt.Errorf("the user %s still has %d running leases", userID, len(leases))
+	}
+
+	holdings, err := countRecords(ctx, shareHoldingObjectType, func(value []byte) (bool, error) {
+		var holding ShareHolding
+		err := json.Unmarshal(value, &holding)
+		return holding.UserID == userID && holding.Shares > 0, err
+	})
+	if err != nil {
+		return err
+	}
+	if holdings > 0 {
+		return fmt.Errorf("the user %s still holds share tokens of %d assets", userID, holdings)
+	}
+	loans, err := countRecords(ctx, loanObjectType, func(value []byte) (bool, error) {
+		var loan Loan
+		err := json.Unmarshal(value, &loan)
+		return loan.Status != LoanRepaid && (loan.LenderID == userID || loan.BorrowerID == userID), err
+	})
+	if err != nil {
+		return err
+	}
+	if loans > 0 {
+		return fmt.Errorf("the user %s still has %d open loans", userID, loans)
+	}
+	escrows, err := countRecords(ctx, escrowObjectType, func(value []byte) (bool, error) {
+		var escrow Escrow
+		err := json.Unmarshal(value, &escrow)
+		return escrow.Status == EscrowFunded && (escrow.SellerID == userID || escrow.BuyerID == userID), err
+	})
+	if err != nil {
+		return err
+	}
+	if escrows > 0 {
+		return fmt.Errorf("the user %s still has %d funded escrows", userID, escrows)
+	}
+	auctions, err := countRecords(ctx, auctionObjectType, func(value []byte) (bool, error) {
+		var auction Auction
+		err := json.Unmarshal(value, &auction)
+		return auctionRunning(&auction) && (auction.HighBidderID == userID || bidDeposit(&auction, userID) > 0), err
+	})
+	if err != nil {
+		return err
+	}
+	if auctions > 0 {
+		return fmt.Errorf("the user %s still has funds held by %d auctions", userID, auctions)
+	}
+
+	return nil
+}
+
+// countRecords returns the number of records of the object type for which matches returns true
+func countRecords(ctx contractapi.TransactionContextInterface, objectType string, matches func(value []byte) (bool, error)) (int, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, []string{})
+	if err != nil {
+		return 0, err
+	}
+	defer resultsIterator.Close()
+
+	count := 0
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return 0, err
+		}
+
+		match, err := matches(queryResponse.Value)
+		if err != nil {
+			return 0, err
+		}
+		if match {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// GetUserByEmail returns the user registered with given email address.
+func (s *SmartContract) GetUserByEmail(ctx contractapi.TransactionContextInterface, email string) (*User, error) {
+	indexKey, err := emailIndexKey(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if userID == nil {
+		indexKey, err = emailHashIndexKey(ctx, hashEmail(email))
+		if err != nil {
+			return nil, err
+		}
+		userID, err = ctx.GetStub().GetState(indexKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read from world state: %v", err)
+		}
+	}
+	if userID == nil {
+		return nil, fmt.Errorf("no user with email %s", email)
+	}
+
+	return s.ReadUser(ctx, string(userID))
+}
+
+// UserExists returns true when user with given ID exists in world state
+func (s *SmartContract) UserExists(ctx contractapi.TransactionContextInterface, id string) (bool, error) {
+	userJSON, err := ctx.GetStub().GetState(id)
+	if err != nil {
+		return false, fmt.Errorf("failed to read from world state: %v", err)
 	}
 
-	userJSON, err := json.Marshal(user)
+	return userJSON != nil, nil
+}
+
+const emailIndexName = "email~user"
+
+// emailIndexKey returns the key of the email index entry for given email address
+func emailIndexKey(ctx contractapi.TransactionContextInterface, email string) (string, error) {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(emailIndexName, []string{strings.ToLower(email)})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return indexKey, nil
+}
+
+// checkEmailAvailable returns an error when another user already uses given email address,
+// publicly or privately
+func checkEmailAvailable(ctx contractapi.TransactionContextInterface, email string) error {
+	indexKey, err := emailIndexKey(ctx, email)
+	if err != nil {
+		return err
+	}
+	hashIndexKey, err := emailHashIndexKey(ctx, hashEmail(email))
 	if err != nil {
 		return err
 	}
+	for _, key := range []string{indexKey, hashIndexKey} {
+		userID, err := ctx.GetStub().GetState(key)
+		if err != nil {
+			return fmt.Errorf("failed to read from world state: %v", err)
+		}
+		if userID != nil {
+			return fmt.Errorf("email %s is already used by user %s", email, userID)
+		}
+	}
 
-	return ctx.GetStub().PutState(id, userJSON)
+	return nil
+}
+
+// putEmailIndex maps given email address to the user ID
+func putEmailIndex(ctx contractapi.TransactionContextInterface, email string, userID string) error {
+	indexKey, err := emailIndexKey(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte(userID))
+}
+
+// deleteEmailIndex removes the email index entry for given email address
+func deleteEmailIndex(ctx contractapi.TransactionContextInterface, email string) error {
+	indexKey, err := emailIndexKey(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().DelState(indexKey)
+}
+
+// checkUserActive returns an error when the account of the user has been closed or frozen
+func checkUserActive(user *User) error {
+	if user.Status == UserClosed {
+		return fmt.Errorf("the user %s is closed", user.ID)
+	}
+	if user.Status == UserFrozen {
+		return fmt.Errorf("%s: the user %s is frozen", ErrCodeUserFrozen, user.ID)
+	}
+
+	return nil
+}
+
+// validateContactDetails checks that name, lastname and email are usable
+func validateContactDetails(name string, lastname string, email string) error {
+	if strings.TrimSpace(name) == "" || strings.TrimSpace(lastname) == "" {
+		return fmt.Errorf("name and lastname must not be empty")
+	}
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return fmt.Errorf("invalid email address %s", email)
+	}
+
+	return nil
 }
 
 // ReadAsset returns the asset stored in the world state with given id.
@@ -148,7 +569,15 @@ func (s *SmartContract) ReadAsset(ctx contractapi.TransactionContextInterface, i
 	}
 
 	var asset Asset
-	err = json.Unmarshal(assetJSON, &asset)
+	err = decodeAsset(assetJSON, &asset)
+	if err != nil {
+		return nil, err
+	}
+	err = checkExpectedVersion(ctx, asset.ID, asset.Version)
+	if err != nil {
+		return nil, err
+	}
+	err = loadDamages(ctx, &asset)
 	if err != nil {
 		return nil, err
 	}
@@ -158,6 +587,11 @@ func (s *SmartContract) ReadAsset(ctx contractapi.TransactionContextInterface, i
 
 // ReadUser returns the user stored in the world state with given id.
 func (s *SmartContract) ReadUser(ctx contractapi.TransactionContextInterface, id string) (*User, error) {
+	return readUser(ctx, id)
+}
+
+// readUser returns the user stored in the world state with given id
+func readUser(ctx contractapi.TransactionContextInterface, id string) (*User, error) {
 	userJSON, err := ctx.GetStub().GetState(id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read from world state: %v", err)
@@ -167,7 +601,11 @@ func (s *SmartContract) ReadUser(ctx contractapi.TransactionContextInterface, id
 	}
 
 	var user User
-	err = json.Unmarshal(userJSON, &user)
+	err = decodeUser(userJSON, &user)
+	if err != nil {
+		return nil, err
+	}
+	err = checkExpectedVersion(ctx, user.ID, user.Version)
 	if err != nil {
 		return nil, err
 	}
@@ -175,8 +613,48 @@ func (s *SmartContract) ReadUser(ctx contractapi.TransactionContextInterface, id
 	return &user, nil
 }
 
+// putUser writes the given user to the world state under its ID, recording the submitting client as its last editor
+// and increasing its version.
+func putUser(ctx contractapi.TransactionContextInterface, user *User) error {
+	err := stampUpdated(ctx, &user.Audit)
+	if err != nil {
+		return err
+	}
+	user.Version++
+	userJSON, err := encodeUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(user.ID, userJSON)
+}
+
+// putAsset writes the given asset to the world state under its ID, recording the submitting client as its last editor
+// and the change in the audit trail of the asset, and increasing its version.
+func putAsset(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	err := stampUpdated(ctx, &asset.Audit)
+	if err != nil {
+		return err
+	}
+	asset.Version++
+	asset.DocType = assetDocType
+	// damages live under their own keys, see loadDamages
+	stored := *asset
+	stored.Damages = unkeyedDamages(asset.Damages)
+	assetJSON, err := encodeAsset(ctx, &stored)
+	if err != nil {
+		return err
+	}
+	err = putAuditEntry(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(asset.ID, assetJSON)
+}
+
 // UpdateAsset updates an existing asset in the world state with provided parameters.
-// func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface, id string, color string, size int, owner string, appraisedValue float64) error {
+// func (s *SmartContract) UpdateAsset(ctx contractapi.TransactionContextInterface, id string, color string, size int, owner string, appraisedValue int64) error {
 // 	exists, err := s.AssetExists(ctx, id)
 // 	if err != nil {
 // 		return err
@@ -200,19 +678,140 @@ func (s *SmartContract) ReadUser(ctx contractapi.TransactionContextInterface, id
 // 	return ctx.GetStub().PutState(id, assetJSON)
 // }
 
-// DeleteAsset deletes an given asset from the world state.
+// DeleteAsset deletes an given asset from the world state together with its damages, listing,
+// leases, insurance policies and auctions. Only the owner or an admin may delete an asset, and not
+// while it is held, encumbered or auctioned.
 func (s *SmartContract) DeleteAsset(ctx contractapi.TransactionContextInterface, id string) error {
-	exists, err := s.AssetExists(ctx, id)
+	asset, err := s.ReadAsset(ctx, id)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("the asset %s does not exist", id)
+	err = s.verifyOwnerOrAdmin(ctx, asset)
+	if err != nil {
+		return err
+	}
+	err = checkAssetDeletable(ctx, asset)
+	if err != nil {
+		return err
+	}
+	err = deleteAssetRecords(ctx, id)
+	if err != nil {
+		return err
+	}
+	if asset.VIN != "" {
+		err = deleteVINIndex(ctx, asset.VIN)
+		if err != nil {
+			return err
+		}
+	}
+	err = putAuditEntry(ctx, id)
+	if err != nil {
+		return err
 	}
 
 	return ctx.GetStub().DelState(id)
 }
 
+// verifyOwnerOrAdmin returns an error unless the submitting client is an admin or acts for the owner of the asset
+func (s *SmartContract) verifyOwnerOrAdmin(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	if hasAttr(ctx, attr(adminAttribute, "true")) {
+		return nil
+	}
+	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	return verifyUserIdentity(ctx, owner)
+}
+
+// checkAssetDeletable returns an error when deleting the asset would drop a claim someone else has on it
+func checkAssetDeletable(ctx contractapi.TransactionContextInterface, asset *Asset) error {
+	err := checkAssetNotHeld(asset)
+	if err != nil {
+		return err
+	}
+	if asset.Encumbered {
+		return fmt.Errorf("the asset %s is encumbered and cannot be deleted", asset.ID)
+	}
+	if asset.LienID != "" {
+		return fmt.Errorf("the asset %s has a lien held by user %s", asset.ID, asset.LienholderID)
+	}
+	if asset.ExportID != "" {
+		return fmt.Errorf("the asset %s is being exported", asset.ID)
+	}
+	if asset.RegistrationID != "" {
+		return fmt.Errorf("the asset %s has pending registration %s", asset.ID, asset.RegistrationID)
+	}
+	// bidders' money is held by the auction until it ends
+	if asset.AuctionID != "" {
+		return fmt.Errorf("the asset %s is up for auction %s", asset.ID, asset.AuctionID)
+	}
+
+	return checkNotTokenized(asset)
+}
+
+// deleteAssetRecords deletes the damages, the listing, the leases, the insurance policies and the
+// auctions of the asset with given ID
+func deleteAssetRecords(ctx contractapi.TransactionContextInterface, assetID string) error {
+	err := deleteRecords(ctx, damageObjectType, []string{assetID}, nil)
+	if err != nil {
+		return err
+	}
+	err = deleteRecords(ctx, listingObjectType, []string{assetID}, nil)
+	if err != nil {
+		return err
+	}
+	ofAsset := func(value []byte) (bool, error) {
+		var record struct {
+			AssetID string `json:"assetID"`
+		}
+		err := json.Unmarshal(value, &record)
+		return record.AssetID == assetID, err
+	}
+	for _, objectType := range []string{leaseObjectType, policyObjectType, auctionObjectType} {
+		err = deleteRecords(ctx, objectType, []string{}, ofAsset)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteRecords deletes the records of the object type under the partial key attributes for which
+// matches returns true, or all of them when matches is nil
+func deleteRecords(ctx contractapi.TransactionContextInterface, objectType string, attributes []string, matches func(value []byte) (bool, error)) error {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(objectType, attributes)
+	if err != nil {
+		return err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return err
+		}
+
+		if matches != nil {
+			match, err := matches(queryResponse.Value)
+			if err != nil {
+				return err
+			}
+			if !match {
+				continue
+			}
+		}
+		err = ctx.GetStub().DelState(queryResponse.Key)
+		if err != nil {
+			return fmt.Errorf("failed to delete from world state: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // AssetExists returns true when asset with given ID exists in world state
 func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface, id string) (bool, error) {
 	assetJSON, err := ctx.GetStub().GetState(id)
@@ -223,10 +822,17 @@ func (s *SmartContract) AssetExists(ctx contractapi.TransactionContextInterface,
 	return assetJSON != nil, nil
 }
 
-// TransferAsset updates the owner field of asset with given id in world state.
-func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id string, newOwner string, withDamage bool) error {
+// TransferAsset sells asset with given id to newOwner at the negotiated salePrice. A car with unrepaired
+// damages is only sold when withDamage is set. The submitting client must be bound to the seller or
+// to the delegate the seller approved with ApproveTransferDelegate, and to the buyer; otherwise use
+// OfferAsset and AcceptOffer. While transfers are regulated the sale waits for the registry to
+// confirm it.
+func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterface, id string, newOwner string, withDamage bool, salePrice int64) error {
 	asset, err := s.ReadAsset(ctx, id)
 	if err != nil {
+		if isVersionConflict(err) {
+			return err
+		}
 		return fmt.Errorf("Car not found")
 	}
 	if asset.OwnerID == newOwner {
@@ -236,44 +842,24 @@ func (s *SmartContract) TransferAsset(ctx contractapi.TransactionContextInterfac
 	if err != nil {
 		return fmt.Errorf("Owner not found")
 	}
-	newO, err := s.ReadUser(ctx, newOwner)
-	if err != nil {
-		return fmt.Errorf("New owner not found")
-	}
-	length := len(asset.Damages)
-	totalPrice := asset.AppraisedValue
-	if length == 0 {
-		asset.OwnerID = newOwner
-	} else if withDamage {
-		totalDamage := 0.0
-		for i := 0; i < length; i++ {
-			totalDamage = totalDamage + asset.Damages[i].Cost
-		}
-		asset.OwnerID = newOwner
-		totalPrice = totalPrice - totalDamage
-	} else {
-		return fmt.Errorf("Car has unrepaired damages")
-	}
-	if newO.Money < totalPrice {
-		return fmt.Errorf("Customer doesn't have enough money on his account")
-	}
-	assetJSON, err := json.Marshal(asset)
+	err = s.verifyOwnerOrDelegate(ctx, asset, owner)
 	if err != nil {
 		return err
 	}
-	owner.Money = owner.Money + totalPrice
-	newO.Money = newO.Money - totalPrice
-	ownerJSON, err := json.Marshal(owner)
+	newO, err := s.ReadUser(ctx, newOwner)
 	if err != nil {
-		return err
+		return fmt.Errorf("New owner not found")
 	}
-	newOwnerJSON, err := json.Marshal(newO)
+	err = verifyUserIdentity(ctx, newO)
 	if err != nil {
 		return err
 	}
-	ctx.GetStub().PutState(owner.ID, ownerJSON)
-	ctx.GetStub().PutState(newOwner, newOwnerJSON)
-	return ctx.GetStub().PutState(id, assetJSON)
+	if len(asset.Damages) > 0 && !withDamage {
+		return fmt.Errorf("Car has unrepaired damages")
+	}
+
+	_, err = settleOrRegister(ctx, &saleTerms{asset: asset, seller: owner, buyer: newO, price: salePrice, upfront: salePrice})
+	return err
 }
 
 // GetAllAssets returns all assets found in world state
@@ -294,7 +880,11 @@ func (s *SmartContract) GetAllAssets(ctx contractapi.TransactionContextInterface
 		}
 
 		var asset Asset
-		err = json.Unmarshal(queryResponse.Value, &asset)
+		err = decodeAsset(queryResponse.Value, &asset)
+		if err != nil {
+			return nil, err
+		}
+		err = loadDamages(ctx, &asset)
 		if err != nil {
 			return nil, err
 		}
@@ -321,7 +911,7 @@ func (s *SmartContract) GetAllUsers(ctx contractapi.TransactionContextInterface)
 		}
 
 		var user User
-		err = json.Unmarshal(queryResponse.Value, &user)
+		err = decodeUser(queryResponse.Value, &user)
 		if err != nil {
 			return nil, err
 		}
@@ -331,89 +921,104 @@ func (s *SmartContract) GetAllUsers(ctx contractapi.TransactionContextInterface)
 	return users, nil
 }
 
-// ChangeAssetColor updates color of asset with given ID
+// ChangeAssetColor updates color of asset with given ID. Only the owner or an admin may repaint it.
 func (s *SmartContract) ChangeAssetColor(ctx contractapi.TransactionContextInterface, id string, color string) error {
 	asset, err := s.ReadAsset(ctx, id)
 	if err != nil {
+		if isVersionConflict(err) {
+			return err
+		}
 		return fmt.Errorf("Car not found")
 	}
-	asset.Color = color
-	assetJSON, err := json.Marshal(asset)
+	err = s.verifyOwnerOrAdmin(ctx, asset)
 	if err != nil {
 		return err
 	}
-
-	return ctx.GetStub().PutState(id, assetJSON)
+	asset.Color = color
+	return putAsset(ctx, asset)
 }
 
 // CreateAssetDamage issues a new damage to the asset in the world state with given details.
-func (s *SmartContract) CreateAssetDamage(ctx contractapi.TransactionContextInterface, id string, description string, cost float64) error {
+// The damage is stored under its own asset~damage key and its ID is the transaction ID.
+// The asset is marked totaled once its damages cost more than its appraised value, so only admins,
+// mechanics and the owner may report damages.
+func (s *SmartContract) CreateAssetDamage(ctx contractapi.TransactionContextInterface, id string, description string, cost int64) error {
 	asset, err := s.ReadAsset(ctx, id)
 	if err != nil {
+		if isVersionConflict(err) {
+			return err
+		}
 		return fmt.Errorf("Car not found")
 	}
-	damage := Damage{
-		Description: description,
-		Cost:        cost,
-	}
-	asset.Damages = append(asset.Damages, damage)
-	totalCost := 0.0
-	for _, damage := range asset.Damages {
-		totalCost = totalCost + damage.Cost
-	}
-	if totalCost > asset.AppraisedValue {
-		return ctx.GetStub().DelState(id)
-	}
-	assetJSON, err := json.Marshal(asset)
-	if err != nil {
-		return err
+	if !hasAttr(ctx, attr(adminAttribute, "true")) && !hasAttr(ctx, attr(roleAttribute, RoleMechanic)) {
+		owner, err := s.ReadUser(ctx, asset.OwnerID)
+		if err != nil {
+			return err
+		}
+		err = verifyUserIdentity(ctx, owner)
+		if err != nil {
+			return fmt.Errorf("only admins, mechanics and the owner can report damages to asset %s", id)
+		}
 	}
-	return ctx.GetStub().PutState(id, assetJSON)
+
+	return s.addAssetDamage(ctx, id, description, cost, nil)
 }
 
-// RepairDamages removes all damages from asset with given ID
-func (s *SmartContract) RepairDamages(ctx contractapi.TransactionContextInterface, id string, mechanic string) error {
+// addAssetDamage records a new damage to the asset, repaired at the expense of given users
+func (s *SmartContract) addAssetDamage(ctx contractapi.TransactionContextInterface, id string, description string, cost int64, liability []LiabilityShare) error {
+	if cost <= 0 {
+		return fmt.Errorf("damage cost must be positive")
+	}
 	asset, err := s.ReadAsset(ctx, id)
 	if err != nil {
+		if isVersionConflict(err) {
+			return err
+		}
 		return fmt.Errorf("Car not found")
 	}
-	owner, err := s.ReadUser(ctx, asset.OwnerID)
+	reporter, err := submittingClientID(ctx)
 	if err != nil {
-		return fmt.Errorf("Owner not found")
+		return err
 	}
-	repairman, err := s.ReadUser(ctx, mechanic)
+	now, err := txTime(ctx)
 	if err != nil {
-		return fmt.Errorf("Repairman not found")
+		return err
 	}
-
-	totalCost := 0.0
-	for _, damage := range asset.Damages {
-		totalCost = totalCost + damage.Cost
+	damage := Damage{
+		ID:          ctx.GetStub().GetTxID(),
+		AssetID:     id,
+		Description: description,
+		Cost:        cost,
+		Status:      DamageOpen,
+		ReporterID:  reporter,
+		ReportedAt:  now,
+		Liability:   liability,
 	}
-
-	if owner.Money < totalCost {
-		return fmt.Errorf("Owner doesn't have enough money on his account")
+	err = stampCreated(ctx, &damage.Audit)
+	if err != nil {
+		return err
 	}
-
-	owner.Money = owner.Money - totalCost
-	repairman.Money = repairman.Money + totalCost
-	ownerJSON, err := json.Marshal(owner)
+	value, err := appraisedValue(ctx, asset)
 	if err != nil {
 		return err
 	}
-	repairmanJSON, err := json.Marshal(repairman)
+	previousCost := damagesCost(asset.Damages)
+	asset.Damages = append(asset.Damages, damage)
+	err = checkAssetAtRisk(ctx, asset, value, previousCost)
 	if err != nil {
 		return err
 	}
-
-	asset.Damages = []Damage{}
-	assetJSON, err := json.Marshal(asset)
+	err = putDamage(ctx, &damage)
 	if err != nil {
 		return err
 	}
-	ctx.GetStub().PutState(owner.ID, ownerJSON)
-	ctx.GetStub().PutState(mechanic, repairmanJSON)
-	return ctx.GetStub().PutState(id, assetJSON)
+	// the damage has its own key, the asset is only rewritten when the damage totals it
+	if damagesCost(asset.Damages) > value && asset.Status != AssetTotaled {
+		asset.Status = AssetTotaled
+		return putAsset(ctx, asset)
+	}
+
+	return nil
 }
 
 // FindAssets returns all assets by color and owner
@@ -432,11 +1037,15 @@ func (s *SmartContract) FindAssets(ctx contractapi.TransactionContextInterface,
 		}
 
 		var asset Asset
-		err = json.Unmarshal(queryResponse.Value, &asset)
+		err = decodeAsset(queryResponse.Value, &asset)
 		if err != nil {
 			return nil, err
 		}
 		if (asset.Color == color || color == "") && (owner == "" || asset.OwnerID == owner) {
+			err = loadDamages(ctx, &asset)
+			if err != nil {
+				return nil, err
+			}
 			assets = append(assets, &asset)
 		}
 	}
@@ -1,10 +1,16 @@
 package chaincode_test
 
 import (
+	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric-chaincode-go/pkg/cid"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-contract-api-go/contractapi"
 	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
@@ -28,10 +34,22 @@ type stateQueryIterator interface {
 	shim.StateQueryIteratorInterface
 }
 
+//go:generate counterfeiter -o mocks/clientidentity.go -fake-name ClientIdentity . clientIdentity
+type clientIdentity interface {
+	cid.ClientIdentity
+}
+
+//go:generate counterfeiter -o mocks/historyqueryiterator.go -fake-name HistoryQueryIterator . historyQueryIterator
+type historyQueryIterator interface {
+	shim.HistoryQueryIteratorInterface
+}
+
 func TestInitLedger(t *testing.T) {
 	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000}, nil)
 	transactionContext := &mocks.TransactionContext{}
 	transactionContext.GetStubReturns(chaincodeStub)
+	transactionContext.GetClientIdentityReturns(&mocks.ClientIdentity{})
 
 	assetTransfer := chaincode.SmartContract{}
 	err := assetTransfer.InitLedger(transactionContext)
@@ -39,29 +57,75 @@ func TestInitLedger(t *testing.T) {
 
 	chaincodeStub.PutStateReturns(fmt.Errorf("failed inserting key"))
 	err = assetTransfer.InitLedger(transactionContext)
-	require.EqualError(t, err, "failed to put to world state. failed inserting key")
+	require.EqualError(t, err, "failed to put user to world state. failed inserting key")
+}
+
+func TestInitLedgerOnlyOnce(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.InitLedger(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, []byte("1"), state["\x00config\x00ledgerInitialized\x00"])
+	asset, err := assetTransfer.ReadAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	require.Equal(t, "asset", asset.DocType)
+	require.Equal(t, int64(1), asset.Version)
+
+	err = assetTransfer.InitLedger(transactionContext)
+	require.EqualError(t, err, "the ledger is already initialized")
+
+	transactionContext.GetClientIdentity().(*mocks.ClientIdentity).AssertAttributeValueReturns(fmt.Errorf("attribute admin not found"))
+	err = assetTransfer.InitLedger(transactionContext)
+	require.EqualError(t, err, "submitting client does not have attribute admin=true")
 }
 
 func TestCreateAsset(t *testing.T) {
 	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000}, nil)
+	clientIdentity := &mocks.ClientIdentity{}
 	transactionContext := &mocks.TransactionContext{}
 	transactionContext.GetStubReturns(chaincodeStub)
+	transactionContext.GetClientIdentityReturns(clientIdentity)
 
 	assetTransfer := chaincode.SmartContract{}
-	err := assetTransfer.CreateAsset(transactionContext, "", "", 0, "", 0)
+	err := assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "", "", 0, "", "", 0)
 	require.NoError(t, err)
 
+	err = assetTransfer.CreateAsset(transactionContext, "", "1HGCM82633A004352", "", "", 0, "", "", 0)
+	require.EqualError(t, err, "asset ID  must start with \"asset\"")
+
+	err = assetTransfer.CreateAsset(transactionContext, "car1", "1HGCM82633A004352", "", "", 0, "", "", 0)
+	require.EqualError(t, err, "asset ID car1 must start with \"asset\"")
+
+	clientIdentity.AssertAttributeValueStub = func(name string, value string) error {
+		if name == "role" && value == chaincode.RoleDealer {
+			return nil
+		}
+		return fmt.Errorf("attribute %s not found", name)
+	}
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "", "", 0, "", "", 0)
+	require.NoError(t, err)
+
+	clientIdentity.AssertAttributeValueStub = nil
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute role not found"))
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "", "", 0, "", "user1", 0)
+	require.EqualError(t, err, "the user user1 does not exist")
+	clientIdentity.AssertAttributeValueReturns(nil)
+
 	chaincodeStub.GetStateReturns([]byte{}, nil)
-	err = assetTransfer.CreateAsset(transactionContext, "asset1", "", 0, "", 0)
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "", "", 0, "", "", 0)
 	require.EqualError(t, err, "the asset asset1 already exists")
 
 	chaincodeStub.GetStateReturns(nil, fmt.Errorf("unable to retrieve asset"))
-	err = assetTransfer.CreateAsset(transactionContext, "asset1", "", 0, "", 0)
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A004352", "", "", 0, "", "", 0)
 	require.EqualError(t, err, "failed to read from world state: unable to retrieve asset")
 }
 
 func TestReadAsset(t *testing.T) {
 	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(&mocks.StateQueryIterator{}, nil)
 	transactionContext := &mocks.TransactionContext{}
 	transactionContext.GetStubReturns(chaincodeStub)
 
@@ -85,70 +149,290 @@ func TestReadAsset(t *testing.T) {
 	require.Nil(t, asset)
 }
 
-func TestUpdateAsset(t *testing.T) {
+func TestDeleteAsset(t *testing.T) {
 	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(&mocks.StateQueryIterator{}, nil)
 	transactionContext := &mocks.TransactionContext{}
 	transactionContext.GetStubReturns(chaincodeStub)
+	transactionContext.GetClientIdentityReturns(&mocks.ClientIdentity{})
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000}, nil)
 
-	expectedAsset := &chaincode.Asset{ID: "asset1"}
-	bytes, err := json.Marshal(expectedAsset)
+	asset := &chaincode.Asset{ID: "asset1"}
+	bytes, err := json.Marshal(asset)
 	require.NoError(t, err)
 
 	chaincodeStub.GetStateReturns(bytes, nil)
+	chaincodeStub.DelStateReturns(nil)
 	assetTransfer := chaincode.SmartContract{}
-	err = assetTransfer.UpdateAsset(transactionContext, "", "", 0, "", 0)
+	err = assetTransfer.DeleteAsset(transactionContext, "")
 	require.NoError(t, err)
 
 	chaincodeStub.GetStateReturns(nil, nil)
-	err = assetTransfer.UpdateAsset(transactionContext, "asset1", "", 0, "", 0)
+	err = assetTransfer.DeleteAsset(transactionContext, "asset1")
 	require.EqualError(t, err, "the asset asset1 does not exist")
 
 	chaincodeStub.GetStateReturns(nil, fmt.Errorf("unable to retrieve asset"))
-	err = assetTransfer.UpdateAsset(transactionContext, "asset1", "", 0, "", 0)
+	err = assetTransfer.DeleteAsset(transactionContext, "")
 	require.EqualError(t, err, "failed to read from world state: unable to retrieve asset")
 }
 
-func TestDeleteAsset(t *testing.T) {
-	chaincodeStub := &mocks.ChaincodeStub{}
-	transactionContext := &mocks.TransactionContext{}
-	transactionContext.GetStubReturns(chaincodeStub)
-
-	asset := &chaincode.Asset{ID: "asset1"}
-	bytes, err := json.Marshal(asset)
+func TestDeleteAssetRestricted(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.AssertAttributeValueReturns(fmt.Errorf("attribute admin not found"))
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "owner"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Encumbered: true})
+	records := map[string][]string{
+		"asset~damage": {"asset1", "damage1"},
+		"listing":      {"asset1"},
+		"lease":        {"lease1"},
+		"policy":       {"policy1"},
+		"auction":      {"auction1"},
+	}
+	for objectType, attributes := range records {
+		key, err := shim.CreateCompositeKey(objectType, attributes)
+		require.NoError(t, err)
+		state.put(t, key, map[string]string{"assetID": "asset1", "status": chaincode.AuctionEnded})
+	}
+	auctionKey, err := shim.CreateCompositeKey("auction", []string{"auction2"})
 	require.NoError(t, err)
+	state.put(t, auctionKey, &chaincode.Auction{ID: "auction2", AssetID: "asset2", Status: chaincode.AuctionOpen})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1", AuctionID: "auction2"})
 
-	chaincodeStub.GetStateReturns(bytes, nil)
-	chaincodeStub.DelStateReturns(nil)
 	assetTransfer := chaincode.SmartContract{}
-	err = assetTransfer.DeleteAsset(transactionContext, "")
-	require.NoError(t, err)
+	clientIdentity.GetIDReturns("stranger", nil)
+	err = assetTransfer.DeleteAsset(transactionContext, "asset1")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
+	err = assetTransfer.ChangeAssetColor(transactionContext, "asset1", "red")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
 
-	chaincodeStub.GetStateReturns(nil, nil)
+	clientIdentity.GetIDReturns("owner", nil)
 	err = assetTransfer.DeleteAsset(transactionContext, "asset1")
-	require.EqualError(t, err, "the asset asset1 does not exist")
+	require.EqualError(t, err, "the asset asset1 is encumbered and cannot be deleted")
+	err = assetTransfer.DeleteAsset(transactionContext, "asset2")
+	require.EqualError(t, err, "the asset asset2 is up for auction auction2")
+	require.NoError(t, assetTransfer.ChangeAssetColor(transactionContext, "asset1", "red"))
+
+	var asset chaincode.Asset
+	state.get(t, "asset1", &asset)
+	asset.Encumbered = false
+	state.put(t, "asset1", &asset)
+	require.NoError(t, assetTransfer.DeleteAsset(transactionContext, "asset1"))
+	require.NotContains(t, state, "asset1")
+	for objectType, attributes := range records {
+		key, err := shim.CreateCompositeKey(objectType, attributes)
+		require.NoError(t, err)
+		require.NotContains(t, state, key, "%s record left behind", objectType)
+	}
+	require.Contains(t, state, auctionKey)
+}
+
+func TestTransferAsset(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 100})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Damages: []chaincode.Damage{{Description: "tyre", Cost: 200}}, AppraisedValue: 3000})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.TransferAsset(transactionContext, "asset1", "user1", false, 2800)
+	require.EqualError(t, err, "New owner is same as current")
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 2800)
+	require.EqualError(t, err, "Car has unrepaired damages")
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", true, 2800)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(2900), seller.Money)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(2200), buyer.Money)
+	transfer := &chaincode.TransferRecord{}
+	state.get(t, "\x00transfer\x00asset1\x00tx1\x00", transfer)
+	require.Equal(t, &chaincode.TransferRecord{TxID: "tx1", AssetID: "asset1", SellerID: "user1", BuyerID: "user2", Price: 2800, AppraisedValue: 3000}, transfer)
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user1", true, 5000)
+	require.EqualError(t, err, "Customer doesn't have enough money on his account")
 
 	chaincodeStub.GetStateReturns(nil, fmt.Errorf("unable to retrieve asset"))
-	err = assetTransfer.DeleteAsset(transactionContext, "")
-	require.EqualError(t, err, "failed to read from world state: unable to retrieve asset")
+	chaincodeStub.GetStateStub = nil
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user1", false, 0)
+	require.EqualError(t, err, "Car not found")
 }
 
-func TestTransferAsset(t *testing.T) {
-	chaincodeStub := &mocks.ChaincodeStub{}
-	transactionContext := &mocks.TransactionContext{}
-	transactionContext.GetStubReturns(chaincodeStub)
+func TestCreateUser(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
 
-	asset := &chaincode.Asset{ID: "asset1"}
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateUser(transactionContext, "user4", "Milan", "Milanovic", "milan.milanovic@email.com", 5600)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user4", user)
+	require.Equal(t, &chaincode.User{ID: "user4", Name: "Milan", Lastname: "Milanovic", Email: "milan.milanovic@email.com", Money: 5600, Status: chaincode.UserActive, Roles: []string{chaincode.RoleOwner}, Audit: chaincode.Audit{
+		CreatedAt: time.Unix(1600000000, 0).UTC(),
+		UpdatedAt: time.Unix(1600000000, 0).UTC(),
+	}, Version: 1}, user)
+
+	err = assetTransfer.CreateUser(transactionContext, "user4", "Milan", "Milanovic", "milan.milanovic@email.com", 5600)
+	require.EqualError(t, err, "the user user4 already exists")
+
+	err = assetTransfer.CreateUser(transactionContext, "user5", "Milan", "Petrovic", "Milan.Milanovic@email.com", 0)
+	require.EqualError(t, err, "email Milan.Milanovic@email.com is already used by user user4")
+
+	err = assetTransfer.CreateUser(transactionContext, "milan", "Milan", "Milanovic", "milan.milanovic@email.com", 5600)
+	require.EqualError(t, err, "user ID milan must start with \"user\"")
+
+	err = assetTransfer.CreateUser(transactionContext, "user5", "", "Milanovic", "milan.milanovic@email.com", 5600)
+	require.EqualError(t, err, "name and lastname must not be empty")
+
+	err = assetTransfer.CreateUser(transactionContext, "user5", "Milan", "Milanovic", "milan.milanovic", 5600)
+	require.EqualError(t, err, "invalid email address milan.milanovic")
+
+	err = assetTransfer.CreateUser(transactionContext, "user5", "Milan", "Milanovic", "milan.milanovic@email.com", -1)
+	require.EqualError(t, err, "initial balance must not be negative")
+}
+
+func TestCloseUserAccount(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 250, Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 100, Status: chaincode.UserActive})
+	asset := &chaincode.Asset{ID: "asset1", OwnerID: "user1"}
 	bytes, err := json.Marshal(asset)
 	require.NoError(t, err)
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Value: bytes}, nil)
+	chaincodeStub.GetStateByRangeReturnsOnCall(0, iterator, nil)
+	chaincodeStub.GetStateByRangeReturns(&mocks.StateQueryIterator{}, nil)
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(&mocks.StateQueryIterator{}, nil)
+
+	assetTransfer := chaincode.SmartContract{}
+	err = assetTransfer.CloseUserAccount(transactionContext, "user1", "user2")
+	require.EqualError(t, err, "the user user1 still owns 1 assets")
+
+	err = assetTransfer.CloseUserAccount(transactionContext, "user1", "user2")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, &chaincode.User{ID: "user1", Money: 0, Status: chaincode.UserClosed, Audit: chaincode.Audit{UpdatedAt: time.Unix(1600000000, 0).UTC()}, Version: 1}, user)
+	payout := &chaincode.User{}
+	state.get(t, "user2", payout)
+	require.Equal(t, int64(350), payout.Money)
+
+	err = assetTransfer.CloseUserAccount(transactionContext, "user1", "user2")
+	require.EqualError(t, err, "the user user1 is closed")
+
+	err = assetTransfer.DepositFunds(transactionContext, "user1", 10)
+	require.EqualError(t, err, "the user user1 is closed")
+}
+
+func TestCloseHotAccount(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 250, Status: chaincode.UserActive, HotAccount: true})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 100, Status: chaincode.UserActive})
+	state.put(t, "\x00balance~delta\x00user1\x00tx1\x00proceeds\x00", &chaincode.BalanceDelta{UserID: "user1", TxID: "tx1", Reason: "proceeds", Amount: 700})
+
+	assetTransfer := chaincode.SmartContract{}
+	require.NoError(t, assetTransfer.CloseUserAccount(transactionContext, "user1", "user2"))
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(0), user.Money)
+	require.Nil(t, state["\x00balance~delta\x00user1\x00tx1\x00proceeds\x00"])
+	state.get(t, "user2", user)
+	require.Equal(t, int64(1050), user.Money)
+}
+
+func TestCloseUserAccountWithOpenRecords(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanRanges(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 250, Status: chaincode.UserActive})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 100, Status: chaincode.UserActive})
+
+	records := []struct {
+		key    string
+		record interface{}
+		err    string
+	}{
+		{"asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user2", CoOwners: []chaincode.OwnershipShare{{UserID: "user2", Share: 6000}, {UserID: "user1", Share: 4000}}}, "the user user1 still co-owns 1 assets"},
+		{"\x00lease\x00lease1\x00", &chaincode.Lease{ID: "lease1", LessorID: "user2", LesseeID: "user1", Status: chaincode.LeaseActive, NextDueAt: time.Unix(1700000000, 0)}, "the user user1 still has 1 running leases"},
+		{"\x00shareToken\x00asset2\x00user1\x00", &chaincode.ShareHolding{AssetID: "asset2", UserID: "user1", Shares: 10}, "the user user1 still holds share tokens of 1 assets"},
+		{"\x00loan\x00loan1\x00", &chaincode.Loan{ID: "loan1", LenderID: "user1", BorrowerID: "user2", Status: chaincode.LoanActive}, "the user user1 still has 1 open loans"},
+		{"\x00escrow\x00escrow1\x00", &chaincode.Escrow{ID: "escrow1", SellerID: "user2", BuyerID: "user1", Status: chaincode.EscrowFunded}, "the user user1 still has 1 funded escrows"},
+		{"\x00auction\x00auction1\x00", &chaincode.Auction{ID: "auction1", SellerID: "user2", Type: chaincode.AuctionEnglish, Status: chaincode.AuctionOpen, HighBidderID: "user1", HighBid: 50}, "the user user1 still has funds held by 1 auctions"},
+		{"\x00auction\x00auction2\x00", &chaincode.Auction{ID: "auction2", SellerID: "user2", Type: chaincode.AuctionSealed, Status: chaincode.AuctionClosed, Deposits: []chaincode.BidDeposit{{BidderID: "user1", Amount: 20}}}, "the user user1 still has funds held by 1 auctions"},
+	}
 
-	chaincodeStub.GetStateReturns(bytes, nil)
 	assetTransfer := chaincode.SmartContract{}
-	err = assetTransfer.TransferAsset(transactionContext, "", "")
+	for _, record := range records {
+		state.put(t, record.key, record.record)
+		err := assetTransfer.CloseUserAccount(transactionContext, "user1", "user2")
+		require.EqualError(t, err, record.err)
+		delete(state, record.key)
+	}
+
+	state.put(t, "\x00loan\x00loan1\x00", &chaincode.Loan{ID: "loan1", LenderID: "user1", BorrowerID: "user2", Status: chaincode.LoanRepaid})
+	state.put(t, "\x00auction\x00auction1\x00", &chaincode.Auction{ID: "auction1", SellerID: "user2", Type: chaincode.AuctionEnglish, Status: chaincode.AuctionEnded, HighBidderID: "user1"})
+	err := assetTransfer.CloseUserAccount(transactionContext, "user1", "user2")
 	require.NoError(t, err)
+}
 
-	chaincodeStub.GetStateReturns(nil, fmt.Errorf("unable to retrieve asset"))
-	err = assetTransfer.TransferAsset(transactionContext, "", "")
-	require.EqualError(t, err, "failed to read from world state: unable to retrieve asset")
+func TestGetUserByEmail(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Email: "marko.markovic@email.com"})
+	state["\x00email~user\x00marko.markovic@email.com\x00"] = []byte("user1")
+
+	assetTransfer := chaincode.SmartContract{}
+	user, err := assetTransfer.GetUserByEmail(transactionContext, "Marko.Markovic@email.com")
+	require.NoError(t, err)
+	require.Equal(t, "user1", user.ID)
+
+	user, err = assetTransfer.GetUserByEmail(transactionContext, "nobody@email.com")
+	require.EqualError(t, err, "no user with email nobody@email.com")
+	require.Nil(t, user)
+}
+
+func TestUpdateUser(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Name: "Marko", Lastname: "Markovic", Email: "marko.markovic@email.com", Money: 100})
+	state["\x00email~user\x00marko.markovic@email.com\x00"] = []byte("user1")
+	state["\x00email~user\x00jovan.jovanovic@email.com\x00"] = []byte("user2")
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.UpdateUser(transactionContext, "user1", "Marko", "Petrovic", "jovan.jovanovic@email.com")
+	require.EqualError(t, err, "email jovan.jovanovic@email.com is already used by user user2")
+
+	err = assetTransfer.UpdateUser(transactionContext, "user1", "Marko", "Petrovic", "marko.petrovic@email.com")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, &chaincode.User{ID: "user1", Name: "Marko", Lastname: "Petrovic", Email: "marko.petrovic@email.com", Money: 100, Audit: chaincode.Audit{UpdatedAt: time.Unix(1600000000, 0).UTC()}, Version: 1}, user)
+	require.NotContains(t, state, "\x00email~user\x00marko.markovic@email.com\x00")
+	require.Equal(t, []byte("user1"), state["\x00email~user\x00marko.petrovic@email.com\x00"])
+
+	err = assetTransfer.UpdateUser(transactionContext, "user9", "Marko", "Petrovic", "marko.petrovic@email.com")
+	require.EqualError(t, err, "the user user9 does not exist")
+
+	err = assetTransfer.UpdateUser(transactionContext, "user1", "Marko", "Petrovic", "not an email")
+	require.EqualError(t, err, "invalid email address not an email")
+
+	transactionContext.GetClientIdentity().(*mocks.ClientIdentity).GetIDReturns("jovan", nil)
+	err = assetTransfer.UpdateUser(transactionContext, "user1", "Jovan", "Petrovic", "marko.petrovic@email.com")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user1")
 }
 
 func TestGetAllAssets(t *testing.T) {
@@ -162,6 +446,7 @@ func TestGetAllAssets(t *testing.T) {
 	iterator.NextReturns(&queryresult.KV{Value: bytes}, nil)
 
 	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(&mocks.StateQueryIterator{}, nil)
 	transactionContext := &mocks.TransactionContext{}
 	transactionContext.GetStubReturns(chaincodeStub)
 
@@ -182,3 +467,129 @@ func TestGetAllAssets(t *testing.T) {
 	require.EqualError(t, err, "failed retrieving all assets")
 	require.Nil(t, assets)
 }
+
+// worldState is an in-memory key/value store wired into the mocked stub, so
+// transactions that read and write several keys can be tested end to end.
+type worldState map[string][]byte
+
+func (w worldState) put(t *testing.T, key string, value interface{}) {
+	bytes, err := json.Marshal(value)
+	require.NoError(t, err)
+	w[key] = bytes
+}
+
+func (w worldState) get(t *testing.T, key string, value interface{}) {
+	bytes, ok := w[key]
+	require.True(t, ok, "key %s not found in world state", key)
+	require.NoError(t, json.Unmarshal(bytes, value))
+}
+
+// scanPartialCompositeKeys makes partial composite key queries of the stub iterate over the matching keys of the state
+func scanPartialCompositeKeys(state worldState, chaincodeStub *mocks.ChaincodeStub) {
+	chaincodeStub.GetStateByPartialCompositeKeyStub = func(objectType string, attributes []string) (shim.StateQueryIteratorInterface, error) {
+		prefix, err := shim.CreateCompositeKey(objectType, attributes)
+		if err != nil {
+			return nil, err
+		}
+		var keys []string
+		for key := range state {
+			if strings.HasPrefix(key, prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		iterator := &mocks.StateQueryIterator{}
+		for i, key := range keys {
+			iterator.HasNextReturnsOnCall(i, true)
+			iterator.NextReturnsOnCall(i, &queryresult.KV{Key: key, Value: state[key]}, nil)
+		}
+		return iterator, nil
+	}
+}
+
+// scanRanges makes range queries of the stub iterate over the simple keys of the state within the range
+func scanRanges(state worldState, chaincodeStub *mocks.ChaincodeStub) {
+	chaincodeStub.GetStateByRangeStub = func(startKey string, endKey string) (shim.StateQueryIteratorInterface, error) {
+		var keys []string
+		for key := range state {
+			if !strings.HasPrefix(key, "\x00") && key >= startKey && (endKey == "" || key < endKey) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		iterator := &mocks.StateQueryIterator{}
+		for i, key := range keys {
+			iterator.HasNextReturnsOnCall(i, true)
+			iterator.NextReturnsOnCall(i, &queryresult.KV{Key: key, Value: state[key]}, nil)
+		}
+		return iterator, nil
+	}
+}
+
+// prepStrictMocks is prepMocks without read-your-writes: as on a peer, the transaction reads the
+// state as it was before the transaction, while its writes go to the state. Prepare the mocks once
+// per transaction, after setting up the state.
+func prepStrictMocks(state worldState) (*mocks.TransactionContext, *mocks.ChaincodeStub) {
+	transactionContext, chaincodeStub := prepMocks(state)
+	snapshot := worldState{}
+	for key, value := range state {
+		snapshot[key] = value
+	}
+	chaincodeStub.GetStateStub = func(key string) ([]byte, error) {
+		return snapshot[key], nil
+	}
+	scanPartialCompositeKeys(snapshot, chaincodeStub)
+	return transactionContext, chaincodeStub
+}
+
+// enrollClients makes the client identity report the MSP ID of the organization that enrolled the
+// client it currently returns, given by client ID
+func enrollClients(clientIdentity *mocks.ClientIdentity, mspIDs map[string]string) {
+	clientIdentity.GetMSPIDStub = func() (string, error) {
+		clientID, err := clientIdentity.GetID()
+		return mspIDs[clientID], err
+	}
+}
+
+func prepMocks(state worldState) (*mocks.TransactionContext, *mocks.ChaincodeStub) {
+	chaincodeStub := &mocks.ChaincodeStub{}
+	chaincodeStub.GetStateStub = func(key string) ([]byte, error) {
+		return state[key], nil
+	}
+	chaincodeStub.PutStateStub = func(key string, value []byte) error {
+		state[key] = value
+		return nil
+	}
+	chaincodeStub.DelStateStub = func(key string) error {
+		delete(state, key)
+		return nil
+	}
+	chaincodeStub.GetPrivateDataStub = func(collection string, key string) ([]byte, error) {
+		return state[collection+"/"+key], nil
+	}
+	chaincodeStub.PutPrivateDataStub = func(collection string, key string, value []byte) error {
+		state[collection+"/"+key] = value
+		return nil
+	}
+	chaincodeStub.DelPrivateDataStub = func(collection string, key string) error {
+		delete(state, collection+"/"+key)
+		return nil
+	}
+	chaincodeStub.GetPrivateDataHashStub = func(collection string, key string) ([]byte, error) {
+		value, ok := state[collection+"/"+key]
+		if !ok {
+			return nil, nil
+		}
+		hash := sha256.Sum256(value)
+		return hash[:], nil
+	}
+	chaincodeStub.CreateCompositeKeyStub = shim.CreateCompositeKey
+	chaincodeStub.GetTxTimestampReturns(&timestamp.Timestamp{Seconds: 1600000000}, nil)
+	// assets are read together with their damages
+	scanPartialCompositeKeys(state, chaincodeStub)
+
+	transactionContext := &mocks.TransactionContext{}
+	transactionContext.GetStubReturns(chaincodeStub)
+	transactionContext.GetClientIdentityReturns(&mocks.ClientIdentity{})
+	return transactionContext, chaincodeStub
+}
@@ -0,0 +1,562 @@
+package chaincode
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+)
+
+// The protobuf messages of the protobuf state encoding, see protobufCodec. They are declared by
+// hand with struct tags, as there is no generated code in the chaincode. Field numbers are part of
+// the stored format: give new fields new numbers and never reuse or renumber existing ones.
+
+type auditProto struct {
+	CreatedBy  string               `protobuf:"bytes,1,opt,name=created_by,proto3"`
+	CreatedMSP string               `protobuf:"bytes,2,opt,name=created_msp,proto3"`
+	CreatedAt  *timestamp.Timestamp `protobuf:"bytes,3,opt,name=created_at,proto3"`
+	UpdatedBy  string               `protobuf:"bytes,4,opt,name=updated_by,proto3"`
+	UpdatedMSP string               `protobuf:"bytes,5,opt,name=updated_msp,proto3"`
+	UpdatedAt  *timestamp.Timestamp `protobuf:"bytes,6,opt,name=updated_at,proto3"`
+}
+
+func (m *auditProto) Reset()         { *m = auditProto{} }
+func (m *auditProto) String() string { return proto.CompactTextString(m) }
+func (*auditProto) ProtoMessage()    {}
+
+// shareProto holds a LiabilityShare or an OwnershipShare
+type shareProto struct {
+	UserID string `protobuf:"bytes,1,opt,name=user_id,proto3"`
+	Share  int64  `protobuf:"varint,2,opt,name=share,proto3"`
+}
+
+func (m *shareProto) Reset()         { *m = shareProto{} }
+func (m *shareProto) String() string { return proto.CompactTextString(m) }
+func (*shareProto) ProtoMessage()    {}
+
+type damageProto struct {
+	ID             string               `protobuf:"bytes,1,opt,name=id,proto3"`
+	AssetID        string               `protobuf:"bytes,2,opt,name=asset_id,proto3"`
+	Description    string               `protobuf:"bytes,3,opt,name=description,proto3"`
+	Cost           int64                `protobuf:"varint,4,opt,name=cost,proto3"`
+	Status         string               `protobuf:"bytes,5,opt,name=status,proto3"`
+	ReporterID     string               `protobuf:"bytes,6,opt,name=reporter_id,proto3"`
+	ReportedAt     *timestamp.Timestamp `protobuf:"bytes,7,opt,name=reported_at,proto3"`
+	RepairedBy     string               `protobuf:"bytes,8,opt,name=repaired_by,proto3"`
+	DocumentHashes []string             `protobuf:"bytes,9,rep,name=document_hashes,proto3"`
+	Liability      []*shareProto        `protobuf:"bytes,10,rep,name=liability,proto3"`
+	ClaimID        string               `protobuf:"bytes,11,opt,name=claim_id,proto3"`
+	CoveredAmount  int64                `protobuf:"varint,12,opt,name=covered_amount,proto3"`
+	AccidentID     string               `protobuf:"bytes,13,opt,name=accident_id,proto3"`
+	Audit          *auditProto          `protobuf:"bytes,14,opt,name=audit,proto3"`
+	RepairJobID    string               `protobuf:"bytes,15,opt,name=repair_job_id,proto3"`
+}
+
+func (m *damageProto) Reset()         { *m = damageProto{} }
+func (m *damageProto) String() string { return proto.CompactTextString(m) }
+func (*damageProto) ProtoMessage()    {}
+
+type factoryProto struct {
+	ManufacturerID string               `protobuf:"bytes,1,opt,name=manufacturer_id,proto3"`
+	ProductionDate *timestamp.Timestamp `protobuf:"bytes,2,opt,name=production_date,proto3"`
+	Engine         string               `protobuf:"bytes,3,opt,name=engine,proto3"`
+	PowerKW        int64                `protobuf:"varint,4,opt,name=power_kw,proto3"`
+	Transmission   string               `protobuf:"bytes,5,opt,name=transmission,proto3"`
+	FuelType       string               `protobuf:"bytes,6,opt,name=fuel_type,proto3"`
+	Seats          int64                `protobuf:"varint,7,opt,name=seats,proto3"`
+}
+
+func (m *factoryProto) Reset()         { *m = factoryProto{} }
+func (m *factoryProto) String() string { return proto.CompactTextString(m) }
+func (*factoryProto) ProtoMessage()    {}
+
+type batteryProto struct {
+	ID            string               `protobuf:"bytes,1,opt,name=id,proto3"`
+	AssetID       string               `protobuf:"bytes,2,opt,name=asset_id,proto3"`
+	ReporterID    string               `protobuf:"bytes,3,opt,name=reporter_id,proto3"`
+	StateOfHealth int64                `protobuf:"varint,4,opt,name=state_of_health,proto3"`
+	CapacityWh    int64                `protobuf:"varint,5,opt,name=capacity_wh,proto3"`
+	ReportedAt    *timestamp.Timestamp `protobuf:"bytes,6,opt,name=reported_at,proto3"`
+}
+
+func (m *batteryProto) Reset()         { *m = batteryProto{} }
+func (m *batteryProto) String() string { return proto.CompactTextString(m) }
+func (*batteryProto) ProtoMessage()    {}
+
+type assetProto struct {
+	DocType              string               `protobuf:"bytes,1,opt,name=doc_type,proto3"`
+	ID                   string               `protobuf:"bytes,2,opt,name=id,proto3"`
+	VIN                  string               `protobuf:"bytes,3,opt,name=vin,proto3"`
+	Plate                string               `protobuf:"bytes,4,opt,name=plate,proto3"`
+	Brand                string               `protobuf:"bytes,5,opt,name=brand,proto3"`
+	Model                string               `protobuf:"bytes,6,opt,name=model,proto3"`
+	Year                 int64                `protobuf:"varint,7,opt,name=year,proto3"`
+	Color                string               `protobuf:"bytes,8,opt,name=color,proto3"`
+	OwnerID              string               `protobuf:"bytes,9,opt,name=owner_id,proto3"`
+	Damages              []*damageProto       `protobuf:"bytes,10,rep,name=damages,proto3"`
+	AppraisedValue       int64                `protobuf:"varint,11,opt,name=appraised_value,proto3"`
+	Encumbered           bool                 `protobuf:"varint,12,opt,name=encumbered,proto3"`
+	Frozen               bool                 `protobuf:"varint,13,opt,name=frozen,proto3"`
+	FrozenReason         string               `protobuf:"bytes,14,opt,name=frozen_reason,proto3"`
+	Status               string               `protobuf:"bytes,15,opt,name=status,proto3"`
+	PolicyID             string               `protobuf:"bytes,16,opt,name=policy_id,proto3"`
+	Delegate             string               `protobuf:"bytes,17,opt,name=delegate,proto3"`
+	SeizureID            string               `protobuf:"bytes,18,opt,name=seizure_id,proto3"`
+	Stolen               bool                 `protobuf:"varint,19,opt,name=stolen,proto3"`
+	Jurisdiction         string               `protobuf:"bytes,20,opt,name=jurisdiction,proto3"`
+	ExportID             string               `protobuf:"bytes,21,opt,name=export_id,proto3"`
+	Recalls              []string             `protobuf:"bytes,22,rep,name=recalls,proto3"`
+	Mileage              int64                `protobuf:"varint,23,opt,name=mileage,proto3"`
+	MileageTampered      bool                 `protobuf:"varint,24,opt,name=mileage_tampered,proto3"`
+	WarrantyID           string               `protobuf:"bytes,25,opt,name=warranty_id,proto3"`
+	Factory              *factoryProto        `protobuf:"bytes,26,opt,name=factory,proto3"`
+	Battery              *batteryProto        `protobuf:"bytes,27,opt,name=battery,proto3"`
+	InspectionID         string               `protobuf:"bytes,28,opt,name=inspection_id,proto3"`
+	InspectionValidUntil *timestamp.Timestamp `protobuf:"bytes,29,opt,name=inspection_valid_until,proto3"`
+	EmissionsTestID      string               `protobuf:"bytes,30,opt,name=emissions_test_id,proto3"`
+	EmissionsValidUntil  *timestamp.Timestamp `protobuf:"bytes,31,opt,name=emissions_valid_until,proto3"`
+	AppraisalCollection  string               `protobuf:"bytes,32,opt,name=appraisal_collection,proto3"`
+	AppraisalHash        string               `protobuf:"bytes,33,opt,name=appraisal_hash,proto3"`
+	DetailsCollection    string               `protobuf:"bytes,34,opt,name=details_collection,proto3"`
+	DetailsHash          string               `protobuf:"bytes,35,opt,name=details_hash,proto3"`
+	CoOwners             []*shareProto        `protobuf:"bytes,36,rep,name=co_owners,proto3"`
+	SaleApprovals        []string             `protobuf:"bytes,37,rep,name=sale_approvals,proto3"`
+	SaleApprovalBuyer    string               `protobuf:"bytes,38,opt,name=sale_approval_buyer,proto3"`
+	ShareSupply          int64                `protobuf:"varint,39,opt,name=share_supply,proto3"`
+	BuyBackUserID        string               `protobuf:"bytes,40,opt,name=buy_back_user_id,proto3"`
+	BuyBackPrice         int64                `protobuf:"varint,41,opt,name=buy_back_price,proto3"`
+	BuyBackEscrow        int64                `protobuf:"varint,42,opt,name=buy_back_escrow,proto3"`
+	LienID               string               `protobuf:"bytes,43,opt,name=lien_id,proto3"`
+	LienholderID         string               `protobuf:"bytes,44,opt,name=lienholder_id,proto3"`
+	LienTransferApproval string               `protobuf:"bytes,45,opt,name=lien_transfer_approval,proto3"`
+	RegistrationID       string               `protobuf:"bytes,46,opt,name=registration_id,proto3"`
+	NoteCollection       string               `protobuf:"bytes,47,opt,name=note_collection,proto3"`
+	Audit                *auditProto          `protobuf:"bytes,48,opt,name=audit,proto3"`
+	Version              int64                `protobuf:"varint,49,opt,name=version,proto3"`
+	AuctionID            string               `protobuf:"bytes,50,opt,name=auction_id,proto3"`
+}
+
+func (m *assetProto) Reset()         { *m = assetProto{} }
+func (m *assetProto) String() string { return proto.CompactTextString(m) }
+func (*assetProto) ProtoMessage()    {}
+
+type userProto struct {
+	ID            string      `protobuf:"bytes,1,opt,name=id,proto3"`
+	Name          string      `protobuf:"bytes,2,opt,name=name,proto3"`
+	Lastname      string      `protobuf:"bytes,3,opt,name=lastname,proto3"`
+	Email         string      `protobuf:"bytes,4,opt,name=email,proto3"`
+	Money         int64       `protobuf:"varint,5,opt,name=money,proto3"`
+	Status        string      `protobuf:"bytes,6,opt,name=status,proto3"`
+	Roles         []string    `protobuf:"bytes,7,rep,name=roles,proto3"`
+	Identity      string      `protobuf:"bytes,8,opt,name=identity,proto3"`
+	MSPID         string      `protobuf:"bytes,9,opt,name=msp_id,proto3"`
+	KYCVerified   bool        `protobuf:"varint,10,opt,name=kyc_verified,proto3"`
+	Jurisdiction  string      `protobuf:"bytes,11,opt,name=jurisdiction,proto3"`
+	PIICollection string      `protobuf:"bytes,12,opt,name=pii_collection,proto3"`
+	PIIHash       string      `protobuf:"bytes,13,opt,name=pii_hash,proto3"`
+	EmailHash     string      `protobuf:"bytes,14,opt,name=email_hash,proto3"`
+	PIIPurged     bool        `protobuf:"varint,15,opt,name=pii_purged,proto3"`
+	Audit         *auditProto `protobuf:"bytes,16,opt,name=audit,proto3"`
+	Version       int64       `protobuf:"varint,17,opt,name=version,proto3"`
+	FrozenReason  string      `protobuf:"bytes,18,opt,name=frozen_reason,proto3"`
+	HotAccount    bool        `protobuf:"varint,19,opt,name=hot_account,proto3"`
+	DailyLimit    int64       `protobuf:"varint,20,opt,name=daily_limit,proto3"`
+	DailySpent    int64       `protobuf:"varint,21,opt,name=daily_spent,proto3"`
+	SpentDay      string      `protobuf:"bytes,22,opt,name=spent_day,proto3"`
+}
+
+func (m *userProto) Reset()         { *m = userProto{} }
+func (m *userProto) String() string { return proto.CompactTextString(m) }
+func (*userProto) ProtoMessage()    {}
+
+// assetToProto converts the asset to its protobuf message
+func assetToProto(asset *Asset) (*assetProto, error) {
+	message := &assetProto{
+		DocType:              asset.DocType,
+		ID:                   asset.ID,
+		VIN:                  asset.VIN,
+		Plate:                asset.Plate,
+		Brand:                asset.Brand,
+		Model:                asset.Model,
+		Year:                 int64(asset.Year),
+		Color:                asset.Color,
+		OwnerID:              asset.OwnerID,
+		AppraisedValue:       asset.AppraisedValue,
+		Encumbered:           asset.Encumbered,
+		Frozen:               asset.Frozen,
+		FrozenReason:         asset.FrozenReason,
+		Status:               asset.Status,
+		PolicyID:             asset.PolicyID,
+		Delegate:             asset.Delegate,
+		SeizureID:            asset.SeizureID,
+		Stolen:               asset.Stolen,
+		Jurisdiction:         asset.Jurisdiction,
+		ExportID:             asset.ExportID,
+		Recalls:              asset.Recalls,
+		Mileage:              asset.Mileage,
+		MileageTampered:      asset.MileageTampered,
+		WarrantyID:           asset.WarrantyID,
+		InspectionID:         asset.InspectionID,
+		EmissionsTestID:      asset.EmissionsTestID,
+		AppraisalCollection:  asset.AppraisalCollection,
+		AppraisalHash:        asset.AppraisalHash,
+		DetailsCollection:    asset.DetailsCollection,
+		DetailsHash:          asset.DetailsHash,
+		CoOwners:             ownershipSharesToProto(asset.CoOwners),
+		SaleApprovals:        asset.SaleApprovals,
+		SaleApprovalBuyer:    asset.SaleApprovalBuyer,
+		ShareSupply:          asset.ShareSupply,
+		BuyBackUserID:        asset.BuyBackUserID,
+		BuyBackPrice:         asset.BuyBackPrice,
+		BuyBackEscrow:        asset.BuyBackEscrow,
+		LienID:               asset.LienID,
+		LienholderID:         asset.LienholderID,
+		LienTransferApproval: asset.LienTransferApproval,
+		RegistrationID:       asset.RegistrationID,
+		NoteCollection:       asset.NoteCollection,
+		Version:              asset.Version,
+		AuctionID:            asset.AuctionID,
+	}
+	var err error
+	for i := range asset.Damages {
+		damage, err := damageToProto(&asset.Damages[i])
+		if err != nil {
+			return nil, err
+		}
+		message.Damages = append(message.Damages, damage)
+	}
+	if asset.Factory != nil {
+		message.Factory = &factoryProto{
+			ManufacturerID: asset.Factory.ManufacturerID,
+			Engine:         asset.Factory.Specs.Engine,
+			PowerKW:        int64(asset.Factory.Specs.PowerKW),
+			Transmission:   asset.Factory.Specs.Transmission,
+			FuelType:       asset.Factory.Specs.FuelType,
+			Seats:          int64(asset.Factory.Specs.Seats),
+		}
+		message.Factory.ProductionDate, err = timeToProto(asset.Factory.ProductionDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if asset.Battery != nil {
+		message.Battery = &batteryProto{
+			ID:            asset.Battery.ID,
+			AssetID:       asset.Battery.AssetID,
+			ReporterID:    asset.Battery.ReporterID,
+			StateOfHealth: int64(asset.Battery.StateOfHealth),
+			CapacityWh:    asset.Battery.CapacityWh,
+		}
+		message.Battery.ReportedAt, err = timeToProto(asset.Battery.ReportedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	message.InspectionValidUntil, err = timeToProto(asset.InspectionValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	message.EmissionsValidUntil, err = timeToProto(asset.EmissionsValidUntil)
+	if err != nil {
+		return nil, err
+	}
+	message.Audit, err = auditToProto(&asset.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// assetFromProto fills the asset from its protobuf message
+func assetFromProto(message *assetProto, asset *Asset) error {
+	*asset = Asset{
+		DocType:              message.DocType,
+		ID:                   message.ID,
+		VIN:                  message.VIN,
+		Plate:                message.Plate,
+		Brand:                message.Brand,
+		Model:                message.Model,
+		Year:                 int(message.Year),
+		Color:                message.Color,
+		OwnerID:              message.OwnerID,
+		AppraisedValue:       message.AppraisedValue,
+		Encumbered:           message.Encumbered,
+		Frozen:               message.Frozen,
+		FrozenReason:         message.FrozenReason,
+		Status:               message.Status,
+		PolicyID:             message.PolicyID,
+		Delegate:             message.Delegate,
+		SeizureID:            message.SeizureID,
+		Stolen:               message.Stolen,
+		Jurisdiction:         message.Jurisdiction,
+		ExportID:             message.ExportID,
+		Recalls:              message.Recalls,
+		Mileage:              message.Mileage,
+		MileageTampered:      message.MileageTampered,
+		WarrantyID:           message.WarrantyID,
+		InspectionID:         message.InspectionID,
+		EmissionsTestID:      message.EmissionsTestID,
+		AppraisalCollection:  message.AppraisalCollection,
+		AppraisalHash:        message.AppraisalHash,
+		DetailsCollection:    message.DetailsCollection,
+		DetailsHash:          message.DetailsHash,
+		SaleApprovals:        message.SaleApprovals,
+		SaleApprovalBuyer:    message.SaleApprovalBuyer,
+		ShareSupply:          message.ShareSupply,
+		BuyBackUserID:        message.BuyBackUserID,
+		BuyBackPrice:         message.BuyBackPrice,
+		BuyBackEscrow:        message.BuyBackEscrow,
+		LienID:               message.LienID,
+		LienholderID:         message.LienholderID,
+		LienTransferApproval: message.LienTransferApproval,
+		RegistrationID:       message.RegistrationID,
+		NoteCollection:       message.NoteCollection,
+		Version:              message.Version,
+		AuctionID:            message.AuctionID,
+	}
+	for _, share := range message.CoOwners {
+		asset.CoOwners = append(asset.CoOwners, OwnershipShare{UserID: share.UserID, Share: share.Share})
+	}
+	var err error
+	for _, stored := range message.Damages {
+		var damage Damage
+		err = damageFromProto(stored, &damage)
+		if err != nil {
+			return err
+		}
+		asset.Damages = append(asset.Damages, damage)
+	}
+	if message.Factory != nil {
+		asset.Factory = &FactoryData{
+			ManufacturerID: message.Factory.ManufacturerID,
+			Specs: FactorySpecs{
+				Engine:       message.Factory.Engine,
+				PowerKW:      int(message.Factory.PowerKW),
+				Transmission: message.Factory.Transmission,
+				FuelType:     message.Factory.FuelType,
+				Seats:        int(message.Factory.Seats),
+			},
+		}
+		asset.Factory.ProductionDate, err = timeFromProto(message.Factory.ProductionDate)
+		if err != nil {
+			return err
+		}
+	}
+	if message.Battery != nil {
+		asset.Battery = &BatteryReport{
+			ID:            message.Battery.ID,
+			AssetID:       message.Battery.AssetID,
+			ReporterID:    message.Battery.ReporterID,
+			StateOfHealth: int(message.Battery.StateOfHealth),
+			CapacityWh:    message.Battery.CapacityWh,
+		}
+		asset.Battery.ReportedAt, err = timeFromProto(message.Battery.ReportedAt)
+		if err != nil {
+			return err
+		}
+	}
+	asset.InspectionValidUntil, err = timeFromProto(message.InspectionValidUntil)
+	if err != nil {
+		return err
+	}
+	asset.EmissionsValidUntil, err = timeFromProto(message.EmissionsValidUntil)
+	if err != nil {
+		return err
+	}
+
+	return auditFromProto(message.Audit, &asset.Audit)
+}
+
+// userToProto converts the user to its protobuf message
+func userToProto(user *User) (*userProto, error) {
+	audit, err := auditToProto(&user.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &userProto{
+		ID:            user.ID,
+		Name:          user.Name,
+		Lastname:      user.Lastname,
+		Email:         user.Email,
+		Money:         user.Money,
+		Status:        user.Status,
+		Roles:         user.Roles,
+		Identity:      user.Identity,
+		MSPID:         user.MSPID,
+		KYCVerified:   user.KYCVerified,
+		Jurisdiction:  user.Jurisdiction,
+		PIICollection: user.PIICollection,
+		PIIHash:       user.PIIHash,
+		EmailHash:     user.EmailHash,
+		PIIPurged:     user.PIIPurged,
+		Audit:         audit,
+		Version:       user.Version,
+		FrozenReason:  user.FrozenReason,
+		HotAccount:    user.HotAccount,
+		DailyLimit:    user.DailyLimit,
+		DailySpent:    user.DailySpent,
+		SpentDay:      user.SpentDay,
+	}, nil
+}
+
+// userFromProto fills the user from its protobuf message
+func userFromProto(message *userProto, user *User) error {
+	*user = User{
+		ID:            message.ID,
+		Name:          message.Name,
+		Lastname:      message.Lastname,
+		Email:         message.Email,
+		Money:         message.Money,
+		Status:        message.Status,
+		Roles:         message.Roles,
+		Identity:      message.Identity,
+		MSPID:         message.MSPID,
+		KYCVerified:   message.KYCVerified,
+		Jurisdiction:  message.Jurisdiction,
+		PIICollection: message.PIICollection,
+		PIIHash:       message.PIIHash,
+		EmailHash:     message.EmailHash,
+		PIIPurged:     message.PIIPurged,
+		Version:       message.Version,
+		FrozenReason:  message.FrozenReason,
+		HotAccount:    message.HotAccount,
+		DailyLimit:    message.DailyLimit,
+		DailySpent:    message.DailySpent,
+		SpentDay:      message.SpentDay,
+	}
+	return auditFromProto(message.Audit, &user.Audit)
+}
+
+// damageToProto converts the damage to its protobuf message
+func damageToProto(damage *Damage) (*damageProto, error) {
+	message := &damageProto{
+		ID:             damage.ID,
+		AssetID:        damage.AssetID,
+		Description:    damage.Description,
+		Cost:           damage.Cost,
+		Status:         damage.Status,
+		ReporterID:     damage.ReporterID,
+		RepairedBy:     damage.RepairedBy,
+		DocumentHashes: damage.DocumentHashes,
+		ClaimID:        damage.ClaimID,
+		CoveredAmount:  damage.CoveredAmount,
+		AccidentID:     damage.AccidentID,
+		RepairJobID:    damage.RepairJobID,
+	}
+	for _, share := range damage.Liability {
+		message.Liability = append(message.Liability, &shareProto{UserID: share.UserID, Share: share.Share})
+	}
+	var err error
+	message.ReportedAt, err = timeToProto(damage.ReportedAt)
+	if err != nil {
+		return nil, err
+	}
+	message.Audit, err = auditToProto(&damage.Audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// damageFromProto fills the damage from its protobuf message
+func damageFromProto(message *damageProto, damage *Damage) error {
+	*damage = Damage{
+		ID:             message.ID,
+		AssetID:        message.AssetID,
+		Description:    message.Description,
+		Cost:           message.Cost,
+		Status:         message.Status,
+		ReporterID:     message.ReporterID,
+		RepairedBy:     message.RepairedBy,
+		DocumentHashes: message.DocumentHashes,
+		ClaimID:        message.ClaimID,
+		CoveredAmount:  message.CoveredAmount,
+		AccidentID:     message.AccidentID,
+		RepairJobID:    message.RepairJobID,
+	}
+	for _, share := range message.Liability {
+		damage.Liability = append(damage.Liability, LiabilityShare{UserID: share.UserID, Share: share.Share})
+	}
+	var err error
+	damage.ReportedAt, err = timeFromProto(message.ReportedAt)
+	if err != nil {
+		return err
+	}
+
+	return auditFromProto(message.Audit, &damage.Audit)
+}
+
+// ownershipSharesToProto converts the co-owner shares to their protobuf messages
+func ownershipSharesToProto(shares []OwnershipShare) []*shareProto {
+	var messages []*shareProto
+	for _, share := range shares {
+		messages = append(messages, &shareProto{UserID: share.UserID, Share: share.Share})
+	}
+
+	return messages
+}
+
+// auditToProto converts the audit stamps to their protobuf message
+func auditToProto(audit *Audit) (*auditProto, error) {
+	message := &auditProto{
+		CreatedBy:  audit.CreatedBy,
+		CreatedMSP: audit.CreatedMSP,
+		UpdatedBy:  audit.UpdatedBy,
+		UpdatedMSP: audit.UpdatedMSP,
+	}
+	var err error
+	message.CreatedAt, err = timeToProto(audit.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	message.UpdatedAt, err = timeToProto(audit.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// auditFromProto fills the audit stamps from their protobuf message
+func auditFromProto(message *auditProto, audit *Audit) error {
+	if message == nil {
+		*audit = Audit{}
+		return nil
+	}
+	*audit = Audit{
+		CreatedBy:  message.CreatedBy,
+		CreatedMSP: message.CreatedMSP,
+		UpdatedBy:  message.UpdatedBy,
+		UpdatedMSP: message.UpdatedMSP,
+	}
+	var err error
+	audit.CreatedAt, err = timeFromProto(message.CreatedAt)
+	if err != nil {
+		return err
+	}
+	audit.UpdatedAt, err = timeFromProto(message.UpdatedAt)
+
+	return err
+}
+
+// timeToProto converts the time to a protobuf timestamp, nil for the zero time
+func timeToProto(t time.Time) (*timestamp.Timestamp, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return ptypes.TimestampProto(t)
+}
+
+// timeFromProto converts a protobuf timestamp to a UTC time, the zero time for nil
+func timeFromProto(ts *timestamp.Timestamp) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+
+	return ptypes.Timestamp(ts)
+}
@@ -0,0 +1,118 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const stolenIndexName = "stolen~asset"
+
+// ReportStolen lets the owner of the asset or the police report it stolen. Until recovered the
+// asset cannot be transferred, listed or paid for repairs.
+func (s *SmartContract) ReportStolen(ctx contractapi.TransactionContextInterface, assetID string, reporterID string) error {
+	asset, err := s.readReportedAsset(ctx, assetID, reporterID)
+	if err != nil {
+		return err
+	}
+	if asset.Stolen {
+		return fmt.Errorf("the asset %s is already reported stolen", assetID)
+	}
+
+	asset.Stolen = true
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	indexKey, err := ctx.GetStub().CreateCompositeKey(stolenIndexName, []string{assetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().PutState(indexKey, []byte(asset.VIN))
+	if err != nil {
+		return err
+	}
+
+	return setStolenEvent(ctx, "AssetStolen", asset, reporterID)
+}
+
+// ReportRecovered lets the owner of the asset or the police report a stolen asset recovered
+func (s *SmartContract) ReportRecovered(ctx contractapi.TransactionContextInterface, assetID string, reporterID string) error {
+	asset, err := s.readReportedAsset(ctx, assetID, reporterID)
+	if err != nil {
+		return err
+	}
+	if !asset.Stolen {
+		return fmt.Errorf("the asset %s is not reported stolen", assetID)
+	}
+
+	asset.Stolen = false
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return err
+	}
+	indexKey, err := ctx.GetStub().CreateCompositeKey(stolenIndexName, []string{assetID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	err = ctx.GetStub().DelState(indexKey)
+	if err != nil {
+		return err
+	}
+
+	return setStolenEvent(ctx, "AssetRecovered", asset, reporterID)
+}
+
+// GetStolenVINs returns the VINs of all assets currently reported stolen
+func (s *SmartContract) GetStolenVINs(ctx contractapi.TransactionContextInterface) ([]string, error) {
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(stolenIndexName, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	vins := []string{}
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		if len(queryResponse.Value) > 0 {
+			vins = append(vins, string(queryResponse.Value))
+		}
+	}
+
+	return vins, nil
+}
+
+// readReportedAsset returns the asset after checking the reporter is its owner or the police
+func (s *SmartContract) readReportedAsset(ctx contractapi.TransactionContextInterface, assetID string, reporterID string) (*Asset, error) {
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	reporter, err := s.ReadUser(ctx, reporterID)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyUserIdentity(ctx, reporter)
+	if err != nil {
+		return nil, err
+	}
+	if reporter.ID != asset.OwnerID && !hasRole(reporter, RolePolice) {
+		return nil, fmt.Errorf("only the owner or the police can report asset %s", assetID)
+	}
+
+	return asset, nil
+}
+
+// setStolenEvent emits an event recording the change of the asset's stolen flag
+func setStolenEvent(ctx contractapi.TransactionContextInterface, name string, asset *Asset, reporterID string) error {
+	eventJSON, err := json.Marshal(StolenEvent{AssetID: asset.ID, VIN: asset.VIN, Stolen: asset.Stolen, ReporterID: reporterID})
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().SetEvent(name, eventJSON)
+}
@@ -0,0 +1,46 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportStolen(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	scanPartialCompositeKeys(state, chaincodeStub)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Roles: []string{chaincode.RolePolice}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", VIN: "1HGCM82633A004352", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", VIN: "1M8GDM9AXKP042788", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.ReportStolen(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "only the owner or the police can report asset asset1")
+	err = assetTransfer.ReportStolen(transactionContext, "asset1", "user1")
+	require.NoError(t, err)
+	err = assetTransfer.ReportStolen(transactionContext, "asset2", "user3")
+	require.NoError(t, err)
+	name, _ := chaincodeStub.SetEventArgsForCall(1)
+	require.Equal(t, "AssetStolen", name)
+
+	vins, err := assetTransfer.GetStolenVINs(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1HGCM82633A004352", "1M8GDM9AXKP042788"}, vins)
+
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.EqualError(t, err, "ASSET_STOLEN: the asset asset1 is reported stolen")
+	err = assetTransfer.ListForSale(transactionContext, "asset1", 1000, "")
+	require.EqualError(t, err, "ASSET_STOLEN: the asset asset1 is reported stolen")
+
+	err = assetTransfer.ReportRecovered(transactionContext, "asset1", "user3")
+	require.NoError(t, err)
+	vins, err = assetTransfer.GetStolenVINs(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, []string{"1M8GDM9AXKP042788"}, vins)
+	err = assetTransfer.GiftAsset(transactionContext, "asset1", "user2")
+	require.NoError(t, err)
+}
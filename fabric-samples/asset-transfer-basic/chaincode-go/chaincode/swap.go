@@ -0,0 +1,161 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const swapObjectType = "swap"
+
+// ProposeSwap lets the owner of proposerAssetID propose exchanging it for counterpartyAssetID.
+// A positive topUp is paid by the proposer, a negative one by the counterparty.
+// It returns the ID of the new swap.
+func (s *SmartContract) ProposeSwap(ctx contractapi.TransactionContextInterface, proposerAssetID string, counterpartyAssetID string, topUp int64) (string, error) {
+	proposerAsset, err := s.ReadAsset(ctx, proposerAssetID)
+	if err != nil {
+		return "", err
+	}
+	counterpartyAsset, err := s.ReadAsset(ctx, counterpartyAssetID)
+	if err != nil {
+		return "", err
+	}
+	if proposerAsset.OwnerID == counterpartyAsset.OwnerID {
+		return "", fmt.Errorf("both assets are owned by user %s", proposerAsset.OwnerID)
+	}
+	proposer, err := s.ReadUser(ctx, proposerAsset.OwnerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, proposer)
+	if err != nil {
+		return "", err
+	}
+
+	swap := Swap{
+		ID:                  ctx.GetStub().GetTxID(),
+		ProposerID:          proposer.ID,
+		ProposerAssetID:     proposerAssetID,
+		CounterpartyID:      counterpartyAsset.OwnerID,
+		CounterpartyAssetID: counterpartyAssetID,
+		TopUp:               topUp,
+		Status:              SwapProposed,
+	}
+	err = putSwap(ctx, &swap)
+	if err != nil {
+		return "", err
+	}
+
+	return swap.ID, nil
+}
+
+// AcceptSwap lets the counterparty accept a proposed swap, exchanging both assets and paying the top-up.
+func (s *SmartContract) AcceptSwap(ctx contractapi.TransactionContextInterface, swapID string) error {
+	swap, err := s.ReadSwap(ctx, swapID)
+	if err != nil {
+		return err
+	}
+	if swap.Status != SwapProposed {
+		return fmt.Errorf("the swap %s is %s", swapID, swap.Status)
+	}
+	counterparty, err := s.ReadUser(ctx, swap.CounterpartyID)
+	if err != nil {
+		return err
+	}
+	err = verifyUserIdentity(ctx, counterparty)
+	if err != nil {
+		return err
+	}
+	proposer, err := s.ReadUser(ctx, swap.ProposerID)
+	if err != nil {
+		return err
+	}
+	proposerAsset, err := s.ReadAsset(ctx, swap.ProposerAssetID)
+	if err != nil {
+		return err
+	}
+	counterpartyAsset, err := s.ReadAsset(ctx, swap.CounterpartyAssetID)
+	if err != nil {
+		return err
+	}
+
+	// the top-up is settled as the price of the asset received by the paying party
+	proposerAssetPrice, counterpartyAssetPrice := int64(0), swap.TopUp
+	if swap.TopUp < 0 {
+		proposerAssetPrice, counterpartyAssetPrice = -swap.TopUp, 0
+	}
+	err = executeSale(ctx, proposerAsset, proposer, counterparty, proposerAssetPrice)
+	if err != nil {
+		return err
+	}
+	err = executeSale(ctx, counterpartyAsset, counterparty, proposer, counterpartyAssetPrice)
+	if err != nil {
+		return err
+	}
+
+	swap.Status = SwapCompleted
+	return putSwap(ctx, swap)
+}
+
+// CancelSwap lets either party withdraw a proposed swap.
+func (s *SmartContract) CancelSwap(ctx contractapi.TransactionContextInterface, swapID string) error {
+	swap, err := s.ReadSwap(ctx, swapID)
+	if err != nil {
+		return err
+	}
+	if swap.Status != SwapProposed {
+		return fmt.Errorf("the swap %s is %s", swapID, swap.Status)
+	}
+	proposer, err := s.ReadUser(ctx, swap.ProposerID)
+	if err != nil {
+		return err
+	}
+	counterparty, err := s.ReadUser(ctx, swap.CounterpartyID)
+	if err != nil {
+		return err
+	}
+	if verifyUserIdentity(ctx, proposer) != nil && verifyUserIdentity(ctx, counterparty) != nil {
+		return fmt.Errorf("only the proposer or the counterparty can cancel swap %s", swapID)
+	}
+
+	swap.Status = SwapCancelled
+	return putSwap(ctx, swap)
+}
+
+// ReadSwap returns the swap stored in the world state with given id.
+func (s *SmartContract) ReadSwap(ctx contractapi.TransactionContextInterface, swapID string) (*Swap, error) {
+	swapKey, err := ctx.GetStub().CreateCompositeKey(swapObjectType, []string{swapID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	swapJSON, err := ctx.GetStub().GetState(swapKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if swapJSON == nil {
+		return nil, fmt.Errorf("the swap %s does not exist", swapID)
+	}
+
+	var swap Swap
+	err = json.Unmarshal(swapJSON, &swap)
+	if err != nil {
+		return nil, err
+	}
+
+	return &swap, nil
+}
+
+// putSwap writes the given swap to the world state
+func putSwap(ctx contractapi.TransactionContextInterface, swap *Swap) error {
+	swapKey, err := ctx.GetStub().CreateCompositeKey(swapObjectType, []string{swap.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	swapJSON, err := json.Marshal(swap)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(swapKey, swapJSON)
+}
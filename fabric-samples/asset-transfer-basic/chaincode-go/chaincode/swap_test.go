@@ -0,0 +1,45 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwap(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	chaincodeStub.GetTxIDReturns("swap1")
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "a"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 1000, Identity: "b"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user2"})
+
+	assetTransfer := chaincode.SmartContract{}
+	clientIdentity.GetIDReturns("a", nil)
+	swapID, err := assetTransfer.ProposeSwap(transactionContext, "asset1", "asset2", 300)
+	require.NoError(t, err)
+
+	err = assetTransfer.AcceptSwap(transactionContext, swapID)
+	require.EqualError(t, err, "submitting client is not authorized to act for user user2")
+
+	clientIdentity.GetIDReturns("b", nil)
+	err = assetTransfer.AcceptSwap(transactionContext, swapID)
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "user2", asset.OwnerID)
+	state.get(t, "asset2", asset)
+	require.Equal(t, "user1", asset.OwnerID)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(700), user.Money)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(1300), user.Money)
+
+	err = assetTransfer.CancelSwap(transactionContext, swapID)
+	require.EqualError(t, err, "the swap swap1 is completed")
+}
@@ -0,0 +1,55 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const (
+	userTransientKey  = "user"
+	offerTransientKey = "offer"
+)
+
+// CreateUserFromTransient works like CreateUser but takes the UserInput as JSON from the user
+// transient field, so the personal details and balance are not recorded in the transaction arguments.
+func (s *SmartContract) CreateUserFromTransient(ctx contractapi.TransactionContextInterface) error {
+	var input UserInput
+	err := readTransientJSON(ctx, userTransientKey, &input)
+	if err != nil {
+		return err
+	}
+
+	return s.CreateUser(ctx, input.ID, input.Name, input.Lastname, input.Email, input.InitialBalance)
+}
+
+// OfferAssetFromTransient works like OfferAsset but takes the price as an OfferInput from the offer
+// transient field, so it is not recorded in the transaction arguments.
+func (s *SmartContract) OfferAssetFromTransient(ctx contractapi.TransactionContextInterface, assetID string, buyerID string, dealerID string) (string, error) {
+	var input OfferInput
+	err := readTransientJSON(ctx, offerTransientKey, &input)
+	if err != nil {
+		return "", err
+	}
+
+	return s.OfferAsset(ctx, assetID, buyerID, input.Price, dealerID)
+}
+
+// readTransientJSON decodes the JSON passed in the transient field with given key into v
+func readTransientJSON(ctx contractapi.TransactionContextInterface, key string, v interface{}) error {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf("error getting transient: %v", err)
+	}
+	valueJSON, ok := transientMap[key]
+	if !ok {
+		return fmt.Errorf("the %s must be passed in the %s transient field", key, key)
+	}
+	err = json.Unmarshal(valueJSON, v)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal %s: %v", key, err)
+	}
+
+	return nil
+}
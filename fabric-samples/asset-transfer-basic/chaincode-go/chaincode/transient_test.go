@@ -0,0 +1,52 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserFromTransient(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.CreateUserFromTransient(transactionContext)
+	require.EqualError(t, err, "the user must be passed in the user transient field")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"user": []byte(`{"ID":"user4","name":"Milan","lastname":"Milanovic","email":"milan.milanovic@email.com","initialBalance":5600}`)}, nil)
+	err = assetTransfer.CreateUserFromTransient(transactionContext)
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user4", user)
+	require.Equal(t, "Milan", user.Name)
+	require.Equal(t, int64(5600), user.Money)
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"user": []byte(`{"ID":"user5","name":"Milan","lastname":"Milanovic","email":"milan@email.com","initialBalance":-1}`)}, nil)
+	err = assetTransfer.CreateUserFromTransient(transactionContext)
+	require.EqualError(t, err, "initial balance must not be negative")
+}
+
+func TestOfferAssetFromTransient(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	clientIdentity.GetIDReturns("seller", nil)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Identity: "seller"})
+	state.put(t, "user2", &chaincode.User{ID: "user2"})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+	chaincodeStub.GetTxIDReturns("offer1")
+
+	assetTransfer := chaincode.SmartContract{}
+	_, err := assetTransfer.OfferAssetFromTransient(transactionContext, "asset1", "user2", "")
+	require.EqualError(t, err, "the offer must be passed in the offer transient field")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"offer": []byte(`{"price":2500}`)}, nil)
+	offerID, err := assetTransfer.OfferAssetFromTransient(transactionContext, "asset1", "user2", "")
+	require.NoError(t, err)
+	offer, err := assetTransfer.ReadOffer(transactionContext, offerID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2500), offer.Price)
+}
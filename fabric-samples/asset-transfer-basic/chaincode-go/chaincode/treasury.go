@@ -0,0 +1,119 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// TreasuryUserID is the account that collects transfer tax
+const TreasuryUserID = "user_treasury"
+
+const transferTaxRateConfig = "transferTaxRate"
+
+// SetTransferTaxRate sets the share of every sale paid to the treasury, in basis points (1/100 of a percent).
+// Only admins may change the rate.
+func (s *SmartContract) SetTransferTaxRate(ctx contractapi.TransactionContextInterface, basisPoints int64) error {
+	err := requireAdmin(ctx)
+	if err != nil {
+		return err
+	}
+	if basisPoints < 0 || basisPoints > 10000 {
+		return fmt.Errorf("tax rate must be between 0 and 10000 basis points")
+	}
+
+	return putConfigInt(ctx, transferTaxRateConfig, basisPoints)
+}
+
+// GetTreasuryReport returns the treasury balance, the current tax rate and the tax collected so far
+func (s *SmartContract) GetTreasuryReport(ctx contractapi.TransactionContextInterface) (*TreasuryReport, error) {
+	rate, err := getConfigInt(ctx, transferTaxRateConfig, 0)
+	if err != nil {
+		return nil, err
+	}
+	treasury, err := readTreasury(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := TreasuryReport{Balance: treasury.Money, TaxRate: rate}
+
+	resultsIterator, err := ctx.GetStub().GetStateByPartialCompositeKey(transferObjectType, []string{})
+	if err != nil {
+		return nil, err
+	}
+	defer resultsIterator.Close()
+
+	for resultsIterator.HasNext() {
+		queryResponse, err := resultsIterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		var transfer TransferRecord
+		err = json.Unmarshal(queryResponse.Value, &transfer)
+		if err != nil {
+			return nil, err
+		}
+		if transfer.Tax > 0 {
+			report.TotalCollected = report.TotalCollected + transfer.Tax
+			report.TaxedSales++
+		}
+	}
+
+	return &report, nil
+}
+
+// collectTransferTax takes the transfer tax on amount from the seller's proceeds and credits it to
+// the treasury. It returns the tax collected.
+func collectTransferTax(ctx contractapi.TransactionContextInterface, amount int64) (int64, error) {
+	tax, err := transferTax(ctx, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	return tax, creditTreasury(ctx, tax)
+}
+
+// transferTax returns the transfer tax due on amount
+func transferTax(ctx contractapi.TransactionContextInterface, amount int64) (int64, error) {
+	rate, err := getConfigInt(ctx, transferTaxRateConfig, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	return amount * rate / 10000, nil
+}
+
+// creditTreasury adds the collected tax to the treasury account
+func creditTreasury(ctx contractapi.TransactionContextInterface, tax int64) error {
+	if tax == 0 {
+		return nil
+	}
+	treasury, err := readTreasury(ctx)
+	if err != nil {
+		return err
+	}
+
+	treasury.Money = treasury.Money + tax
+	return putUser(ctx, treasury)
+}
+
+// readTreasury returns the treasury account, creating it on first use
+func readTreasury(ctx contractapi.TransactionContextInterface) (*User, error) {
+	treasuryJSON, err := ctx.GetStub().GetState(TreasuryUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if treasuryJSON == nil {
+		return &User{ID: TreasuryUserID, Name: "Treasury", Status: UserActive, Roles: []string{}}, nil
+	}
+
+	var treasury User
+	err = decodeUser(treasuryJSON, &treasury)
+	if err != nil {
+		return nil, err
+	}
+
+	return &treasury, nil
+}
@@ -0,0 +1,46 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/queryresult"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferTax(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	chaincodeStub.GetTxIDReturns("tx1")
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1"})
+
+	assetTransfer := chaincode.SmartContract{}
+	err := assetTransfer.SetTransferTaxRate(transactionContext, 10001)
+	require.EqualError(t, err, "tax rate must be between 0 and 10000 basis points")
+	err = assetTransfer.SetTransferTaxRate(transactionContext, 250)
+	require.NoError(t, err)
+
+	err = assetTransfer.TransferAsset(transactionContext, "asset1", "user2", false, 4000)
+	require.NoError(t, err)
+	seller := &chaincode.User{}
+	state.get(t, "user1", seller)
+	require.Equal(t, int64(3900), seller.Money)
+	buyer := &chaincode.User{}
+	state.get(t, "user2", buyer)
+	require.Equal(t, int64(1000), buyer.Money)
+	treasury := &chaincode.User{}
+	state.get(t, chaincode.TreasuryUserID, treasury)
+	require.Equal(t, int64(100), treasury.Money)
+
+	iterator := &mocks.StateQueryIterator{}
+	iterator.HasNextReturnsOnCall(0, true)
+	iterator.HasNextReturnsOnCall(1, false)
+	iterator.NextReturns(&queryresult.KV{Value: state["\x00transfer\x00asset1\x00tx1\x00"]}, nil)
+	chaincodeStub.GetStateByPartialCompositeKeyReturns(iterator, nil)
+	report, err := assetTransfer.GetTreasuryReport(transactionContext)
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.TreasuryReport{Balance: 100, TaxRate: 250, TotalCollected: 100, TaxedSales: 1}, report)
+}
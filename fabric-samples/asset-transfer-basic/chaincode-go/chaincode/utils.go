@@ -0,0 +1,34 @@
+package chaincode
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// txTime returns the timestamp of the transaction proposal, which is the same on every endorsing peer
+func txTime(ctx contractapi.TransactionContextInterface) (time.Time, error) {
+	timestamp, err := ctx.GetStub().GetTxTimestamp()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get transaction timestamp: %v", err)
+	}
+	txTime, err := ptypes.Timestamp(timestamp)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid transaction timestamp: %v", err)
+	}
+
+	return txTime, nil
+}
+
+// contains returns true when value is one of values
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,43 @@
+package chaincode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+// ErrCodeVersionConflict prefixes the error returned when a record changed since the client read it
+const ErrCodeVersionConflict = "VERSION_CONFLICT"
+
+const expectedVersionsTransientKey = "expected_versions"
+
+// checkExpectedVersion fails when the client expects the asset or user with given key at another
+// version than the stored one. Clients pass the versions they read as a JSON object mapping keys to
+// versions, e.g. {"asset1":3,"user2":7}, in the optional expected_versions transient field of any
+// update or transfer, and get a clean conflict error instead of a late MVCC failure at commit.
+func checkExpectedVersion(ctx contractapi.TransactionContextInterface, key string, version int64) error {
+	transientMap, err := ctx.GetStub().GetTransient()
+	if err != nil {
+		return fmt.Errorf("error getting transient: %v", err)
+	}
+	if _, ok := transientMap[expectedVersionsTransientKey]; !ok {
+		return nil
+	}
+	var expected map[string]int64
+	err = readTransientJSON(ctx, expectedVersionsTransientKey, &expected)
+	if err != nil {
+		return err
+	}
+	expectedVersion, ok := expected[key]
+	if ok && expectedVersion != version {
+		return fmt.Errorf("%s: %s is at version %d, expected %d", ErrCodeVersionConflict, key, version, expectedVersion)
+	}
+
+	return nil
+}
+
+// isVersionConflict reports whether err was returned by checkExpectedVersion
+func isVersionConflict(err error) bool {
+	return strings.HasPrefix(err.Error(), ErrCodeVersionConflict+":")
+}
@@ -0,0 +1,37 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Version: 3})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Version: 5})
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.ChangeAssetColor(transactionContext, "asset1", "red")
+	require.NoError(t, err)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, int64(6), asset.Version)
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"expected_versions": []byte(`{"asset1":5}`)}, nil)
+	err = assetTransfer.ChangeAssetColor(transactionContext, "asset1", "blue")
+	require.EqualError(t, err, "VERSION_CONFLICT: asset1 is at version 6, expected 5")
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"expected_versions": []byte(`{"asset1":6,"user1":3}`)}, nil)
+	err = assetTransfer.ChangeAssetColor(transactionContext, "asset1", "blue")
+	require.NoError(t, err)
+	state.get(t, "asset1", asset)
+	require.Equal(t, "blue", asset.Color)
+	require.Equal(t, int64(7), asset.Version)
+
+	chaincodeStub.GetTransientReturns(map[string][]byte{"expected_versions": []byte(`{"user1":2}`)}, nil)
+	_, err = assetTransfer.ReadUser(transactionContext, "user1")
+	require.EqualError(t, err, "VERSION_CONFLICT: user1 is at version 3, expected 2")
+}
@@ -0,0 +1,124 @@
+package chaincode
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const vinIndexName = "vin~asset"
+
+// vinWeights are the ISO 3779 weights of the VIN positions used to compute the check digit
+var vinWeights = [17]int{8, 7, 6, 5, 4, 3, 2, 10, 0, 9, 8, 7, 6, 5, 4, 3, 2}
+
+// GetAssetByVIN returns the asset registered with given vehicle identification number
+func (s *SmartContract) GetAssetByVIN(ctx contractapi.TransactionContextInterface, vin string) (*Asset, error) {
+	indexKey, err := vinIndexKey(ctx, vin)
+	if err != nil {
+		return nil, err
+	}
+	assetID, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if assetID == nil {
+		return nil, fmt.Errorf("no asset with VIN %s", vin)
+	}
+
+	return s.ReadAsset(ctx, string(assetID))
+}
+
+// validateVIN returns an error unless vin is 17 characters long, avoids the letters I, O and Q
+// and carries the right check digit in the ninth position
+func validateVIN(vin string) error {
+	vin = strings.ToUpper(vin)
+	if len(vin) != 17 {
+		return fmt.Errorf("VIN %s must be 17 characters long", vin)
+	}
+
+	sum := 0
+	for i, c := range vin {
+		value, ok := vinValue(c)
+		if !ok {
+			return fmt.Errorf("VIN %s contains invalid character %c", vin, c)
+		}
+		sum = sum + value*vinWeights[i]
+	}
+	check := byte('0' + sum%11)
+	if sum%11 == 10 {
+		check = 'X'
+	}
+	if vin[8] != check {
+		return fmt.Errorf("VIN %s has an invalid check digit", vin)
+	}
+
+	return nil
+}
+
+// vinValue returns the value a VIN character stands for in the check digit sum
+func vinValue(c rune) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0'), true
+	case c >= 'A' && c <= 'H':
+		return int(c-'A') + 1, true
+	case c >= 'J' && c <= 'N':
+		return int(c-'J') + 1, true
+	case c == 'P':
+		return 7, true
+	case c == 'R':
+		return 9, true
+	case c >= 'S' && c <= 'Z':
+		return int(c-'S') + 2, true
+	}
+
+	return 0, false
+}
+
+// vinIndexKey returns the key of the VIN index entry for given VIN
+func vinIndexKey(ctx contractapi.TransactionContextInterface, vin string) (string, error) {
+	indexKey, err := ctx.GetStub().CreateCompositeKey(vinIndexName, []string{strings.ToUpper(vin)})
+	if err != nil {
+		return "", fmt.Errorf("failed to create composite key: %v", err)
+	}
+
+	return indexKey, nil
+}
+
+// checkVINAvailable returns an error when another asset is already registered with given VIN
+func checkVINAvailable(ctx contractapi.TransactionContextInterface, vin string) error {
+	indexKey, err := vinIndexKey(ctx, vin)
+	if err != nil {
+		return err
+	}
+	assetID, err := ctx.GetStub().GetState(indexKey)
+	if err != nil {
+		return fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if assetID != nil {
+		return fmt.Errorf("VIN %s is already registered to asset %s", vin, assetID)
+	}
+
+	return nil
+}
+
+// putVINIndex maps given VIN to the asset ID
+func putVINIndex(ctx contractapi.TransactionContextInterface, vin string, assetID string) error {
+	indexKey, err := vinIndexKey(ctx, vin)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(indexKey, []byte(assetID))
+}
+
+// deleteVINIndex removes the VIN index entry for given VIN
+func deleteVINIndex(ctx contractapi.TransactionContextInterface, vin string) error {
+	indexKey, err := vinIndexKey(ctx, vin)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().DelState(indexKey)
+}
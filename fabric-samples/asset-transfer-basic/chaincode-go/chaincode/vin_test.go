@@ -0,0 +1,38 @@
+package chaincode_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetVIN(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	assetTransfer := chaincode.SmartContract{}
+
+	err := assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82633A00435", "honda", "accord", 2003, "silver", "user1", 0)
+	require.EqualError(t, err, "VIN 1HGCM82633A00435 must be 17 characters long")
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82O33A004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.EqualError(t, err, "VIN 1HGCM82O33A004352 contains invalid character O")
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1HGCM82643A004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.EqualError(t, err, "VIN 1HGCM82643A004352 has an invalid check digit")
+
+	err = assetTransfer.CreateAsset(transactionContext, "asset1", "1hgcm82633a004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.NoError(t, err)
+	err = assetTransfer.CreateAsset(transactionContext, "asset2", "1HGCM82633A004352", "honda", "accord", 2003, "silver", "user1", 0)
+	require.EqualError(t, err, "VIN 1HGCM82633A004352 is already registered to asset asset1")
+	err = assetTransfer.CreateAsset(transactionContext, "asset2", "1M8GDM9AXKP042788", "mci", "d4500", 1989, "white", "user1", 0)
+	require.NoError(t, err)
+
+	asset, err := assetTransfer.GetAssetByVIN(transactionContext, "1HGCM82633A004352")
+	require.NoError(t, err)
+	require.Equal(t, "asset1", asset.ID)
+	require.Equal(t, "1HGCM82633A004352", asset.VIN)
+
+	err = assetTransfer.DeleteAsset(transactionContext, "asset1")
+	require.NoError(t, err)
+	_, err = assetTransfer.GetAssetByVIN(transactionContext, "1HGCM82633A004352")
+	require.EqualError(t, err, "no asset with VIN 1HGCM82633A004352")
+}
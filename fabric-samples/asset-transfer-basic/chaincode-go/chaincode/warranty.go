@@ -0,0 +1,217 @@
+package chaincode
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hyperledger/fabric-contract-api-go/contractapi"
+)
+
+const warrantyObjectType = "warranty"
+
+// IssueWarranty lets a manufacturer or a dealer attach a warranty to an asset they own, when minting
+// it or before selling it on. The warranty is valid for validDays days and, unless maxMileage is 0,
+// up to maxMileage kilometers. It returns the ID of the new warranty.
+func (s *SmartContract) IssueWarranty(ctx contractapi.TransactionContextInterface, assetID string, issuerID string, coverage string, validDays int, maxMileage int64) (string, error) {
+	if coverage == "" {
+		return "", fmt.Errorf("coverage must not be empty")
+	}
+	if validDays <= 0 {
+		return "", fmt.Errorf("validity must be positive")
+	}
+	if maxMileage < 0 {
+		return "", fmt.Errorf("maximum mileage must not be negative")
+	}
+	issuer, err := s.ReadUser(ctx, issuerID)
+	if err != nil {
+		return "", err
+	}
+	err = verifyUserIdentity(ctx, issuer)
+	if err != nil {
+		return "", err
+	}
+	if !hasRole(issuer, RoleManufacturer) && !hasRole(issuer, RoleDealer) {
+		return "", fmt.Errorf("only manufacturers and dealers can issue warranties")
+	}
+	asset, err := s.ReadAsset(ctx, assetID)
+	if err != nil {
+		return "", err
+	}
+	if asset.OwnerID != issuerID {
+		return "", fmt.Errorf("the asset %s is not owned by user %s", assetID, issuerID)
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return "", err
+	}
+	if asset.WarrantyID != "" {
+		current, err := s.ReadWarranty(ctx, asset.WarrantyID)
+		if err != nil {
+			return "", err
+		}
+		if warrantyValid(current, asset, now) {
+			return "", fmt.Errorf("the asset %s is already covered by warranty %s", assetID, current.ID)
+		}
+	}
+
+	warranty := Warranty{
+		ID:         ctx.GetStub().GetTxID(),
+		AssetID:    assetID,
+		IssuerID:   issuerID,
+		Coverage:   coverage,
+		IssuedAt:   now,
+		ValidUntil: now.AddDate(0, 0, validDays),
+		MaxMileage: maxMileage,
+	}
+	err = putWarranty(ctx, &warranty)
+	if err != nil {
+		return "", err
+	}
+
+	asset.WarrantyID = warranty.ID
+	err = putAsset(ctx, asset)
+	if err != nil {
+		return "", err
+	}
+
+	return warranty.ID, nil
+}
+
+// ReadWarranty returns the warranty stored in the world state with given id
+func (s *SmartContract) ReadWarranty(ctx contractapi.TransactionContextInterface, warrantyID string) (*Warranty, error) {
+	warrantyKey, err := ctx.GetStub().CreateCompositeKey(warrantyObjectType, []string{warrantyID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create composite key: %v", err)
+	}
+	warrantyJSON, err := ctx.GetStub().GetState(warrantyKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read from world state: %v", err)
+	}
+	if warrantyJSON == nil {
+		return nil, fmt.Errorf("the warranty %s does not exist", warrantyID)
+	}
+
+	var warranty Warranty
+	err = json.Unmarshal(warrantyJSON, &warranty)
+	if err != nil {
+		return nil, err
+	}
+
+	return &warranty, nil
+}
+
+// FileWarrantyClaim lets the owner claim the repair job on the warranty of the asset once a quote has
+// been accepted and before the repair is paid. The warranty must still be valid.
+func (s *SmartContract) FileWarrantyClaim(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if job.Status == RepairRequested || job.Status == RepairPaid {
+		return fmt.Errorf("the repair job %s is %s and cannot be claimed on warranty", jobID, job.Status)
+	}
+	if job.WarrantyClaim == WarrantyClaimPending || job.WarrantyClaim == WarrantyClaimApproved {
+		return fmt.Errorf("the repair job %s is already claimed on warranty %s", jobID, job.WarrantyID)
+	}
+	_, err = s.readRepairOwner(ctx, job)
+	if err != nil {
+		return err
+	}
+	asset, err := s.ReadAsset(ctx, job.AssetID)
+	if err != nil {
+		return err
+	}
+	if asset.WarrantyID == "" {
+		return fmt.Errorf("the asset %s has no warranty", asset.ID)
+	}
+	warranty, err := s.ReadWarranty(ctx, asset.WarrantyID)
+	if err != nil {
+		return err
+	}
+	now, err := txTime(ctx)
+	if err != nil {
+		return err
+	}
+	if !warrantyValid(warranty, asset, now) {
+		return fmt.Errorf("the warranty %s has expired", warranty.ID)
+	}
+
+	job.WarrantyID = warranty.ID
+	job.WarrantyClaim = WarrantyClaimPending
+	return writeRepairJob(ctx, job)
+}
+
+// ApproveWarrantyClaim lets the issuer of the warranty accept the claim on the repair job. The issuer
+// then pays the whole repair instead of the owner.
+func (s *SmartContract) ApproveWarrantyClaim(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, warranty, err := s.readWarrantyClaim(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	job.WarrantyClaim = WarrantyClaimApproved
+	job.Liability = []LiabilityShare{{UserID: warranty.IssuerID, Share: 10000}}
+	return writeRepairJob(ctx, job)
+}
+
+// RejectWarrantyClaim lets the issuer of the warranty refuse the claim on the repair job, e.g. because
+// the repair is not covered. The repair is then paid as usual.
+func (s *SmartContract) RejectWarrantyClaim(ctx contractapi.TransactionContextInterface, jobID string) error {
+	job, _, err := s.readWarrantyClaim(ctx, jobID)
+	if err != nil {
+		return err
+	}
+
+	job.WarrantyClaim = WarrantyClaimRejected
+	return writeRepairJob(ctx, job)
+}
+
+// readWarrantyClaim returns the repair job with a pending warranty claim and the claimed warranty,
+// provided its issuer submitted the transaction
+func (s *SmartContract) readWarrantyClaim(ctx contractapi.TransactionContextInterface, jobID string) (*RepairJob, *Warranty, error) {
+	job, err := s.ReadRepairJob(ctx, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.WarrantyClaim != WarrantyClaimPending {
+		return nil, nil, fmt.Errorf("the repair job %s has no pending warranty claim", jobID)
+	}
+	warranty, err := s.ReadWarranty(ctx, job.WarrantyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	issuer, err := s.ReadUser(ctx, warranty.IssuerID)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = verifyUserIdentity(ctx, issuer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return job, warranty, nil
+}
+
+// warrantyValid returns true when the warranty still covers the asset at given time and mileage
+func warrantyValid(warranty *Warranty, asset *Asset, now time.Time) bool {
+	if !warranty.ValidUntil.After(now) {
+		return false
+	}
+
+	return warranty.MaxMileage == 0 || asset.Mileage <= warranty.MaxMileage
+}
+
+// putWarranty writes the given warranty to the world state
+func putWarranty(ctx contractapi.TransactionContextInterface, warranty *Warranty) error {
+	warrantyKey, err := ctx.GetStub().CreateCompositeKey(warrantyObjectType, []string{warranty.ID})
+	if err != nil {
+		return fmt.Errorf("failed to create composite key: %v", err)
+	}
+	warrantyJSON, err := json.Marshal(warranty)
+	if err != nil {
+		return err
+	}
+
+	return ctx.GetStub().PutState(warrantyKey, warrantyJSON)
+}
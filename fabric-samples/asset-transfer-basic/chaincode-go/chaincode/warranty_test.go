@@ -0,0 +1,107 @@
+package chaincode_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode"
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/chaincode/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIssueWarranty(t *testing.T) {
+	state := worldState{}
+	transactionContext, chaincodeStub := prepMocks(state)
+	state.put(t, "user1", &chaincode.User{ID: "user1"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Roles: []string{chaincode.RoleDealer}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user2", Mileage: 20})
+	state.put(t, "asset2", &chaincode.Asset{ID: "asset2", OwnerID: "user1"})
+	assetTransfer := chaincode.SmartContract{}
+
+	_, err := assetTransfer.IssueWarranty(transactionContext, "asset2", "user1", "powertrain", 730, 100000)
+	require.EqualError(t, err, "only manufacturers and dealers can issue warranties")
+	_, err = assetTransfer.IssueWarranty(transactionContext, "asset2", "user2", "powertrain", 730, 100000)
+	require.EqualError(t, err, "the asset asset2 is not owned by user user2")
+
+	chaincodeStub.GetTxIDReturns("warranty1")
+	warrantyID, err := assetTransfer.IssueWarranty(transactionContext, "asset1", "user2", "powertrain", 730, 100000)
+	require.NoError(t, err)
+	warranty, err := assetTransfer.ReadWarranty(transactionContext, warrantyID)
+	require.NoError(t, err)
+	require.Equal(t, &chaincode.Warranty{
+		ID:         "warranty1",
+		AssetID:    "asset1",
+		IssuerID:   "user2",
+		Coverage:   "powertrain",
+		IssuedAt:   time.Unix(1600000000, 0).UTC(),
+		ValidUntil: time.Unix(1600000000, 0).UTC().AddDate(0, 0, 730),
+		MaxMileage: 100000,
+	}, warranty)
+	asset := &chaincode.Asset{}
+	state.get(t, "asset1", asset)
+	require.Equal(t, "warranty1", asset.WarrantyID)
+
+	_, err = assetTransfer.IssueWarranty(transactionContext, "asset1", "user2", "paint", 365, 0)
+	require.EqualError(t, err, "the asset asset1 is already covered by warranty warranty1")
+}
+
+func TestWarrantyClaim(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user1", &chaincode.User{ID: "user1", Money: 1000, Identity: "owner"})
+	state.put(t, "user2", &chaincode.User{ID: "user2", Money: 5000, Identity: "dealer", Roles: []string{chaincode.RoleDealer}})
+	state.put(t, "user3", &chaincode.User{ID: "user3", Identity: "mechanic", Roles: []string{chaincode.RoleMechanic}})
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Mileage: 120000, WarrantyID: "warranty1"})
+	state.put(t, "\x00warranty\x00warranty1\x00", &chaincode.Warranty{ID: "warranty1", AssetID: "asset1", IssuerID: "user2", ValidUntil: time.Unix(1700000000, 0), MaxMileage: 100000})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", AssetID: "asset1", OwnerID: "user1", Status: chaincode.RepairCompleted, MechanicID: "user3", Price: 350})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err := assetTransfer.FileWarrantyClaim(transactionContext, "job1")
+	require.EqualError(t, err, "the warranty warranty1 has expired")
+
+	state.put(t, "asset1", &chaincode.Asset{ID: "asset1", OwnerID: "user1", Mileage: 80000, WarrantyID: "warranty1"})
+	err = assetTransfer.FileWarrantyClaim(transactionContext, "job1")
+	require.NoError(t, err)
+	err = assetTransfer.PayRepair(transactionContext, "job1")
+	require.EqualError(t, err, "the warranty claim on repair job job1 is pending")
+
+	err = assetTransfer.ApproveWarrantyClaim(transactionContext, "job1")
+	require.EqualError(t, err, "submitting client is not authorized to act for user user2")
+	clientIdentity.GetIDReturns("dealer", nil)
+	err = assetTransfer.ApproveWarrantyClaim(transactionContext, "job1")
+	require.NoError(t, err)
+
+	clientIdentity.GetIDReturns("owner", nil)
+	err = assetTransfer.PayRepair(transactionContext, "job1")
+	require.NoError(t, err)
+	user := &chaincode.User{}
+	state.get(t, "user1", user)
+	require.Equal(t, int64(1000), user.Money)
+	state.get(t, "user2", user)
+	require.Equal(t, int64(4650), user.Money)
+	state.get(t, "user3", user)
+	require.Equal(t, int64(350), user.Money)
+}
+
+func TestRejectWarrantyClaim(t *testing.T) {
+	state := worldState{}
+	transactionContext, _ := prepMocks(state)
+	clientIdentity := transactionContext.GetClientIdentity().(*mocks.ClientIdentity)
+	state.put(t, "user2", &chaincode.User{ID: "user2", Identity: "dealer"})
+	state.put(t, "\x00warranty\x00warranty1\x00", &chaincode.Warranty{ID: "warranty1", AssetID: "asset1", IssuerID: "user2"})
+	state.put(t, "\x00repair\x00job1\x00", &chaincode.RepairJob{ID: "job1", AssetID: "asset1", OwnerID: "user1", Status: chaincode.RepairInProgress, WarrantyID: "warranty1", WarrantyClaim: chaincode.WarrantyClaimPending})
+	assetTransfer := chaincode.SmartContract{}
+
+	clientIdentity.GetIDReturns("dealer", nil)
+	err := assetTransfer.RejectWarrantyClaim(transactionContext, "job1")
+	require.NoError(t, err)
+	job, err := assetTransfer.ReadRepairJob(transactionContext, "job1")
+	require.NoError(t, err)
+	require.Equal(t, chaincode.WarrantyClaimRejected, job.WarrantyClaim)
+	require.Empty(t, job.Liability)
+
+	err = assetTransfer.RejectWarrantyClaim(transactionContext, "job1")
+	require.EqualError(t, err, "the repair job job1 has no pending warranty claim")
+}
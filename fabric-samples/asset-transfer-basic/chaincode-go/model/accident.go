@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// AccidentReport is a shared record of an incident involving one or more assets. Damages and
+// claims resulting from it reference the report.
+type AccidentReport struct {
+	ID          string      `json:"ID"`
+	AssetIDs    []string    `json:"assetIDs"`
+	DriverIDs   []string    `json:"driverIDs"` // users driving the involved assets
+	Location    string      `json:"location"`
+	OccurredAt  time.Time   `json:"occurredAt"`
+	Description string      `json:"description"`
+	Damages     []DamageRef `json:"damages"`
+	ReporterID  string      `json:"reporter"` // client identity that filed the report
+	FiledAt     time.Time   `json:"filedAt"`
+}
+
+// DamageRef identifies a damage on an asset
+type DamageRef struct {
+	AssetID  string `json:"assetID"`
+	DamageID string `json:"damageID"`
+}
@@ -0,0 +1,12 @@
+package model
+
+// PriceAgreement is the price a seller or a buyer agreed to sell or buy an asset for. Each side
+// stores it in the implicit private data collection of their own organization; the sale settles
+// only when both stored agreements hash the same.
+type PriceAgreement struct {
+	AssetID string `json:"assetID"`
+	BuyerID string `json:"buyerID"`
+	Price   int64  `json:"price"` // in cents
+	// random value both sides share so the agreement hash is impossible to guess from likely prices
+	Salt string `json:"salt"`
+}
@@ -0,0 +1,11 @@
+package model
+
+// Appraisal is the appraised value of an asset kept private to the owner's organization. It is
+// stored in the implicit private data collection of the organization; the public asset only holds
+// the SHA-256 of its JSON.
+type Appraisal struct {
+	AssetID string `json:"assetID"`
+	Value   int64  `json:"value"` // in cents
+	// random value making the appraisal hash impossible to guess from likely amounts
+	Salt string `json:"salt"`
+}
@@ -0,0 +1,24 @@
+package model
+
+// AssetDetails are the descriptive details of an asset kept private to the owner's organization.
+// They are stored in the implicit private data collection of the organization; the public asset
+// only holds the SHA-256 of their JSON. Damages stay on the public asset because repairs and
+// insurance claims settle against them.
+type AssetDetails struct {
+	AssetID string `json:"assetID"`
+	Brand   string `json:"brand"`
+	Model   string `json:"model"`
+	Year    int    `json:"year"`
+	Color   string `json:"color"`
+	Value   int64  `json:"value"` // appraised value, in cents
+	// random value making the details hash impossible to guess from likely values
+	Salt string `json:"salt"`
+}
+
+// PublicAsset is the part of an asset everyone may see once its details are private
+type PublicAsset struct {
+	ID          string `json:"ID"`
+	OwnerMSP    string `json:"ownerMSP"` // organization of the owner
+	Status      string `json:"status"`
+	DetailsHash string `json:"detailsHash"` // SHA-256 of the private AssetDetails, empty while they are public
+}
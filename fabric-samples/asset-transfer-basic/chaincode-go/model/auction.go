@@ -0,0 +1,77 @@
+package model
+
+import "time"
+
+// Auction sells an asset to the highest bidder
+type Auction struct {
+	ID       string `json:"ID"`
+	AssetID  string `json:"assetID"`
+	SellerID string `json:"sellerID"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	// sealed bids: the public hashes of the bids kept in the bidders' organizations' collections,
+	// and the bids revealed after bidding closed
+	SealedBids   []SealedBid   `json:"sealedBids"`
+	RevealedBids []RevealedBid `json:"revealedBids"`
+	WinnerID     string        `json:"winnerID"`
+	Price        int64         `json:"price"` // winning bid, in cents
+	CreatedAt    time.Time     `json:"createdAt"`
+	BuyNowPrice  int64         `json:"buyNowPrice"` // in cents, 0 when the asset cannot be bought outright
+	Deposit      int64         `json:"deposit"`     // in cents, locked by every bidder with their first bid
+	Deposits     []BidDeposit  `json:"deposits"`
+
+	// english auctions: open bids must reach the reserve price and beat the high bid until EndsAt
+	ReservePrice int64     `json:"reservePrice"` // in cents
+	EndsAt       time.Time `json:"endsAt"`
+	HighBidderID string    `json:"highBidderID"`
+	HighBid      int64     `json:"highBid"` // in cents, held from the high bidder's balance
+
+	// dutch auctions: the price drops by PriceDrop every DropInterval seconds from StartPrice down to FloorPrice
+	StartPrice   int64 `json:"startPrice"`   // in cents
+	FloorPrice   int64 `json:"floorPrice"`   // in cents
+	PriceDrop    int64 `json:"priceDrop"`    // in cents
+	DropInterval int64 `json:"dropInterval"` // in seconds
+
+	// failed auctions: why the sale to the winner could not be settled
+	FailureReason string `json:"failureReason"`
+}
+
+// Auction types
+const (
+	AuctionSealed  = "sealed"  // bids stay private until the seller closes bidding
+	AuctionEnglish = "english" // open ascending bids until a deadline
+	AuctionDutch   = "dutch"   // descending price, the first buyer to accept wins
+)
+
+// Auction statuses
+const (
+	AuctionOpen   = "open"
+	AuctionClosed = "closed" // no more bids, sealed bids may be revealed
+	AuctionEnded  = "ended"
+	AuctionFailed = "failed" // the sale could not be settled, bids and deposits were refunded
+)
+
+// Bid is a sealed bid. It is stored in the implicit private data collection of the bidder's
+// organization; the auction only holds the SHA-256 of its JSON.
+type Bid struct {
+	AuctionID string `json:"auctionID"`
+	BidderID  string `json:"bidderID"`
+	Price     int64  `json:"price"` // in cents
+	// random value making the bid hash impossible to guess from likely prices
+	Salt string `json:"salt"`
+}
+
+// SealedBid is the public trace of a bid kept private until it is revealed
+type SealedBid struct {
+	ID         string `json:"ID"`
+	BidderID   string `json:"bidderID"`
+	Collection string `json:"collection"` // private data collection holding the Bid
+	Hash       string `json:"hash"`       // SHA-256 of the Bid JSON, hex encoded
+}
+
+// RevealedBid is a sealed bid whose price was revealed and checked against its hash
+type RevealedBid struct {
+	ID       string `json:"ID"`
+	BidderID string `json:"bidderID"`
+	Price    int64  `json:"price"` // in cents
+}
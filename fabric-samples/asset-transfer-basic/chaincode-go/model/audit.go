@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// Audit records which client created a record and which client changed it last, so auditors can
+// attribute every change without parsing blocks. It is embedded in assets, users and damages.
+type Audit struct {
+	CreatedBy  string    `json:"createdBy"`  // client identity
+	CreatedMSP string    `json:"createdMSP"` // organization of the client
+	CreatedAt  time.Time `json:"createdAt"`  // transaction timestamp
+	UpdatedBy  string    `json:"updatedBy"`
+	UpdatedMSP string    `json:"updatedMSP"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// AuditEntry records a transaction that changed an asset, for compliance reviews
+type AuditEntry struct {
+	AssetID   string    `json:"assetID"`
+	TxID      string    `json:"txID"`
+	Function  string    `json:"function"` // transaction function that was invoked
+	ClientID  string    `json:"clientID"` // identity of the submitting client
+	MSPID     string    `json:"mspID"`
+	Timestamp time.Time `json:"timestamp"`
+	Keys      []string  `json:"keys"` // world state keys the transaction wrote, the asset's first
+}
@@ -0,0 +1,11 @@
+package model
+
+// BalanceDelta is a credit to a hot account that has not been added to its balance yet. Writing
+// credits under their own keys lets concurrent sales pay the same account without rewriting its
+// user key; ConsolidateBalance folds them into the balance.
+type BalanceDelta struct {
+	UserID string `json:"userID"`
+	TxID   string `json:"txID"`
+	Reason string `json:"reason"` // what the credit is for, unique within the transaction
+	Amount int64  `json:"amount"` // in cents
+}
@@ -0,0 +1,13 @@
+package model
+
+// AssetInput holds the details of a used car registered with CreateAssets
+type AssetInput struct {
+	ID             string `json:"ID"`
+	VIN            string `json:"vin"`
+	Brand          string `json:"brand"`
+	Model          string `json:"model"`
+	Year           int    `json:"year"`
+	Color          string `json:"color"`
+	OwnerID        string `json:"owner"`
+	AppraisedValue int64  `json:"appraisedValue"` // in cents
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// BatteryReport is a mechanic's measurement of the traction battery of an electric car
+type BatteryReport struct {
+	ID            string    `json:"ID"`
+	AssetID       string    `json:"assetID"`
+	ReporterID    string    `json:"reporterID"`
+	StateOfHealth int       `json:"stateOfHealth"` // remaining capacity as a percentage of the original
+	CapacityWh    int64     `json:"capacityWh"`    // measured usable capacity in watt-hours
+	ReportedAt    time.Time `json:"reportedAt"`
+}
+
+// FuelElectric is the fuel type of battery electric cars in their factory specs
+const FuelElectric = "electric"
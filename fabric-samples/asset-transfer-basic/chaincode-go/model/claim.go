@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+// Claim is an owner's request to the insurer to cover the cost of a damage
+type Claim struct {
+	ID             string `json:"ID"`
+	PolicyID       string `json:"policyID"`
+	AssetID        string `json:"assetID"`
+	DamageID       string `json:"damageID"`
+	OwnerID        string `json:"ownerID"`
+	InsurerID      string `json:"insurerID"`
+	ClaimedAmount  int64  `json:"claimedAmount"`  // in cents
+	ApprovedAmount int64  `json:"approvedAmount"` // in cents
+	// payout breakdown, in cents: PaidAmount = ApprovedAmount - Deductible, never below zero.
+	// DeductibleCharged is what the owner paid the insurer when the deductible exceeded the approved amount.
+	Deductible        int64     `json:"deductible"`
+	PaidAmount        int64     `json:"paidAmount"`
+	DeductibleCharged int64     `json:"deductibleCharged"`
+	AssessorID        string    `json:"assessorID"`
+	FiledAt           time.Time `json:"filedAt"`
+	Status            string    `json:"status"`
+}
+
+// Claim statuses
+const (
+	ClaimFiled    = "filed"
+	ClaimApproved = "approved"
+	ClaimRejected = "rejected"
+	ClaimPaid     = "paid"
+)
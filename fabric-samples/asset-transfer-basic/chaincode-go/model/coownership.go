@@ -0,0 +1,7 @@
+package model
+
+// OwnershipShare is the part of an asset a co-owner holds
+type OwnershipShare struct {
+	UserID string `json:"userID"`
+	Share  int64  `json:"share"` // in basis points
+}
@@ -0,0 +1,10 @@
+package model
+
+// AssetAtRiskEvent is emitted when the damages on an asset cross the alert threshold
+type AssetAtRiskEvent struct {
+	AssetID        string `json:"assetID"`
+	OwnerID        string `json:"ownerID"`
+	DamageCost     int64  `json:"damageCost"`     // cost of all open damages, in cents
+	AppraisedValue int64  `json:"appraisedValue"` // in cents, 0 when the appraisal is private
+	Threshold      int64  `json:"threshold"`      // in basis points of the appraised value
+}
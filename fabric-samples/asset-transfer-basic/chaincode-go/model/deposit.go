@@ -0,0 +1,7 @@
+package model
+
+// BidDeposit is the deposit a bidder locked to take part in an auction
+type BidDeposit struct {
+	BidderID string `json:"bidderID"`
+	Amount   int64  `json:"amount"` // in cents
+}
@@ -0,0 +1,5 @@
+// Package model declares the records the asset-transfer chaincode stores in the world state and
+// returns from its transactions, with the values of their status and role fields. It depends on
+// the standard library only, so Go client applications can unmarshal transaction results into the
+// same types the chaincode uses.
+package model
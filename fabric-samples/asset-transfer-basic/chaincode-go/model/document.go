@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// NotarizedDocument anchors an off-chain document, such as a contract, a purchase agreement or a
+// photo, to the record of an asset by its SHA-256 hash
+type NotarizedDocument struct {
+	AssetID     string    `json:"assetID"`
+	Hash        string    `json:"hash"`    // hex encoded SHA-256 of the document
+	DocType     string    `json:"docType"` // e.g. contract, purchase agreement, photo
+	TxID        string    `json:"txID"`
+	NotarizedBy string    `json:"notarizedBy"` // identity of the submitting client
+	NotarizedAt time.Time `json:"notarizedAt"`
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// EmissionsTest is the result of an emissions test of an asset taken at a certified station. A
+// passed test keeps the car compliant until ValidUntil.
+type EmissionsTest struct {
+	ID         string    `json:"ID"`
+	AssetID    string    `json:"assetID"`
+	StationID  string    `json:"stationID"`
+	CO2        int64     `json:"co2"` // measured emissions in grams per kilometer
+	Passed     bool      `json:"passed"`
+	TestedAt   time.Time `json:"testedAt"`
+	ValidUntil time.Time `json:"validUntil"`
+}
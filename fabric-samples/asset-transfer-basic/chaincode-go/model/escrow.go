@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Escrow holds a buyer's funds for an asset until the seller delivers it or the escrow times out
+type Escrow struct {
+	ID       string    `json:"ID"`
+	AssetID  string    `json:"assetID"`
+	SellerID string    `json:"sellerID"`
+	BuyerID  string    `json:"buyerID"`
+	Amount   int64     `json:"amount"` // in cents
+	Deadline time.Time `json:"deadline"`
+	Status   string    `json:"status"`
+}
+
+// Escrow statuses
+const (
+	EscrowFunded   = "funded"
+	EscrowReleased = "released"
+	EscrowRefunded = "refunded"
+)
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Export records the move of an asset from one jurisdiction to another. The asset keeps its ID,
+// so its transfers, mileage and service history stay with it across the move.
+type Export struct {
+	ID          string    `json:"ID"`
+	AssetID     string    `json:"assetID"`
+	OwnerID     string    `json:"ownerID"`
+	Origin      string    `json:"origin"`
+	Destination string    `json:"destination"`
+	Status      string    `json:"status"`
+	RequestedAt time.Time `json:"requestedAt"`
+	ExportedBy  string    `json:"exportedBy"` // regulator of the origin who approved the export
+	ImportedBy  string    `json:"importedBy"` // regulator of the destination who approved the import
+	RejectedBy  string    `json:"rejectedBy"`
+	Reason      string    `json:"reason"` // why the export was rejected
+}
+
+// Export statuses
+const (
+	ExportRequested = "requested"
+	ExportApproved  = "exported"
+	ExportImported  = "imported"
+	ExportRejected  = "rejected"
+)
@@ -0,0 +1,7 @@
+package model
+
+// FleetItem is an asset sold as part of a fleet and the part of the total price paid for it
+type FleetItem struct {
+	AssetID string `json:"assetID"`
+	Price   int64  `json:"price"` // in cents
+}
@@ -0,0 +1,16 @@
+package model
+
+// ClaimFlag marks a user, asset or single claim that insurers may want to look into
+type ClaimFlag struct {
+	Subject  string   `json:"subject"` // "user", "asset" or "claim"
+	ID       string   `json:"ID"`
+	Reason   string   `json:"reason"`
+	ClaimIDs []string `json:"claimIDs"`
+}
+
+// Subjects of claim flags
+const (
+	FlagUser  = "user"
+	FlagAsset = "asset"
+	FlagClaim = "claim"
+)
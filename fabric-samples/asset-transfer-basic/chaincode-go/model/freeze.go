@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// UserFreezeEvent is emitted when an admin freezes or unfreezes an account
+type UserFreezeEvent struct {
+	UserID string `json:"userID"`
+	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+// AssetFreezeEvent is emitted when an admin freezes or unfreezes an asset
+type AssetFreezeEvent struct {
+	AssetID string `json:"assetID"`
+	Frozen  bool   `json:"frozen"`
+	Reason  string `json:"reason"`
+}
+
+// BlockedAttempt records an operation refused because the user is frozen. A refused transaction
+// commits nothing, so the client reports the attempt in a transaction of its own.
+type BlockedAttempt struct {
+	UserID    string    `json:"userID"`
+	Operation string    `json:"operation"` // transaction the user was refused
+	Code      string    `json:"code"`      // error code the operation failed with
+	TxID      string    `json:"txID"`      // transaction recording the attempt
+	ClientID  string    `json:"clientID"`
+	MSPID     string    `json:"mspID"`
+	Timestamp time.Time `json:"timestamp"`
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// FundsEvent is the payload of events emitted when a user's balance changes
+type FundsEvent struct {
+	UserID  string `json:"userID"`
+	Amount  int64  `json:"amount"`  // in cents
+	Balance int64  `json:"balance"` // in cents
+}
+
+// Payment records a transfer of money between two users
+type Payment struct {
+	ID         string `json:"ID"`
+	FromUserID string `json:"fromUserID"`
+	ToUserID   string `json:"toUserID"`
+	Amount     int64  `json:"amount"`
+	Memo       string `json:"memo"`
+}
+
+// BalanceRecord is the balance of a user after a given transaction
+type BalanceRecord struct {
+	TxID      string    `json:"txID"`
+	Timestamp time.Time `json:"timestamp"`
+	Balance   int64     `json:"balance"` // in cents
+	IsDelete  bool      `json:"isDelete"`
+}
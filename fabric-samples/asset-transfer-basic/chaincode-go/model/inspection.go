@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// InspectionRecord is the outcome of a technical inspection of an asset. A passed inspection
+// certifies the car roadworthy until ValidUntil.
+type InspectionRecord struct {
+	ID          string    `json:"ID"`
+	AssetID     string    `json:"assetID"`
+	InspectorID string    `json:"inspectorID"`
+	Passed      bool      `json:"passed"`
+	IssuedAt    time.Time `json:"issuedAt"`
+	ValidUntil  time.Time `json:"validUntil"`
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// InsurancePolicy is an insurer's cover of an asset. The owner pays the premium for each term of
+// TermDays days, during which the policy covers damages. The commercial terms are kept in the
+// insurer's private data collection, the public policy only holds their hash.
+type InsurancePolicy struct {
+	ID              string    `json:"ID"`
+	AssetID         string    `json:"assetID"`
+	OwnerID         string    `json:"ownerID"`
+	InsurerID       string    `json:"insurerID"`
+	InsurerMSP      string    `json:"insurerMSP"`      // organization whose peers must endorse changes to claims on the policy
+	TermsCollection string    `json:"termsCollection"` // private data collection holding the PolicyTerms
+	TermsHash       string    `json:"termsHash"`       // SHA-256 of the PolicyTerms JSON, hex encoded
+	TermDays        int       `json:"termDays"`
+	CreatedAt       time.Time `json:"createdAt"`
+	Transferable    bool      `json:"transferable"` // moves to the buyer when the asset is sold
+	ValidFrom       time.Time `json:"validFrom"`
+	ValidUntil      time.Time `json:"validUntil"`
+	Status          string    `json:"status"`
+}
+
+// Insurance policy statuses
+const (
+	PolicyPending   = "pending" // offered by the insurer, premium not paid yet
+	PolicyActive    = "active"
+	PolicyCancelled = "cancelled"
+)
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Invoice is the bill for a paid repair job
+type Invoice struct {
+	ID         string        `json:"ID"`
+	JobID      string        `json:"jobID"`
+	AssetID    string        `json:"assetID"`
+	OwnerID    string        `json:"ownerID"`
+	MechanicID string        `json:"mechanicID"`
+	Items      []InvoiceItem `json:"items"`
+	Total      int64         `json:"total"` // amount paid to the mechanic, in cents
+	IssuedAt   time.Time     `json:"issuedAt"`
+}
+
+// InvoiceItem is a repaired damage listed on an invoice
+type InvoiceItem struct {
+	DamageID    string `json:"damageID"`
+	Description string `json:"description"`
+	Cost        int64  `json:"cost"` // recorded cost of the damage, in cents
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Lease lets the lessee use the lessor's asset for a number of monthly installments. The lessor
+// keeps ownership and the asset stays encumbered while the lease runs.
+type Lease struct {
+	ID            string    `json:"ID"`
+	AssetID       string    `json:"assetID"`
+	LessorID      string    `json:"lessorID"`
+	LesseeID      string    `json:"lesseeID"`
+	MonthlyAmount int64     `json:"monthlyAmount"` // in cents
+	Term          int       `json:"term"`          // number of monthly installments
+	PaymentsMade  int       `json:"paymentsMade"`
+	StartedAt     time.Time `json:"startedAt"`
+	NextDueAt     time.Time `json:"nextDueAt"` // when the next installment is due
+	Status        string    `json:"status"`
+	// price of the car at the end of the term, the lessee may buy it for that plus the unpaid installments
+	ResidualValue int64 `json:"residualValue"` // in cents
+}
+
+// Lease statuses
+const (
+	LeaseOffered    = "offered"
+	LeaseActive     = "active"
+	LeaseDelinquent = "delinquent" // an installment is past due
+	LeaseCompleted  = "completed"
+	LeaseTerminated = "terminated" // ended early by the lessee
+	LeaseBoughtOut  = "boughtOut"  // the lessee bought the car
+)
+
+// LeaseDelinquentEvent is the payload of the LeaseDelinquent event
+type LeaseDelinquentEvent struct {
+	LeaseID   string    `json:"leaseID"`
+	AssetID   string    `json:"assetID"`
+	LesseeID  string    `json:"lesseeID"`
+	NextDueAt time.Time `json:"nextDueAt"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Listing publishes an asset for sale to anyone at an asking price
+type Listing struct {
+	AssetID     string `json:"assetID"`
+	SellerID    string `json:"sellerID"`
+	AskingPrice int64  `json:"askingPrice"` // in cents
+	DealerID    string `json:"dealerID"`
+	Status      string `json:"status"`
+	BuyerID     string `json:"buyerID"`
+}
+
+// Listing statuses
+const (
+	ListingOpen      = "open"
+	ListingSold      = "sold"
+	ListingWithdrawn = "withdrawn"
+)
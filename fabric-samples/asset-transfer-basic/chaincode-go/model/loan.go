@@ -0,0 +1,21 @@
+package model
+
+// Loan finances the purchase of an asset. The lender pays part of the price and holds a lien on
+// the asset until the borrower repays the balance; the asset cannot be transferred while the lien
+// exists unless the lender approves it.
+type Loan struct {
+	ID         string `json:"ID"`
+	AssetID    string `json:"assetID"`
+	LenderID   string `json:"lenderID"`
+	BorrowerID string `json:"borrowerID"`
+	Principal  int64  `json:"principal"` // in cents
+	Balance    int64  `json:"balance"`   // still to be repaid, in cents
+	Status     string `json:"status"`
+}
+
+// Loan statuses
+const (
+	LoanOffered = "offered"
+	LoanActive  = "active"
+	LoanRepaid  = "repaid"
+)
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// MileageReading is an odometer reading of an asset recorded on the ledger
+type MileageReading struct {
+	AssetID    string    `json:"assetID"`
+	TxID       string    `json:"txID"`
+	Mileage    int64     `json:"mileage"` // in kilometers
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// MileageAnomaly is the event emitted when a reading lower than the recorded mileage is submitted
+type MileageAnomaly struct {
+	AssetID          string `json:"assetID"`
+	VIN              string `json:"vin"`
+	RecordedMileage  int64  `json:"recordedMileage"`  // mileage on the ledger, in kilometers
+	SubmittedMileage int64  `json:"submittedMileage"` // rejected reading, in kilometers
+	SubmittedBy      string `json:"submittedBy"`      // identity of the submitting client
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// FactorySpecs are the specifications a car left the factory with
+type FactorySpecs struct {
+	Engine       string `json:"engine"`
+	PowerKW      int    `json:"powerKW"`
+	Transmission string `json:"transmission"`
+	FuelType     string `json:"fuelType"`
+	Seats        int    `json:"seats"`
+}
+
+// FactoryData is what the manufacturer recorded when minting a new car
+type FactoryData struct {
+	ManufacturerID string       `json:"manufacturerID"`
+	ProductionDate time.Time    `json:"productionDate"`
+	Specs          FactorySpecs `json:"specs"`
+}
@@ -0,0 +1,157 @@
+package model
+
+import "time"
+
+// Damage describes car damages
+type Damage struct {
+	ID          string    `json:"ID"`
+	AssetID     string    `json:"assetID"`
+	Description string    `json:"description"`
+	Cost        int64     `json:"cost"` // in cents
+	Status      string    `json:"status"`
+	ReporterID  string    `json:"reporter"` // client identity that reported the damage
+	ReportedAt  time.Time `json:"reportedAt"`
+	RepairedBy  string    `json:"repairedBy"` // ID of the mechanic who repaired the damage
+	// SHA-256 hashes of off-chain photos and reports of the damage, hex encoded
+	DocumentHashes []string `json:"documentHashes"`
+	// users who pay for the repair, the owner of the asset when empty
+	Liability []LiabilityShare `json:"liability"`
+	ClaimID   string           `json:"claimID"` // insurance claim filed for the damage
+	// amount the insurer paid out for the damage, in cents
+	CoveredAmount int64  `json:"coveredAmount"`
+	AccidentID    string `json:"accidentID"`  // accident report the damage comes from
+	RepairJobID   string `json:"repairJobID"` // repair job requested for the damage
+	Audit
+}
+
+// LiabilityShare is the part of a repair cost a user is liable for
+type LiabilityShare struct {
+	UserID string `json:"userID"`
+	Share  int64  `json:"share"` // in basis points
+}
+
+// Damage statuses
+const (
+	DamageOpen     = "open"
+	DamageRepaired = "repaired"
+)
+
+// User describes user details (car owner, repairman, ...)
+type User struct {
+	ID       string   `json:"ID"`
+	Name     string   `json:"name"`
+	Lastname string   `json:"lastname"`
+	Email    string   `json:"email"`
+	Money    int64    `json:"money"` // in cents
+	Status   string   `json:"status"`
+	Roles    []string `json:"roles"`
+	Identity string   `json:"identity"` // enrollment identity of the client acting for the user
+	MSPID    string   `json:"mspID"`    // organization of the client acting for the user
+
+	KYCVerified  bool   `json:"kycVerified"`  // identity checked by a regulator, required for high-value purchases
+	Jurisdiction string `json:"jurisdiction"` // jurisdiction a regulator oversees
+
+	// private data collection holding the UserPII and the SHA-256 of its JSON, hex encoded
+	PIICollection string `json:"piiCollection"`
+	PIIHash       string `json:"piiHash"`
+	EmailHash     string `json:"emailHash"` // SHA-256 of the private email address, keeps it unique
+	PIIPurged     bool   `json:"piiPurged"` // personal data erased with PurgeUserPII
+
+	Audit
+	Version int64 `json:"version"` // increased on every write, for optimistic concurrency
+
+	FrozenReason string `json:"frozenReason"` // why an admin froze the account
+
+	HotAccount bool `json:"hotAccount"` // sale proceeds and commissions are credited as balance deltas
+
+	DailyLimit int64  `json:"dailyLimit"` // in cents, 0 means no limit
+	DailySpent int64  `json:"dailySpent"` // in cents, spent on SpentDay
+	SpentDay   string `json:"spentDay"`   // UTC date of the last payment
+}
+
+// User statuses
+const (
+	UserActive = "active"
+	UserClosed = "closed"
+	UserFrozen = "frozen" // blacklisted by an admin, funds and assets cannot move
+)
+
+// Asset describes basic details of what makes up a simple asset (car)
+type Asset struct {
+	DocType        string   `json:"docType"` // always assetDocType, lets CouchDB indexes tell assets from other records
+	ID             string   `json:"ID"`
+	VIN            string   `json:"vin"`   // vehicle identification number, unique across assets
+	Plate          string   `json:"plate"` // license plate assigned by the registry, unique across assets
+	Brand          string   `json:"brand"`
+	Model          string   `json:"model"`
+	Year           int      `json:"year"`
+	Color          string   `json:"color"`
+	OwnerID        string   `json:"owner"`
+	Damages        []Damage `json:"damages"`        // open damages, stored under their own keys and loaded on read
+	AppraisedValue int64    `json:"appraisedValue"` // in cents, 0 once the appraisal is private
+	Encumbered     bool     `json:"encumbered"`     // cannot be transferred while set
+	Frozen         bool     `json:"frozen"`         // held by an admin, cannot be transferred, listed or paid for repairs
+	FrozenReason   string   `json:"frozenReason"`   // why an admin froze the car
+	Status         string   `json:"status"`         // empty while the car is roadworthy
+	PolicyID       string   `json:"policyID"`       // insurance policy last paid for the car
+	Delegate       string   `json:"delegate"`       // user the owner approved to transfer the car on their behalf
+	SeizureID      string   `json:"seizureID"`      // seizure holding the car in custody, empty when not seized
+	AuctionID      string   `json:"auctionID"`      // running auction selling the car, empty otherwise
+	Stolen         bool     `json:"stolen"`         // reported stolen, cannot be transferred, listed or paid for repairs
+	Jurisdiction   string   `json:"jurisdiction"`   // jurisdiction the car is registered in
+	ExportID       string   `json:"exportID"`       // move to another jurisdiction in progress, empty otherwise
+	Recalls        []string `json:"recalls"`        // open recalls the car has not been fixed for
+	Mileage        int64    `json:"mileage"`        // last odometer reading in kilometers
+	// a reading lower than Mileage was submitted, only a regulator can clear the flag
+	MileageTampered bool   `json:"mileageTampered"`
+	WarrantyID      string `json:"warrantyID"` // last warranty issued for the car
+
+	Factory *FactoryData   `json:"factory"` // set on cars minted by their manufacturer
+	Battery *BatteryReport `json:"battery"` // latest battery report of an electric car
+
+	InspectionID         string    `json:"inspectionID"`         // last technical inspection of the car
+	InspectionValidUntil time.Time `json:"inspectionValidUntil"` // end of the validity of the inspection certificate
+	EmissionsTestID      string    `json:"emissionsTestID"`      // last emissions test of the car
+	EmissionsValidUntil  time.Time `json:"emissionsValidUntil"`  // the car complies with emission rules until then
+
+	// private data collection holding the Appraisal and the SHA-256 of its JSON, hex encoded
+	AppraisalCollection string `json:"appraisalCollection"`
+	AppraisalHash       string `json:"appraisalHash"`
+	// private data collection holding the AssetDetails and the SHA-256 of their JSON, hex encoded
+	DetailsCollection string `json:"detailsCollection"`
+	DetailsHash       string `json:"detailsHash"`
+
+	// co-owners and their shares in basis points, empty while OwnerID owns the whole car
+	CoOwners []OwnershipShare `json:"coOwners"`
+	// co-owners who approved selling the whole car to SaleApprovalBuyer
+	SaleApprovals     []string `json:"saleApprovals"`
+	SaleApprovalBuyer string   `json:"saleApprovalBuyer"`
+
+	// number of share tokens the car is divided into, zero while it is not tokenized
+	ShareSupply int64 `json:"shareSupply"`
+	// shareholder buying back the outstanding shares at BuyBackPrice per share, paid from BuyBackEscrow
+	BuyBackUserID string `json:"buyBackUserID"`
+	BuyBackPrice  int64  `json:"buyBackPrice"`
+	BuyBackEscrow int64  `json:"buyBackEscrow"`
+
+	LienID       string `json:"lienID"`       // loan secured by the car, it cannot be transferred while set
+	LienholderID string `json:"lienholderID"` // lender holding the lien
+	// buyer the lienholder approved the car to be transferred to, the lien stays on the car
+	LienTransferApproval string `json:"lienTransferApproval"`
+
+	RegistrationID string `json:"registrationID"` // sale waiting for the registry to confirm it
+
+	NoteCollection string `json:"noteCollection"` // private data collection holding the owner's note, empty without one
+
+	Audit
+	Version int64 `json:"version"` // increased on every write, for optimistic concurrency
+}
+
+// Asset statuses
+const (
+	AssetTotaled  = "totaled"  // damages exceed the appraised value, cannot be transferred or repaired
+	AssetSalvaged = "salvaged" // restored after being totaled
+	// AssetFrozen is the status queried for assets on administrative hold. The hold is kept in
+	// Frozen rather than Status, so that lifting it does not lose whether the car is roadworthy.
+	AssetFrozen = "frozen"
+)
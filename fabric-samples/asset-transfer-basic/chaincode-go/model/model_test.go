@@ -0,0 +1,44 @@
+package model_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-samples/asset-transfer-basic/chaincode-go/model"
+	"github.com/stretchr/testify/require"
+)
+
+// requireJSONTags fails when a field of the struct type lacks a JSON tag or shares its name with
+// another field, since clients depend on the names the chaincode writes
+func requireJSONTags(t *testing.T, structType reflect.Type) {
+	names := map[string]string{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if field.Anonymous {
+			continue
+		}
+		tag, ok := field.Tag.Lookup("json")
+		require.True(t, ok, "%s.%s has no json tag", structType.Name(), field.Name)
+		name := strings.Split(tag, ",")[0]
+		require.NotEmpty(t, name, "%s.%s", structType.Name(), field.Name)
+		other, taken := names[name]
+		require.False(t, taken, "%s.%s and %s.%s are both %q", structType.Name(), field.Name, structType.Name(), other, name)
+		names[name] = field.Name
+	}
+}
+
+func TestJSONTags(t *testing.T) {
+	for _, value := range []interface{}{
+		model.Asset{},
+		model.User{},
+		model.Damage{},
+		model.LiabilityShare{},
+		model.TransferRecord{},
+		model.PublicAsset{},
+		model.Lease{},
+		model.FleetItem{},
+	} {
+		requireJSONTags(t, reflect.TypeOf(value))
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+// Offer is a seller's proposal to sell an asset to a specific buyer at a price
+type Offer struct {
+	ID       string `json:"ID"`
+	AssetID  string `json:"assetID"`
+	SellerID string `json:"sellerID"`
+	BuyerID  string `json:"buyerID"`
+	Price    int64  `json:"price"` // in cents
+	DealerID string `json:"dealerID"`
+	Status   string `json:"status"`
+}
+
+// Offer statuses
+const (
+	OfferOpen      = "open"
+	OfferAccepted  = "accepted"
+	OfferCancelled = "cancelled"
+)
@@ -0,0 +1,13 @@
+package model
+
+// UserPII is the personal data of a user kept private to the user's organization. It is stored in
+// the implicit private data collection of the organization; the public user only holds the SHA-256
+// of its JSON, so the data can be erased with PurgeUserPII while balances and ownership stay public.
+type UserPII struct {
+	UserID   string `json:"userID"`
+	Name     string `json:"name"`
+	Lastname string `json:"lastname"`
+	Email    string `json:"email"`
+	// random value making the PII hash impossible to guess from known personal data
+	Salt string `json:"salt"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// PurchasePlan is a sale paid off in installments. The buyer owns the asset after the down payment,
+// but the asset stays encumbered until the remaining balance is paid.
+type PurchasePlan struct {
+	ID          string `json:"ID"`
+	AssetID     string `json:"assetID"`
+	SellerID    string `json:"sellerID"`
+	BuyerID     string `json:"buyerID"`
+	Price       int64  `json:"price"`       // in cents
+	DownPayment int64  `json:"downPayment"` // in cents
+	Remaining   int64  `json:"remaining"`   // in cents
+	Status      string `json:"status"`
+}
+
+// Purchase plan statuses
+const (
+	PlanOffered   = "offered"
+	PlanActive    = "active"
+	PlanPaid      = "paid"
+	PlanCancelled = "cancelled"
+)
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// PlateRecord is an entry of the license plate history of an asset
+type PlateRecord struct {
+	AssetID     string    `json:"assetID"`
+	TxID        string    `json:"txID"`
+	Plate       string    `json:"plate"`
+	Action      string    `json:"action"`
+	RegistrarID string    `json:"registrarID"` // registry user who made the change
+	At          time.Time `json:"at"`
+}
+
+// Plate history actions
+const (
+	PlateAssigned = "assigned"
+	PlateRemoved  = "removed"
+)
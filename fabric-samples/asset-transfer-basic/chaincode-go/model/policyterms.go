@@ -0,0 +1,13 @@
+package model
+
+// PolicyTerms are the commercially sensitive terms of an insurance policy. They are stored in
+// the insurer's private data collection; the public policy holds the SHA-256 of their JSON.
+type PolicyTerms struct {
+	Premium    int64 `json:"premium"`    // per term, in cents
+	Coverage   int64 `json:"coverage"`   // in cents
+	Deductible int64 `json:"deductible"` // subtracted from every claim payout, in cents
+	// charge the owner whatever part of the deductible a claim payout cannot absorb
+	ChargeDeductible bool `json:"chargeDeductible"`
+	// random value making the terms hash impossible to guess from likely amounts
+	Salt string `json:"salt"`
+}
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// SalePriceRecord is the price an asset actually changed hands for
+type SalePriceRecord struct {
+	AssetID string    `json:"assetID"`
+	TxID    string    `json:"txID"`
+	Price   int64     `json:"price"`   // in cents
+	Mileage int64     `json:"mileage"` // odometer reading at the time of the sale, in kilometers
+	SoldAt  time.Time `json:"soldAt"`
+}
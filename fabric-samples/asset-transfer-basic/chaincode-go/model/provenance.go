@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// OwnershipPeriod is the time a user owned an asset
+type OwnershipPeriod struct {
+	OwnerID string    `json:"ownerID"`
+	TxID    string    `json:"txID"` // transaction that made the user the owner
+	From    time.Time `json:"from"`
+	Until   time.Time `json:"until"` // zero while the user still owns the asset
+	// price the user paid for the asset in cents, 0 when it changed hands without a recorded sale
+	Price int64 `json:"price"`
+}
+
+// ProvenanceReport is the chain of owners of an asset, oldest first
+type ProvenanceReport struct {
+	AssetID string             `json:"assetID"`
+	Owners  []*OwnershipPeriod `json:"owners"`
+	Deleted bool               `json:"deleted"` // the asset has since been deleted
+}
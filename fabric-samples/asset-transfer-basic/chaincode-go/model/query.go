@@ -0,0 +1,8 @@
+package model
+
+// PaginatedQueryResult is a page of assets returned by QueryAssetsWithPagination
+type PaginatedQueryResult struct {
+	Records      []*Asset `json:"records"`
+	FetchedCount int32    `json:"fetchedCount"`
+	Bookmark     string   `json:"bookmark"` // pass to the next call to fetch the next page, empty after the last one
+}
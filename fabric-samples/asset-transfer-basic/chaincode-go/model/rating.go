@@ -0,0 +1,17 @@
+package model
+
+// Rating is an owner's review of the mechanic who did a repair job
+type Rating struct {
+	MechanicID string `json:"mechanicID"`
+	JobID      string `json:"jobID"`
+	OwnerID    string `json:"ownerID"`
+	Score      int    `json:"score"` // 1 to 5
+	Comment    string `json:"comment"`
+}
+
+// MechanicRating summarizes all ratings of a mechanic
+type MechanicRating struct {
+	MechanicID string  `json:"mechanicID"`
+	Average    float64 `json:"average"`
+	Count      int     `json:"count"`
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Recall is a manufacturer campaign to fix a defect of the cars of a model built in a range of years
+type Recall struct {
+	ID             string    `json:"ID"`
+	ManufacturerID string    `json:"manufacturerID"`
+	Brand          string    `json:"brand"`
+	Model          string    `json:"model"`
+	FromYear       int       `json:"fromYear"`
+	ToYear         int       `json:"toYear"`
+	Description    string    `json:"description"`
+	OpenedAt       time.Time `json:"openedAt"`
+	Affected       int       `json:"affected"` // number of cars flagged when the recall opened
+}
+
+// RecallFix records a mechanic fixing a recalled car
+type RecallFix struct {
+	RecallID   string    `json:"recallID"`
+	AssetID    string    `json:"assetID"`
+	MechanicID string    `json:"mechanicID"`
+	FixedAt    time.Time `json:"fixedAt"`
+}
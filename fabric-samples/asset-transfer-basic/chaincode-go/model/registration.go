@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// Registration is a sale agreed between seller and buyer waiting for the registry to confirm it.
+// The price is held from the buyer's account until the registration is confirmed, rejected or
+// expires.
+type Registration struct {
+	ID       string    `json:"ID"`
+	AssetID  string    `json:"assetID"`
+	SellerID string    `json:"sellerID"`
+	BuyerID  string    `json:"buyerID"`
+	DealerID string    `json:"dealerID"`
+	Price    int64     `json:"price"` // in cents
+	Status   string    `json:"status"`
+	FiledAt  time.Time `json:"filedAt"`
+	Deadline time.Time `json:"deadline"` // the registration expires unless confirmed by then
+	DMVID    string    `json:"dmvID"`    // registry user who confirmed or rejected it
+	Reason   string    `json:"reason"`   // why the registry rejected it
+}
+
+// Registration statuses
+const (
+	RegistrationPending   = "pending"
+	RegistrationConfirmed = "confirmed"
+	RegistrationRejected  = "rejected"
+	RegistrationExpired   = "expired"
+)
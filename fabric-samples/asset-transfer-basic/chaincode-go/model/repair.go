@@ -0,0 +1,46 @@
+package model
+
+// RepairJob tracks the repair of damages on an asset from the owner's request until the
+// mechanic is paid. Mechanics answer a requested job with quotes and the owner picks one.
+type RepairJob struct {
+	ID         string   `json:"ID"`
+	AssetID    string   `json:"assetID"`
+	OwnerID    string   `json:"ownerID"`
+	DamageIDs  []string `json:"damageIDs"`
+	Status     string   `json:"status"`
+	QuoteID    string   `json:"quoteID"`    // set once the owner accepts a quote
+	MechanicID string   `json:"mechanicID"` // mechanic of the accepted quote
+	Price      int64    `json:"price"`      // price of the accepted quote, in cents
+	Salvage    bool     `json:"salvage"`    // restores a totaled asset
+	// SHA-256 hashes of off-chain repair reports, hex encoded
+	DocumentHashes []string `json:"documentHashes"`
+	// users who pay for the repair, the owner of the asset when empty
+	Liability []LiabilityShare `json:"liability"`
+	// warranty the repair is claimed on and the status of the claim, empty when not claimed
+	WarrantyID    string `json:"warrantyID"`
+	WarrantyClaim string `json:"warrantyClaim"`
+}
+
+// RepairQuote is a mechanic's price for doing the repair asked for in a repair job
+type RepairQuote struct {
+	ID         string `json:"ID"`
+	JobID      string `json:"jobID"`
+	MechanicID string `json:"mechanicID"`
+	Price      int64  `json:"price"` // in cents
+	Status     string `json:"status"`
+}
+
+// Repair job statuses, in the order a job moves through them
+const (
+	RepairRequested  = "Requested"
+	RepairAccepted   = "Accepted"
+	RepairInProgress = "InProgress"
+	RepairCompleted  = "Completed"
+	RepairPaid       = "Paid"
+)
+
+// Repair quote statuses
+const (
+	QuotePending  = "pending"
+	QuoteAccepted = "accepted"
+)
@@ -0,0 +1,17 @@
+package model
+
+// User roles
+const (
+	RoleOwner     = "owner"
+	RoleMechanic  = "mechanic"
+	RoleDealer    = "dealer"
+	RoleInsurer   = "insurer"
+	RoleAssessor  = "assessor"
+	RoleRegulator = "regulator"
+	RoleInspector = "inspector"
+	RoleDMV       = "dmv" // vehicle registry
+	RolePolice    = "police"
+	// certified station recording emissions tests
+	RoleEmissionsStation = "emissionsStation"
+	RoleManufacturer     = "manufacturer"
+)
@@ -0,0 +1,15 @@
+package model
+
+// TransferRecord documents a completed change of ownership
+type TransferRecord struct {
+	TxID           string `json:"txID"`
+	AssetID        string `json:"assetID"`
+	SellerID       string `json:"sellerID"`
+	BuyerID        string `json:"buyerID"`
+	Price          int64  `json:"price"`          // agreed price in cents
+	AppraisedValue int64  `json:"appraisedValue"` // public appraisal at the time of the sale, in cents
+	Tax            int64  `json:"tax"`            // transfer tax paid from the proceeds, in cents
+	DealerID       string `json:"dealerID"`       // dealer who brokered the sale, if any
+	Commission     int64  `json:"commission"`     // dealer commission paid from the proceeds, in cents
+	Mileage        int64  `json:"mileage"`        // odometer reading at the time of the sale, in kilometers
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// Seizure records a regulator taking an asset into custody and its release by court order
+type Seizure struct {
+	ID          string    `json:"ID"`
+	AssetID     string    `json:"assetID"`
+	OwnerID     string    `json:"ownerID"` // owner the asset was seized from
+	CustodianID string    `json:"custodianID"`
+	RegulatorID string    `json:"regulatorID"`
+	Reason      string    `json:"reason"`
+	SeizedAt    time.Time `json:"seizedAt"`
+	CourtOrder  string    `json:"courtOrder"` // reference of the order releasing the asset
+	ReleasedAt  time.Time `json:"releasedAt"`
+	Status      string    `json:"status"`
+}
+
+// Seizure statuses
+const (
+	SeizureActive   = "seized"
+	SeizureReleased = "released"
+)
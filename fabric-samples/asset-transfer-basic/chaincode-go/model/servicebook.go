@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// ServiceBookEntry is a public entry of the digital service book of an asset. Unlike the private
+// ServiceRecord of the owner, entries are written by the mechanic who did the work and stay with
+// the car when it is sold.
+type ServiceBookEntry struct {
+	ID            string    `json:"ID"`
+	AssetID       string    `json:"assetID"`
+	Date          time.Time `json:"date"`
+	Mileage       int64     `json:"mileage"` // in kilometers
+	WorkPerformed string    `json:"workPerformed"`
+	MechanicID    string    `json:"mechanicID"`
+}
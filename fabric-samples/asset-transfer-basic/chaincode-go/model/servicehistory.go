@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// ServiceRecord is a detailed service or repair entry kept private to the owner's organization.
+// It is stored in the implicit private data collection of the organization; the ledger holds a
+// ServiceRecordHash so shared copies can be verified.
+type ServiceRecord struct {
+	ID          string    `json:"ID"` // set from the ServiceRecordHash when read
+	AssetID     string    `json:"assetID"`
+	Date        time.Time `json:"date"`
+	Mileage     int64     `json:"mileage"` // in kilometers
+	Description string    `json:"description"`
+	MechanicID  string    `json:"mechanicID"`
+	Cost        int64     `json:"cost"` // in cents
+	// random value making the record hash impossible to guess from likely entries
+	Salt string `json:"salt"`
+}
+
+// ServiceRecordHash is the public trace of a private service record
+type ServiceRecordHash struct {
+	AssetID    string `json:"assetID"`
+	RecordID   string `json:"recordID"`
+	Collection string `json:"collection"` // private data collection the record was added to
+	Hash       string `json:"hash"`       // SHA-256 of the ServiceRecord JSON, hex encoded
+}
@@ -0,0 +1,8 @@
+package model
+
+// ShareHolding is the number of share tokens of a tokenized asset a user holds
+type ShareHolding struct {
+	AssetID string `json:"assetID"`
+	UserID  string `json:"userID"`
+	Shares  int64  `json:"shares"`
+}
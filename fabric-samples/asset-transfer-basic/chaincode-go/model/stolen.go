@@ -0,0 +1,9 @@
+package model
+
+// StolenEvent is emitted when an asset is reported stolen or recovered
+type StolenEvent struct {
+	AssetID    string `json:"assetID"`
+	VIN        string `json:"vin"`
+	Stolen     bool   `json:"stolen"`
+	ReporterID string `json:"reporterID"`
+}
@@ -0,0 +1,19 @@
+package model
+
+// Swap is a proposal to exchange two assets between their owners, optionally with a cash top-up
+type Swap struct {
+	ID                  string `json:"ID"`
+	ProposerID          string `json:"proposerID"`
+	ProposerAssetID     string `json:"proposerAssetID"`
+	CounterpartyID      string `json:"counterpartyID"`
+	CounterpartyAssetID string `json:"counterpartyAssetID"`
+	TopUp               int64  `json:"topUp"` // in cents paid by the proposer, negative when paid by the counterparty
+	Status              string `json:"status"`
+}
+
+// Swap statuses
+const (
+	SwapProposed  = "proposed"
+	SwapCompleted = "completed"
+	SwapCancelled = "cancelled"
+)
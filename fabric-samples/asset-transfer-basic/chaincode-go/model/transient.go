@@ -0,0 +1,15 @@
+package model
+
+// UserInput holds the details of a new user passed to CreateUserFromTransient
+type UserInput struct {
+	ID             string `json:"ID"`
+	Name           string `json:"name"`
+	Lastname       string `json:"lastname"`
+	Email          string `json:"email"`
+	InitialBalance int64  `json:"initialBalance"` // in cents
+}
+
+// OfferInput holds the price passed to OfferAssetFromTransient
+type OfferInput struct {
+	Price int64 `json:"price"` // in cents
+}
@@ -0,0 +1,9 @@
+package model
+
+// TreasuryReport summarizes the treasury account and the tax collected on sales
+type TreasuryReport struct {
+	Balance        int64 `json:"balance"`        // in cents
+	TaxRate        int64 `json:"taxRate"`        // in basis points
+	TotalCollected int64 `json:"totalCollected"` // in cents
+	TaxedSales     int   `json:"taxedSales"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Warranty is a promise by its issuer to pay for covered repairs of an asset until the warranty
+// expires by date or by mileage, whichever comes first. It stays with the car across sales.
+type Warranty struct {
+	ID         string    `json:"ID"`
+	AssetID    string    `json:"assetID"`
+	IssuerID   string    `json:"issuerID"` // manufacturer or dealer paying for covered repairs
+	Coverage   string    `json:"coverage"` // what the warranty covers
+	IssuedAt   time.Time `json:"issuedAt"`
+	ValidUntil time.Time `json:"validUntil"`
+	MaxMileage int64     `json:"maxMileage"` // in kilometers, 0 when the warranty does not expire by mileage
+}
+
+// Warranty claim statuses of a repair job
+const (
+	WarrantyClaimPending  = "pending"
+	WarrantyClaimApproved = "approved"
+	WarrantyClaimRejected = "rejected"
+)
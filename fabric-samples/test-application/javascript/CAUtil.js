@@ -50,7 +50,8 @@ exports.enrollAdmin = async (caClient, wallet, orgMspId) => {
 	}
 };
 
-exports.registerAndEnrollUser = async (caClient, wallet, orgMspId, userId, affiliation) => {
+// attrs are optional certificate attributes of the user, e.g. [{ name: 'admin', value: 'true', ecert: true }]
+exports.registerAndEnrollUser = async (caClient, wallet, orgMspId, userId, affiliation, attrs) => {
 	try {
 		// Check to see if we've already enrolled the user
 		const userIdentity = await wallet.get(userId);
@@ -76,7 +77,8 @@ exports.registerAndEnrollUser = async (caClient, wallet, orgMspId, userId, affil
 		const secret = await caClient.register({
 			affiliation: affiliation,
 			enrollmentID: userId,
-			role: 'client'
+			role: 'client',
+			attrs: attrs || []
 		}, adminUser);
 		const enrollment = await caClient.enroll({
 			enrollmentID: userId,